@@ -36,6 +36,8 @@ type Downloader struct {
 	stats     AggStats
 
 	folder storage.ClientImplCloser
+
+	webSeeds *WebSeeds
 }
 
 type AggStats struct {
@@ -64,6 +66,14 @@ func New(cfg *torrentcfg.Cfg) (*Downloader, error) {
 	if !common.FileExist(filepath.Join(cfg.DataDir, "db")) {
 		cfg.DataDir += "_tmp"
 	}
+	webSeeds, err := NewWebSeeds(cfg.WebSeeds)
+	if err != nil {
+		return nil, err
+	}
+	if len(webSeeds.Urls()) > 0 {
+		log.Info("[Snapshots] Using webseeds", "urls", webSeeds.Urls())
+	}
+
 	db, c, m, torrentClient, err := openClient(cfg.ClientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("openClient: %w", err)
@@ -87,6 +97,7 @@ func New(cfg *torrentcfg.Cfg) (*Downloader, error) {
 		folder:            m,
 		torrentClient:     torrentClient,
 		clientLock:        &sync.RWMutex{},
+		webSeeds:          webSeeds,
 
 		statsLock: &sync.RWMutex{},
 	}, nil
@@ -198,6 +209,9 @@ func (d *Downloader) Close() {
 		log.Warn("[Snapshots] pieceCompletionDB.close", "err", err)
 	}
 	d.db.Close()
+	if err := d.webSeeds.Close(); err != nil {
+		log.Warn("[Snapshots] webSeeds.close", "err", err)
+	}
 }
 
 func (d *Downloader) PeerID() []byte {
@@ -268,6 +282,7 @@ func MainLoop(ctx context.Context, d *Downloader, silent bool) {
 				if err := sem.Acquire(ctx, 1); err != nil {
 					return
 				}
+				d.webSeeds.AddTo(t)
 				t.AllowDataDownload()
 				t.DownloadAll()
 				go func(t *torrent.Torrent) {
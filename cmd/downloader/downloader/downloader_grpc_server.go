@@ -42,7 +42,7 @@ func (s *GrpcServer) Download(ctx context.Context, request *proto_downloader.Dow
 		}
 
 		magnet := mi.Magnet(&hash, nil)
-		go func(magnetUrl string) {
+		go func(magnetUrl, fileName string) {
 			t, err := torrentClient.AddMagnet(magnetUrl)
 			if err != nil {
 				log.Warn("[downloader] add magnet link", "err", err)
@@ -50,13 +50,14 @@ func (s *GrpcServer) Download(ctx context.Context, request *proto_downloader.Dow
 			}
 			t.DisallowDataDownload()
 			t.AllowDataUpload()
+			s.d.webSeeds.UseTorrentFilesOf(t, fileName)
 			<-t.GotInfo()
 			mi := t.Metainfo()
 			if err := CreateTorrentFileIfNotExists(s.d.SnapshotsDir(), t.Info(), &mi); err != nil {
 				log.Warn("[downloader] create torrent file", "err", err)
 				return
 			}
-		}(magnet.String())
+		}(magnet.String(), it.Path)
 
 	}
 	return &emptypb.Empty{}, nil
@@ -26,6 +26,8 @@ type Cfg struct {
 	//DB kv.RwDB
 	//CompletionCloser io.Closer
 	DownloadSlots int
+	// WebSeeds - urls of snapshots mirrors: http(s)://, file:// or s3://
+	WebSeeds []string
 }
 
 func Default() *torrent.ClientConfig {
@@ -45,7 +47,7 @@ func Default() *torrent.ClientConfig {
 	return torrentConfig
 }
 
-func New(snapshotsDir string, verbosity lg.Level, natif nat.Interface, downloadRate, uploadRate datasize.ByteSize, port, connsPerFile int, downloadSlots int) (*Cfg, error) {
+func New(snapshotsDir string, verbosity lg.Level, natif nat.Interface, downloadRate, uploadRate datasize.ByteSize, port, connsPerFile int, downloadSlots int, webSeeds []string) (*Cfg, error) {
 	torrentConfig := Default()
 	// We would-like to reduce amount of goroutines in Erigon, so reducing next params
 	torrentConfig.EstablishedConnsPerTorrent = connsPerFile // default: 50
@@ -98,5 +100,5 @@ func New(snapshotsDir string, verbosity lg.Level, natif nat.Interface, downloadR
 	torrentConfig.Logger = lg.Default.FilterLevel(verbosity)
 	torrentConfig.Logger.Handlers = []lg.Handler{adapterHandler{}}
 
-	return &Cfg{ClientConfig: torrentConfig, DownloadSlots: downloadSlots}, nil
+	return &Cfg{ClientConfig: torrentConfig, DownloadSlots: downloadSlots, WebSeeds: webSeeds}, nil
 }
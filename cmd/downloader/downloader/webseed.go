@@ -0,0 +1,147 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/anacrolix/torrent"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// WebSeeds - mirrors of snapshots dir, used as additional piece sources next to BitTorrent peers
+// (see BEP 19). Useful where BitTorrent ports are blocked. Supported mirror urls:
+//   - http(s)://host/path/ - any static http server
+//   - file:///path/to/dir - local directory (or network mount), served over loopback http
+//   - s3://bucket/prefix?endpoint=host:port - S3-compatible storage with public-read objects.
+//     Endpoint may have http:// or https:// prefix, default: s3.amazonaws.com over https
+//
+// Mirror must contain .seg files and may contain .torrent files - they used to get metadata
+// of torrents which no peer can provide.
+// Pieces downloaded from mirrors are checked against piece hashes from .torrent metainfo
+// by torrent client - exactly as pieces downloaded from peers.
+type WebSeeds struct {
+	urls   []string // all urls are http(s) and end with "/"
+	server *http.Server
+}
+
+func NewWebSeeds(mirrors []string) (*WebSeeds, error) {
+	ws := &WebSeeds{}
+	var localDirs []string
+	for _, mirror := range mirrors {
+		u, err := url.Parse(mirror)
+		if err != nil {
+			return nil, fmt.Errorf("webseed %q: %w", mirror, err)
+		}
+		switch u.Scheme {
+		case "http", "https":
+			ws.urls = append(ws.urls, withTrailingSlash(u.String()))
+		case "s3":
+			s3Url, err := s3WebSeedUrl(u)
+			if err != nil {
+				return nil, fmt.Errorf("webseed %q: %w", mirror, err)
+			}
+			ws.urls = append(ws.urls, s3Url)
+		case "file", "":
+			dir := u.Path
+			if fi, err := os.Stat(dir); err != nil {
+				return nil, fmt.Errorf("webseed %q: %w", mirror, err)
+			} else if !fi.IsDir() {
+				return nil, fmt.Errorf("webseed %q: not a directory", mirror)
+			}
+			localDirs = append(localDirs, dir)
+		default:
+			return nil, fmt.Errorf("webseed %q: unsupported scheme %q", mirror, u.Scheme)
+		}
+	}
+	if len(localDirs) > 0 {
+		if err := ws.serveLocalDirs(localDirs); err != nil {
+			return nil, err
+		}
+	}
+	return ws, nil
+}
+
+// serveLocalDirs - torrent client can download only over http, so expose local dirs on loopback interface
+func (ws *WebSeeds) serveLocalDirs(dirs []string) error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("webseed listen: %w", err)
+	}
+	mux := http.NewServeMux()
+	for i, dir := range dirs {
+		prefix := "/" + strconv.Itoa(i) + "/"
+		mux.Handle(prefix, http.StripPrefix(prefix, http.FileServer(http.Dir(dir))))
+		ws.urls = append(ws.urls, "http://"+ln.Addr().String()+prefix)
+	}
+	ws.server = &http.Server{Handler: mux}
+	go func() {
+		if err := ws.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Warn("[Snapshots] webseed local server", "err", err)
+		}
+	}()
+	return nil
+}
+
+// s3WebSeedUrl - converts s3://bucket/prefix?endpoint=host to path-style url: https://host/bucket/prefix/
+func s3WebSeedUrl(u *url.URL) (string, error) {
+	if u.Host == "" {
+		return "", fmt.Errorf("bucket name is empty")
+	}
+	endpoint := u.Query().Get("endpoint")
+	if endpoint == "" {
+		endpoint = "s3.amazonaws.com"
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+	return withTrailingSlash(strings.TrimSuffix(endpoint, "/") + "/" + u.Host + u.Path), nil
+}
+
+func withTrailingSlash(s string) string {
+	if strings.HasSuffix(s, "/") {
+		return s
+	}
+	return s + "/"
+}
+
+func (ws *WebSeeds) Urls() []string {
+	if ws == nil {
+		return nil
+	}
+	return ws.urls
+}
+
+// AddTo - adds mirrors as webseeds of torrent. Torrent client will request pieces from them
+// after it got torrent's metadata.
+func (ws *WebSeeds) AddTo(t *torrent.Torrent) {
+	if ws == nil || len(ws.urls) == 0 {
+		return
+	}
+	t.AddWebSeeds(ws.urls)
+}
+
+// UseTorrentFilesOf - download metadata of torrent from <mirror>/<fileName>.torrent,
+// torrent client checks that info hash of downloaded metadata is the same as requested.
+func (ws *WebSeeds) UseTorrentFilesOf(t *torrent.Torrent, fileName string) {
+	if ws == nil || len(ws.urls) == 0 {
+		return
+	}
+	sources := make([]string, len(ws.urls))
+	for i, u := range ws.urls {
+		sources[i] = u + url.PathEscape(fileName) + ".torrent"
+	}
+	t.UseSources(sources)
+}
+
+func (ws *WebSeeds) Close() error {
+	if ws == nil || ws.server == nil {
+		return nil
+	}
+	return ws.server.Close()
+}
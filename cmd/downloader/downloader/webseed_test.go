@@ -0,0 +1,59 @@
+package downloader
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3WebSeedUrl(t *testing.T) {
+	cases := map[string]string{
+		"s3://bucket/snapshots": "https://s3.amazonaws.com/bucket/snapshots/",
+		"s3://bucket":           "https://s3.amazonaws.com/bucket/",
+		"s3://bucket/mainnet/?endpoint=minio.local:9000":       "https://minio.local:9000/bucket/mainnet/",
+		"s3://bucket/mainnet?endpoint=http://127.0.0.1:9000/":  "http://127.0.0.1:9000/bucket/mainnet/",
+		"s3://bucket/a/b?endpoint=https://storage.example.com": "https://storage.example.com/bucket/a/b/",
+	}
+	for in, expect := range cases {
+		u, err := url.Parse(in)
+		require.NoError(t, err)
+		res, err := s3WebSeedUrl(u)
+		require.NoError(t, err)
+		require.Equal(t, expect, res, in)
+	}
+}
+
+func TestNewWebSeeds(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1-000000-000500-headers.seg"), []byte("0123456789"), 0644))
+	ws, err := NewWebSeeds([]string{"https://example.com/snapshots", "file://" + dir, "s3://bucket/snapshots"})
+	require.NoError(t, err)
+	defer ws.Close()
+	urls := ws.Urls()
+	require.Equal(t, 3, len(urls))
+	require.Equal(t, "https://example.com/snapshots/", urls[0])
+	require.Equal(t, "https://s3.amazonaws.com/bucket/snapshots/", urls[1])
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/0/$`, urls[2])
+
+	// local dir must be served with range requests support
+	req, err := http.NewRequest(http.MethodGet, urls[2]+"v1-000000-000500-headers.seg", nil)
+	require.NoError(t, err)
+	req.Header.Set("Range", "bytes=2-5")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "2345", string(body))
+
+	_, err = NewWebSeeds([]string{"ftp://example.com/snapshots"})
+	require.Error(t, err)
+	_, err = NewWebSeeds([]string{"file:///not/existing/dir"})
+	require.Error(t, err)
+}
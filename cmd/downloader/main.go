@@ -10,16 +10,23 @@ import (
 
 	"github.com/anacrolix/torrent/metainfo"
 	"github.com/c2h5oh/datasize"
+	"github.com/holiman/uint256"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/etl"
 	proto_downloader "github.com/ledgerwatch/erigon-lib/gointerfaces/downloader"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
 	"github.com/ledgerwatch/erigon/cmd/downloader/downloader"
 	"github.com/ledgerwatch/erigon/cmd/downloader/downloader/torrentcfg"
+	"github.com/ledgerwatch/erigon/cmd/hack/tool"
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/common/paths"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/internal/debug"
 	"github.com/ledgerwatch/erigon/p2p/nat"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
@@ -44,6 +51,8 @@ var (
 	torrentMaxPeers                int
 	torrentConnsPerFile            int
 	targetFile                     string
+	torrentWebSeeds                string
+	verifyRebuild                  bool
 )
 
 func init() {
@@ -61,6 +70,7 @@ func init() {
 	rootCmd.Flags().IntVar(&torrentMaxPeers, "torrent.maxpeers", utils.TorrentMaxPeersFlag.Value, utils.TorrentMaxPeersFlag.Usage)
 	rootCmd.Flags().IntVar(&torrentConnsPerFile, "torrent.conns.perfile", utils.TorrentConnsPerFileFlag.Value, utils.TorrentConnsPerFileFlag.Usage)
 	rootCmd.Flags().IntVar(&torrentDownloadSlots, "torrent.download.slots", utils.TorrentDownloadSlotsFlag.Value, utils.TorrentDownloadSlotsFlag.Usage)
+	rootCmd.Flags().StringVar(&torrentWebSeeds, utils.TorrentWebSeedsFlag.Name, utils.TorrentWebSeedsFlag.Value, utils.TorrentWebSeedsFlag.Usage)
 
 	withDataDir(printTorrentHashes)
 	printTorrentHashes.PersistentFlags().BoolVar(&forceRebuild, "rebuild", false, "Force re-create .torrent files")
@@ -71,6 +81,10 @@ func init() {
 	}
 
 	rootCmd.AddCommand(printTorrentHashes)
+
+	withDataDir(verifySnapshots)
+	verifySnapshots.Flags().BoolVar(&verifyRebuild, "rebuild", false, "Rebuild broken .idx files")
+	rootCmd.AddCommand(verifySnapshots)
 }
 
 func withDataDir(cmd *cobra.Command) {
@@ -132,7 +146,7 @@ func Downloader(ctx context.Context) error {
 		return fmt.Errorf("invalid nat option %s: %w", natSetting, err)
 	}
 
-	cfg, err := torrentcfg.New(snapshotDir, torrentLogLevel, natif, downloadRate, uploadRate, torrentPort, torrentConnsPerFile, torrentDownloadSlots)
+	cfg, err := torrentcfg.New(snapshotDir, torrentLogLevel, natif, downloadRate, uploadRate, torrentPort, torrentConnsPerFile, torrentDownloadSlots, utils.SplitAndTrim(torrentWebSeeds))
 	if err != nil {
 		return err
 	}
@@ -232,6 +246,61 @@ var printTorrentHashes = &cobra.Command{
 	},
 }
 
+var verifySnapshots = &cobra.Command{
+	Use:     "verify",
+	Short:   "Verify content of snapshots: headers chain, transactions roots and .idx files. For torrent piece hashes see: torrent_hashes --verify",
+	Example: "go run ./cmd/downloader verify --datadir <your_datadir> --rebuild",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshotDir := filepath.Join(datadir, "snapshots")
+		tmpDir := filepath.Join(datadir, etl.TmpDirName)
+		ctx := cmd.Context()
+
+		snapshots := snapshotsync.NewRoSnapshots(ethconfig.NewSnapshotCfg(true, true), snapshotDir)
+		defer snapshots.Close()
+		if err := snapshots.ReopenSegments(); err != nil {
+			return err
+		}
+		if err := snapshots.ReopenIndices(); err != nil { // not found and broken .idx files will be reported by verification
+			log.Warn("[Snapshots] open indices", "err", err)
+		}
+		problems, err := snapshotsync.VerifySnapshots(ctx, snapshots, log.LvlInfo)
+		if err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			log.Info("[Snapshots] Verify succeed")
+			return nil
+		}
+
+		var brokenSegments, brokenIndices int
+		for _, p := range problems {
+			if p.Index == "" {
+				brokenSegments++
+				log.Error("[Snapshots] Broken segment, remove it and start downloader to fetch it again", "file", p.File, "err", p.Err)
+			} else {
+				brokenIndices++
+				log.Error("[Snapshots] Broken index", "file", p.File, "err", p.Err)
+			}
+		}
+		if brokenIndices > 0 && verifyRebuild {
+			snapshots.Close()
+			chainDB := mdbx.NewMDBX(log.New()).Label(kv.ChainDB).Path(filepath.Join(datadir, "chaindata")).Readonly().MustOpen()
+			chainConfig := tool.ChainConfigFromDB(chainDB)
+			chainDB.Close()
+			chainID, _ := uint256.FromBig(chainConfig.ChainID)
+			if err := snapshotsync.RepairIndices(ctx, problems, *chainID, snapshotDir, tmpDir, log.LvlInfo); err != nil {
+				return err
+			}
+			log.Info("[Snapshots] Rebuilt broken indices", "amount", brokenIndices)
+			brokenIndices = 0
+		}
+		if brokenSegments > 0 || brokenIndices > 0 {
+			return fmt.Errorf("found %d broken segments and %d broken indices", brokenSegments, brokenIndices)
+		}
+		return nil
+	},
+}
+
 //nolint
 func removePieceCompletionStorage(snapshotDir string) {
 	_ = os.RemoveAll(filepath.Join(snapshotDir, "db"))
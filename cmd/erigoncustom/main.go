@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"encoding/binary"
 	"fmt"
 	"os"
 
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	erigonapp "github.com/ledgerwatch/erigon/turbo/app"
 	erigoncli "github.com/ledgerwatch/erigon/turbo/cli"
+	"github.com/ledgerwatch/erigon/turbo/node"
+	"github.com/ledgerwatch/log/v3"
 
 	"github.com/urfave/cli"
 )
@@ -21,6 +28,15 @@ const (
 	customBucketName = "ch.torquem.demo.tgcustom.CUSTOM_BUCKET" //nolint
 )
 
+// defining a custom stage name, it's recommended to prefix it with reverse domain to avoid clashes
+const customStage stages.SyncStage = "ch.torquem.demo.tgcustom.CUSTOM_STAGE"
+
+func init() {
+	// custom bucket has to be registered before any database is opened
+	kv.ChaindataTables = append(kv.ChaindataTables, customBucketName)
+	kv.ChaindataTablesCfg[customBucketName] = kv.TableCfgItem{}
+}
+
 // the regular main function
 func main() {
 	// initializing Erigon application here and providing our custom flag
@@ -34,17 +50,77 @@ func main() {
 }
 
 // Erigon main function
-func runErigon(ctx *cli.Context) {
-	// running a node and initializing a custom bucket with all default settings
-	//eri := node.New(ctx, node.Params{
-	//	CustomBuckets: map[string]dbutils.BucketConfigItem{
-	//		customBucketName: {},
-	//	},
-	//})
-
-	//err := eri.Serve()
-
-	//if err != nil {
-	//	log.Error("error while serving a Erigon node", "err", err)
-	//}
+func runErigon(cliCtx *cli.Context) {
+	logger := log.New()
+	greeting := cliCtx.String(flag.Name)
+
+	// adding a custom stage right after execution of blocks, before node is created
+	stagedsync.RegisterCustomStage(stages.Execution, customStage, func(ctx context.Context, db kv.RwDB, tmpdir string) *stagedsync.Stage {
+		return &stagedsync.Stage{
+			Description: "Write greeting for every executed block",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *stagedsync.StageState, u stagedsync.Unwinder, tx kv.RwTx) error {
+				return withTx(ctx, db, tx, func(tx kv.RwTx) error { return spawnCustomStage(s, tx, greeting) })
+			},
+			Unwind: func(firstCycle bool, u *stagedsync.UnwindState, s *stagedsync.StageState, tx kv.RwTx) error {
+				return withTx(ctx, db, tx, func(tx kv.RwTx) error { return unwindCustomStage(u, tx) })
+			},
+			Prune: func(firstCycle bool, p *stagedsync.PruneState, tx kv.RwTx) error { return nil },
+		}
+	})
+
+	// running a node with all default settings
+	nodeCfg := node.NewNodConfigUrfave(cliCtx)
+	ethCfg := node.NewEthConfigUrfave(cliCtx, nodeCfg)
+	ethNode, err := node.New(nodeCfg, ethCfg, logger)
+	if err != nil {
+		log.Error("Erigon startup", "err", err)
+		return
+	}
+	if err = ethNode.Serve(); err != nil {
+		log.Error("error while serving a Erigon node", "err", err)
+	}
+}
+
+// withTx - stages get nil tx if sync cycle doesn't run in one transaction
+func withTx(ctx context.Context, db kv.RwDB, tx kv.RwTx, f func(tx kv.RwTx) error) error {
+	if tx != nil {
+		return f(tx)
+	}
+	return db.Update(ctx, f)
+}
+
+func spawnCustomStage(s *stagedsync.StageState, tx kv.RwTx, greeting string) error {
+	executed, err := s.ExecutionAt(tx)
+	if err != nil {
+		return err
+	}
+	for blockNum := s.BlockNumber + 1; blockNum <= executed; blockNum++ {
+		if err = tx.Put(customBucketName, encodeBlockNumber(blockNum), []byte(greeting)); err != nil {
+			return err
+		}
+	}
+	return s.Update(tx, executed)
+}
+
+func unwindCustomStage(u *stagedsync.UnwindState, tx kv.RwTx) error {
+	c, err := tx.RwCursor(customBucketName)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(encodeBlockNumber(u.UnwindPoint + 1)); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if err = c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return u.Done(tx)
+}
+
+func encodeBlockNumber(blockNum uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, blockNum)
+	return k
 }
@@ -9,8 +9,10 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/eth/appearances"
 	"github.com/ledgerwatch/erigon/eth/stagedsync"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracestore"
 	"github.com/ledgerwatch/erigon/ethdb/prune"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/spf13/cobra"
@@ -62,6 +64,12 @@ func resetState(db kv.RwDB, logger log.Logger, ctx context.Context) error {
 	if err := db.Update(ctx, resetCallTraces); err != nil {
 		return err
 	}
+	if err := db.Update(ctx, resetTraceIndex); err != nil {
+		return err
+	}
+	if err := db.Update(ctx, resetAddressAppearances); err != nil {
+		return err
+	}
 	if err := db.Update(ctx, resetTxLookup); err != nil {
 		return err
 	}
@@ -209,6 +217,35 @@ func resetCallTraces(tx kv.RwTx) error {
 	return nil
 }
 
+func resetTraceIndex(tx kv.RwTx) error {
+	if err := tx.ClearBucket(tracestore.TxTraces); err != nil {
+		return err
+	}
+	if err := stages.SaveStageProgress(tx, stages.TraceIndex, 0); err != nil {
+		return err
+	}
+	if err := stages.SaveStagePruneProgress(tx, stages.TraceIndex, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resetAddressAppearances(tx kv.RwTx) error {
+	if err := tx.ClearBucket(appearances.AddressAppearances); err != nil {
+		return err
+	}
+	if err := tx.ClearBucket(appearances.TxAppearances); err != nil {
+		return err
+	}
+	if err := stages.SaveStageProgress(tx, stages.AddressAppearances, 0); err != nil {
+		return err
+	}
+	if err := stages.SaveStagePruneProgress(tx, stages.AddressAppearances, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
 func resetTxLookup(tx kv.RwTx) error {
 	if err := tx.ClearBucket(kv.TxLookup); err != nil {
 		return err
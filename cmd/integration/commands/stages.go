@@ -195,6 +195,40 @@ var cmdCallTraces = &cobra.Command{
 	},
 }
 
+var cmdTraceIndex = &cobra.Command{
+	Use:   "stage_trace_index",
+	Short: "",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, _ := common2.RootContext()
+		logger := log.New()
+		db := openDB(chaindata, logger, true)
+		defer db.Close()
+
+		if err := stageTraceIndex(db, ctx); err != nil {
+			log.Error("Error", "err", err)
+			return err
+		}
+		return nil
+	},
+}
+
+var cmdAddressAppearances = &cobra.Command{
+	Use:   "stage_address_appearances",
+	Short: "",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, _ := common2.RootContext()
+		logger := log.New()
+		db := openDB(chaindata, logger, true)
+		defer db.Close()
+
+		if err := stageAddressAppearances(db, ctx); err != nil {
+			log.Error("Error", "err", err)
+			return err
+		}
+		return nil
+	},
+}
+
 var cmdStageTxLookup = &cobra.Command{
 	Use:   "stage_tx_lookup",
 	Short: "",
@@ -399,6 +433,26 @@ func init() {
 
 	rootCmd.AddCommand(cmdCallTraces)
 
+	withDataDir(cmdTraceIndex)
+	withReset(cmdTraceIndex)
+	withBlock(cmdTraceIndex)
+	withUnwind(cmdTraceIndex)
+	withPruneTo(cmdTraceIndex)
+	withChain(cmdTraceIndex)
+	withHeimdall(cmdTraceIndex)
+
+	rootCmd.AddCommand(cmdTraceIndex)
+
+	withDataDir(cmdAddressAppearances)
+	withReset(cmdAddressAppearances)
+	withBlock(cmdAddressAppearances)
+	withUnwind(cmdAddressAppearances)
+	withPruneTo(cmdAddressAppearances)
+	withChain(cmdAddressAppearances)
+	withHeimdall(cmdAddressAppearances)
+
+	rootCmd.AddCommand(cmdAddressAppearances)
+
 	withReset(cmdStageTxLookup)
 	withBlock(cmdStageTxLookup)
 	withUnwind(cmdStageTxLookup)
@@ -807,7 +861,7 @@ func stageHashState(db kv.RwDB, ctx context.Context) error {
 func stageLogIndex(db kv.RwDB, ctx context.Context) error {
 	tmpdir := filepath.Join(datadir, etl.TmpDirName)
 
-	pm, _, _, _, sync, _, _ := newSync(ctx, db, nil)
+	pm, _, chainConfig, _, sync, _, _ := newSync(ctx, db, nil)
 	must(sync.SetCurrentStage(stages.LogIndex))
 	tx, err := db.BeginRw(ctx)
 	if err != nil {
@@ -835,7 +889,7 @@ func stageLogIndex(db kv.RwDB, ctx context.Context) error {
 	log.Info("Stage exec", "progress", execAt)
 	log.Info("Stage", "name", s.ID, "progress", s.BlockNumber)
 
-	cfg := stagedsync.StageLogIndexCfg(db, pm, tmpdir)
+	cfg := stagedsync.StageLogIndexCfg(db, pm, tmpdir, allSnapshots(chainConfig), getBlockReader(chainConfig))
 	if unwind > 0 {
 		u := sync.NewUnwindState(stages.LogIndex, s.BlockNumber-unwind, s.BlockNumber)
 		err = stagedsync.UnwindLogIndex(u, s, tx, cfg, ctx)
@@ -920,6 +974,108 @@ func stageCallTraces(kv kv.RwDB, ctx context.Context) error {
 	return tx.Commit()
 }
 
+// replayStage - functions of a stage re-executing blocks on top of historical state
+type replayStage struct {
+	reset  func(tx kv.RwTx) error
+	spawn  func(s *stagedsync.StageState, tx kv.RwTx) error
+	unwind func(u *stagedsync.UnwindState, s *stagedsync.StageState, tx kv.RwTx) error
+	prune  func(p *stagedsync.PruneState, tx kv.RwTx) error
+}
+
+func stageTraceIndex(db kv.RwDB, ctx context.Context) error {
+	return runReplayStage(db, ctx, stages.TraceIndex, func(pm prune.Mode, chainConfig *params.ChainConfig, engine consensus.Engine) replayStage {
+		cfg := stagedsync.StageTraceIndexCfg(db, pm, chainConfig, engine, getBlockReader(chainConfig))
+		return replayStage{
+			reset: resetTraceIndex,
+			spawn: func(s *stagedsync.StageState, tx kv.RwTx) error {
+				return stagedsync.SpawnTraceIndex(s, tx, cfg, ctx)
+			},
+			unwind: func(u *stagedsync.UnwindState, s *stagedsync.StageState, tx kv.RwTx) error {
+				return stagedsync.UnwindTraceIndex(u, s, tx, cfg, ctx)
+			},
+			prune: func(p *stagedsync.PruneState, tx kv.RwTx) error {
+				return stagedsync.PruneTraceIndex(p, tx, cfg, ctx)
+			},
+		}
+	})
+}
+
+func stageAddressAppearances(db kv.RwDB, ctx context.Context) error {
+	return runReplayStage(db, ctx, stages.AddressAppearances, func(pm prune.Mode, chainConfig *params.ChainConfig, engine consensus.Engine) replayStage {
+		cfg := stagedsync.StageAddressAppearancesCfg(db, pm, chainConfig, engine, getBlockReader(chainConfig))
+		return replayStage{
+			reset: resetAddressAppearances,
+			spawn: func(s *stagedsync.StageState, tx kv.RwTx) error {
+				return stagedsync.SpawnAddressAppearances(s, tx, cfg, ctx)
+			},
+			unwind: func(u *stagedsync.UnwindState, s *stagedsync.StageState, tx kv.RwTx) error {
+				return stagedsync.UnwindAddressAppearances(u, s, tx, cfg, ctx)
+			},
+			prune: func(p *stagedsync.PruneState, tx kv.RwTx) error {
+				return stagedsync.PruneAddressAppearances(p, tx, cfg, ctx)
+			},
+		}
+	})
+}
+
+// runReplayStage resets, unwinds, prunes or spawns (by flags) the stage re-executing blocks
+func runReplayStage(db kv.RwDB, ctx context.Context, stageID stages.SyncStage, newStage func(pm prune.Mode, chainConfig *params.ChainConfig, engine consensus.Engine) replayStage) error {
+	pm, engine, chainConfig, _, sync, _, _ := newSync(ctx, db, nil)
+	must(sync.SetCurrentStage(stageID))
+
+	tx, err := db.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	execStage := progress(tx, stages.Execution)
+	s := stage(sync, tx, nil, stageID)
+	if pruneTo > 0 {
+		pm.History = prune.Distance(s.BlockNumber - pruneTo)
+		pm.Receipts = prune.Distance(s.BlockNumber - pruneTo)
+		pm.CallTraces = prune.Distance(s.BlockNumber - pruneTo)
+		pm.TxIndex = prune.Distance(s.BlockNumber - pruneTo)
+	}
+	replay := newStage(pm, chainConfig, engine)
+
+	if reset {
+		if err = replay.reset(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	log.Info("ID exec", "progress", execStage)
+	if block != 0 {
+		s.BlockNumber = block
+		log.Info("Overriding initial state", "block", block)
+	}
+	log.Info("ID "+string(stageID), "progress", s.BlockNumber)
+
+	if unwind > 0 {
+		u := sync.NewUnwindState(stageID, s.BlockNumber-unwind, s.BlockNumber)
+		err = replay.unwind(u, s, tx)
+		if err != nil {
+			return err
+		}
+	} else if pruneTo > 0 {
+		p, err := sync.PruneStageState(stageID, s.BlockNumber, tx, nil)
+		if err != nil {
+			return err
+		}
+		err = replay.prune(p, tx)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := replay.spawn(s, tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func stageHistory(db kv.RwDB, ctx context.Context) error {
 	tmpdir := filepath.Join(datadir, etl.TmpDirName)
 	pm, _, _, _, sync, _, _ := newSync(ctx, db, nil)
@@ -25,6 +25,24 @@ type HandshakeError struct {
 	Time       time.Time
 }
 
+type NodeInfo struct {
+	ID               NodeID
+	Addr             NodeAddr
+	AddrUpdated      time.Time
+	PingTries        uint
+	IsCompatFork     *bool
+	ClientID         *string
+	NetworkID        *uint
+	EthVersion       *uint
+	HandshakeUpdated *time.Time
+}
+
+type ENREntry struct {
+	Key   string
+	Value string // decoded value
+	Raw   string // hex of RLP value
+}
+
 type DB interface {
 	io.Closer
 
@@ -52,6 +70,16 @@ type DB interface {
 
 	UpdateForkCompatibility(ctx context.Context, id NodeID, isCompatFork bool) error
 
+	// UpdateENR replaces the stored ENR and its entries unless the stored one has a higher seq.
+	UpdateENR(ctx context.Context, id NodeID, seq uint64, enr string, entries []ENREntry) error
+
+	// FindCountryCandidates finds nodes which IP wasn't looked up in GeoIP country DB yet.
+	FindCountryCandidates(ctx context.Context, limit uint) (map[NodeID]net.IP, error)
+	UpdateCountry(ctx context.Context, id NodeID, ip net.IP, country string) error
+	// FindASNCandidates finds nodes which IP wasn't looked up in GeoIP ASN DB yet.
+	FindASNCandidates(ctx context.Context, limit uint) (map[NodeID]net.IP, error)
+	UpdateASN(ctx context.Context, id NodeID, ip net.IP, asn uint, asOrg string) error
+
 	UpdateNeighborBucketKeys(ctx context.Context, id NodeID, keys []string) error
 	FindNeighborBucketKeys(ctx context.Context, id NodeID) ([]string, error)
 
@@ -70,4 +98,11 @@ type DB interface {
 	CountClientsWithNetworkID(ctx context.Context, clientIDPrefix string, maxPingTries uint) (uint, error)
 	CountClientsWithHandshakeTransientError(ctx context.Context, clientIDPrefix string, maxPingTries uint) (uint, error)
 	EnumerateClientIDs(ctx context.Context, maxPingTries uint, networkID uint, enumFunc func(clientID *string)) error
+	// EnumerateNodes enumerates nodes seen or handshaken between since and until (zero time means no limit).
+	EnumerateNodes(ctx context.Context, since time.Time, until time.Time, enumFunc func(node NodeInfo)) error
+	EnumerateHandshakeErrors(ctx context.Context, since time.Time, until time.Time, enumFunc func(id NodeID, handshakeErr HandshakeError)) error
+	EnumerateCountryCounts(ctx context.Context, maxPingTries uint, networkID uint, enumFunc func(country *string, count uint)) error
+	EnumerateASNCounts(ctx context.Context, maxPingTries uint, networkID uint, enumFunc func(asn *uint, asOrg string, count uint)) error
+	CountENRs(ctx context.Context, maxPingTries uint) (uint, error)
+	EnumerateENREntryCounts(ctx context.Context, maxPingTries uint, enumFunc func(key string, value string, count uint)) error
 }
@@ -185,6 +185,13 @@ func (db DBRetrier) UpdateForkCompatibility(ctx context.Context, id NodeID, isCo
 	return err
 }
 
+func (db DBRetrier) UpdateENR(ctx context.Context, id NodeID, seq uint64, enr string, entries []ENREntry) error {
+	_, err := db.retry(ctx, "UpdateENR", func(ctx context.Context) (interface{}, error) {
+		return nil, db.db.UpdateENR(ctx, id, seq, enr, entries)
+	})
+	return err
+}
+
 func (db DBRetrier) UpdateNeighborBucketKeys(ctx context.Context, id NodeID, keys []string) error {
 	_, err := db.retry(ctx, "UpdateNeighborBucketKeys", func(ctx context.Context) (interface{}, error) {
 		return nil, db.db.UpdateNeighborBucketKeys(ctx, id, keys)
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	_ "modernc.org/sqlite"
 	"net"
 	"strings"
@@ -45,7 +46,13 @@ CREATE TABLE IF NOT EXISTS nodes (
     
     neighbor_keys TEXT,
     
-    crawl_retry_time INTEGER
+    crawl_retry_time INTEGER,
+
+    country TEXT,
+    country_ip TEXT,
+    asn INTEGER,
+    as_org TEXT,
+    asn_ip TEXT
 );
 
 CREATE TABLE IF NOT EXISTS handshake_errors (
@@ -54,6 +61,21 @@ CREATE TABLE IF NOT EXISTS handshake_errors (
     updated INTEGER NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS node_enrs (
+    id TEXT PRIMARY KEY,
+    seq INTEGER NOT NULL,
+    enr TEXT NOT NULL,
+    updated INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS node_enr_entries (
+    id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    raw TEXT NOT NULL,
+    PRIMARY KEY (id, key)
+);
+
 CREATE INDEX IF NOT EXISTS idx_nodes_crawl_retry_time ON nodes (crawl_retry_time);
 CREATE INDEX IF NOT EXISTS idx_nodes_ip ON nodes (ip);
 CREATE INDEX IF NOT EXISTS idx_nodes_ip_v6 ON nodes (ip_v6);
@@ -62,6 +84,16 @@ CREATE INDEX IF NOT EXISTS idx_nodes_compat_fork ON nodes (compat_fork);
 CREATE INDEX IF NOT EXISTS idx_nodes_network_id ON nodes (network_id);
 CREATE INDEX IF NOT EXISTS idx_nodes_handshake_retry_time ON nodes (handshake_retry_time);
 CREATE INDEX IF NOT EXISTS idx_handshake_errors_id ON handshake_errors (id);
+CREATE INDEX IF NOT EXISTS idx_node_enr_entries_key ON node_enr_entries (key);
+`
+
+	// columns added to the nodes table after its creation
+	sqlMigrateSchemaAddColumns = `
+ALTER TABLE nodes ADD COLUMN country TEXT;
+ALTER TABLE nodes ADD COLUMN country_ip TEXT;
+ALTER TABLE nodes ADD COLUMN asn INTEGER;
+ALTER TABLE nodes ADD COLUMN as_org TEXT;
+ALTER TABLE nodes ADD COLUMN asn_ip TEXT;
 `
 
 	sqlUpsertNodeAddr = `
@@ -184,6 +216,36 @@ UPDATE nodes SET handshake_retry_time = ? WHERE id IN (123)
 
 	sqlUpdateForkCompatibility = `
 UPDATE nodes SET compat_fork = ?, compat_fork_updated = ? WHERE id = ?
+`
+
+	sqlFindENRSeq = `
+SELECT seq FROM node_enrs WHERE id = ?
+`
+
+	sqlUpsertENR = `
+INSERT INTO node_enrs(
+	id,
+	seq,
+	enr,
+	updated
+) VALUES (?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    seq = excluded.seq,
+    enr = excluded.enr,
+    updated = excluded.updated
+`
+
+	sqlDeleteENREntries = `
+DELETE FROM node_enr_entries WHERE id = ?
+`
+
+	sqlInsertENREntry = `
+INSERT INTO node_enr_entries(
+	id,
+	key,
+	value,
+	raw
+) VALUES (?, ?, ?, ?)
 `
 
 	sqlUpdateNeighborBucketKeys = `
@@ -260,6 +322,81 @@ SELECT client_id FROM nodes
 WHERE (ping_try < ?)
     AND ((network_id = ?) OR (network_id IS NULL))
     AND ((compat_fork == TRUE) OR (compat_fork IS NULL))
+`
+
+	sqlEnumerateNodes = `
+SELECT
+    id,
+    ip,
+    port_disc,
+    port_rlpx,
+    ip_v6,
+    ip_v6_port_disc,
+    ip_v6_port_rlpx,
+    addr_updated,
+    ping_try,
+    compat_fork,
+    client_id,
+    network_id,
+    eth_version,
+    handshake_updated
+FROM nodes
+WHERE ((addr_updated BETWEEN ? AND ?) OR (handshake_updated BETWEEN ? AND ?))
+`
+
+	sqlEnumerateHandshakeErrors = `
+SELECT id, err, updated FROM handshake_errors
+WHERE (updated BETWEEN ? AND ?)
+ORDER BY updated
+`
+
+	sqlFindCountryCandidates = `
+SELECT id, ip FROM nodes
+WHERE (ip IS NOT NULL) AND ((country_ip IS NULL) OR (country_ip != ip))
+LIMIT ?
+`
+
+	sqlUpdateCountry = `
+UPDATE nodes SET country = ?, country_ip = ? WHERE id = ?
+`
+
+	sqlFindASNCandidates = `
+SELECT id, ip FROM nodes
+WHERE (ip IS NOT NULL) AND ((asn_ip IS NULL) OR (asn_ip != ip))
+LIMIT ?
+`
+
+	sqlUpdateASN = `
+UPDATE nodes SET asn = ?, as_org = ?, asn_ip = ? WHERE id = ?
+`
+
+	sqlEnumerateCountryCounts = `
+SELECT country, COUNT(*) FROM nodes
+WHERE (ping_try < ?)
+    AND ((network_id = ?) OR (network_id IS NULL))
+    AND ((compat_fork == TRUE) OR (compat_fork IS NULL))
+GROUP BY country
+`
+
+	sqlEnumerateASNCounts = `
+SELECT asn, MAX(as_org), COUNT(*) FROM nodes
+WHERE (ping_try < ?)
+    AND ((network_id = ?) OR (network_id IS NULL))
+    AND ((compat_fork == TRUE) OR (compat_fork IS NULL))
+GROUP BY asn
+`
+
+	sqlCountENRs = `
+SELECT COUNT(*) FROM node_enrs
+JOIN nodes ON nodes.id = node_enrs.id
+WHERE (nodes.ping_try < ?)
+`
+
+	sqlEnumerateENREntryCounts = `
+SELECT key, value, COUNT(*) FROM node_enr_entries
+JOIN nodes ON nodes.id = node_enr_entries.id
+WHERE (nodes.ping_try < ?)
+GROUP BY key, value
 `
 )
 
@@ -274,6 +411,13 @@ func NewDBSQLite(filePath string) (*DBSQLite, error) {
 		return nil, fmt.Errorf("failed to create the DB schema: %w", err)
 	}
 
+	for _, statement := range strings.Split(strings.TrimSpace(sqlMigrateSchemaAddColumns), "\n") {
+		_, err = db.Exec(statement)
+		if (err != nil) && !strings.Contains(err.Error(), "duplicate column name") {
+			return nil, fmt.Errorf("failed to migrate the DB schema: %w", err)
+		}
+	}
+
 	instance := DBSQLite{db}
 	return &instance, nil
 }
@@ -356,19 +500,34 @@ func (db *DBSQLite) FindNodeAddr(ctx context.Context, id NodeID) (*NodeAddr, err
 		return nil, fmt.Errorf("FindNodeAddr failed: %w", err)
 	}
 
+	addr, err := parseNodeAddr(ip, portDisc, portRLPx, ipV6, ipV6PortDisc, ipV6PortRLPx)
+	if err != nil {
+		return nil, fmt.Errorf("FindNodeAddr %w", err)
+	}
+	return addr, nil
+}
+
+func parseNodeAddr(
+	ip sql.NullString,
+	portDisc sql.NullInt32,
+	portRLPx sql.NullInt32,
+	ipV6 sql.NullString,
+	ipV6PortDisc sql.NullInt32,
+	ipV6PortRLPx sql.NullInt32,
+) (*NodeAddr, error) {
 	var addr NodeAddr
 
 	if ip.Valid {
 		value := net.ParseIP(ip.String)
 		if value == nil {
-			return nil, errors.New("FindNodeAddr failed to parse IP")
+			return nil, errors.New("failed to parse IP")
 		}
 		addr.IP = value
 	}
 	if ipV6.Valid {
 		value := net.ParseIP(ipV6.String)
 		if value == nil {
-			return nil, errors.New("FindNodeAddr failed to parse IPv6")
+			return nil, errors.New("failed to parse IPv6")
 		}
 		addr.IPv6.IP = value
 	}
@@ -645,6 +804,44 @@ func (db *DBSQLite) UpdateForkCompatibility(ctx context.Context, id NodeID, isCo
 	return nil
 }
 
+func (db *DBSQLite) UpdateENR(ctx context.Context, id NodeID, seq uint64, enr string, entries []ENREntry) error {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("UpdateENR failed to start a transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var prevSeq uint64
+	err = tx.QueryRowContext(ctx, sqlFindENRSeq, id).Scan(&prevSeq)
+	if err == nil {
+		if prevSeq > seq {
+			return nil
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("UpdateENR failed to get seq: %w", err)
+	}
+
+	updated := time.Now().Unix()
+	if _, err = tx.ExecContext(ctx, sqlUpsertENR, id, seq, enr, updated); err != nil {
+		return fmt.Errorf("UpdateENR failed to upsert: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, sqlDeleteENREntries, id); err != nil {
+		return fmt.Errorf("UpdateENR failed to delete entries: %w", err)
+	}
+	for _, entry := range entries {
+		if _, err = tx.ExecContext(ctx, sqlInsertENREntry, id, entry.Key, entry.Value, entry.Raw); err != nil {
+			return fmt.Errorf("UpdateENR failed to insert entry: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("UpdateENR failed to commit: %w", err)
+	}
+	return nil
+}
+
 func (db *DBSQLite) UpdateNeighborBucketKeys(ctx context.Context, id NodeID, keys []string) error {
 	keysStr := strings.Join(keys, ",")
 
@@ -857,6 +1054,317 @@ func (db *DBSQLite) EnumerateClientIDs(
 	return nil
 }
 
+func (db *DBSQLite) EnumerateNodes(
+	ctx context.Context,
+	since time.Time,
+	until time.Time,
+	enumFunc func(node NodeInfo),
+) error {
+	sinceTimestamp, untilTimestamp := timeRangeToUnix(since, until)
+	cursor, err := db.db.QueryContext(
+		ctx,
+		sqlEnumerateNodes,
+		sinceTimestamp,
+		untilTimestamp,
+		sinceTimestamp,
+		untilTimestamp)
+	if err != nil {
+		return fmt.Errorf("EnumerateNodes failed to query: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.Next() {
+		var id NodeID
+		var ip sql.NullString
+		var portDisc sql.NullInt32
+		var portRLPx sql.NullInt32
+		var ipV6 sql.NullString
+		var ipV6PortDisc sql.NullInt32
+		var ipV6PortRLPx sql.NullInt32
+		var addrUpdatedTimestamp int64
+		var pingTries uint
+		var isCompatFork sql.NullBool
+		var clientID sql.NullString
+		var networkID sql.NullInt64
+		var ethVersion sql.NullInt32
+		var handshakeUpdatedTimestamp sql.NullInt64
+
+		err := cursor.Scan(
+			&id,
+			&ip,
+			&portDisc,
+			&portRLPx,
+			&ipV6,
+			&ipV6PortDisc,
+			&ipV6PortRLPx,
+			&addrUpdatedTimestamp,
+			&pingTries,
+			&isCompatFork,
+			&clientID,
+			&networkID,
+			&ethVersion,
+			&handshakeUpdatedTimestamp)
+		if err != nil {
+			return fmt.Errorf("EnumerateNodes failed to read data: %w", err)
+		}
+
+		addr, err := parseNodeAddr(ip, portDisc, portRLPx, ipV6, ipV6PortDisc, ipV6PortRLPx)
+		if err != nil {
+			return fmt.Errorf("EnumerateNodes %w", err)
+		}
+
+		node := NodeInfo{
+			ID:          id,
+			Addr:        *addr,
+			AddrUpdated: time.Unix(addrUpdatedTimestamp, 0),
+			PingTries:   pingTries,
+		}
+		if isCompatFork.Valid {
+			node.IsCompatFork = &isCompatFork.Bool
+		}
+		if clientID.Valid {
+			node.ClientID = &clientID.String
+		}
+		if networkID.Valid {
+			value := uint(networkID.Int64)
+			node.NetworkID = &value
+		}
+		if ethVersion.Valid {
+			value := uint(ethVersion.Int32)
+			node.EthVersion = &value
+		}
+		if handshakeUpdatedTimestamp.Valid {
+			value := time.Unix(handshakeUpdatedTimestamp.Int64, 0)
+			node.HandshakeUpdated = &value
+		}
+		enumFunc(node)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("EnumerateNodes failed to iterate: %w", err)
+	}
+	return nil
+}
+
+func (db *DBSQLite) EnumerateHandshakeErrors(
+	ctx context.Context,
+	since time.Time,
+	until time.Time,
+	enumFunc func(id NodeID, handshakeErr HandshakeError),
+) error {
+	sinceTimestamp, untilTimestamp := timeRangeToUnix(since, until)
+	cursor, err := db.db.QueryContext(ctx, sqlEnumerateHandshakeErrors, sinceTimestamp, untilTimestamp)
+	if err != nil {
+		return fmt.Errorf("EnumerateHandshakeErrors failed to query: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.Next() {
+		var id NodeID
+		var stringCode string
+		var updatedTimestamp int64
+		err := cursor.Scan(&id, &stringCode, &updatedTimestamp)
+		if err != nil {
+			return fmt.Errorf("EnumerateHandshakeErrors failed to read data: %w", err)
+		}
+		enumFunc(id, HandshakeError{stringCode, time.Unix(updatedTimestamp, 0)})
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("EnumerateHandshakeErrors failed to iterate: %w", err)
+	}
+	return nil
+}
+
+func timeRangeToUnix(since time.Time, until time.Time) (int64, int64) {
+	var sinceTimestamp int64
+	if !since.IsZero() {
+		sinceTimestamp = since.Unix()
+	}
+	var untilTimestamp int64 = math.MaxInt64
+	if !until.IsZero() {
+		untilTimestamp = until.Unix()
+	}
+	return sinceTimestamp, untilTimestamp
+}
+
+func (db *DBSQLite) FindCountryCandidates(ctx context.Context, limit uint) (map[NodeID]net.IP, error) {
+	return db.findGeoIPCandidates(ctx, "FindCountryCandidates", sqlFindCountryCandidates, limit)
+}
+
+func (db *DBSQLite) UpdateCountry(ctx context.Context, id NodeID, ip net.IP, country string) error {
+	_, err := db.db.ExecContext(ctx, sqlUpdateCountry, nullIfEmpty(country), ip.String(), id)
+	if err != nil {
+		return fmt.Errorf("UpdateCountry failed: %w", err)
+	}
+	return nil
+}
+
+func (db *DBSQLite) FindASNCandidates(ctx context.Context, limit uint) (map[NodeID]net.IP, error) {
+	return db.findGeoIPCandidates(ctx, "FindASNCandidates", sqlFindASNCandidates, limit)
+}
+
+func (db *DBSQLite) UpdateASN(ctx context.Context, id NodeID, ip net.IP, asn uint, asOrg string) error {
+	var asnValue interface{}
+	if asn != 0 {
+		asnValue = asn
+	}
+	_, err := db.db.ExecContext(ctx, sqlUpdateASN, asnValue, nullIfEmpty(asOrg), ip.String(), id)
+	if err != nil {
+		return fmt.Errorf("UpdateASN failed: %w", err)
+	}
+	return nil
+}
+
+func (db *DBSQLite) findGeoIPCandidates(ctx context.Context, name string, query string, limit uint) (map[NodeID]net.IP, error) {
+	cursor, err := db.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to query: %w", name, err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	candidates := make(map[NodeID]net.IP)
+	for cursor.Next() {
+		var id NodeID
+		var ipStr string
+		err := cursor.Scan(&id, &ipStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s failed to read data: %w", name, err)
+		}
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("%s failed to parse IP", name)
+		}
+		candidates[id] = ip
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("%s failed to iterate: %w", name, err)
+	}
+	return candidates, nil
+}
+
+func (db *DBSQLite) EnumerateCountryCounts(
+	ctx context.Context,
+	maxPingTries uint,
+	networkID uint,
+	enumFunc func(country *string, count uint),
+) error {
+	cursor, err := db.db.QueryContext(ctx, sqlEnumerateCountryCounts, maxPingTries, networkID)
+	if err != nil {
+		return fmt.Errorf("EnumerateCountryCounts failed to query: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.Next() {
+		var country sql.NullString
+		var count uint
+		err := cursor.Scan(&country, &count)
+		if err != nil {
+			return fmt.Errorf("EnumerateCountryCounts failed to read data: %w", err)
+		}
+		if country.Valid {
+			enumFunc(&country.String, count)
+		} else {
+			enumFunc(nil, count)
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("EnumerateCountryCounts failed to iterate: %w", err)
+	}
+	return nil
+}
+
+func (db *DBSQLite) EnumerateASNCounts(
+	ctx context.Context,
+	maxPingTries uint,
+	networkID uint,
+	enumFunc func(asn *uint, asOrg string, count uint),
+) error {
+	cursor, err := db.db.QueryContext(ctx, sqlEnumerateASNCounts, maxPingTries, networkID)
+	if err != nil {
+		return fmt.Errorf("EnumerateASNCounts failed to query: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.Next() {
+		var asn sql.NullInt64
+		var asOrg sql.NullString
+		var count uint
+		err := cursor.Scan(&asn, &asOrg, &count)
+		if err != nil {
+			return fmt.Errorf("EnumerateASNCounts failed to read data: %w", err)
+		}
+		if asn.Valid {
+			value := uint(asn.Int64)
+			enumFunc(&value, asOrg.String, count)
+		} else {
+			enumFunc(nil, "", count)
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("EnumerateASNCounts failed to iterate: %w", err)
+	}
+	return nil
+}
+
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (db *DBSQLite) CountENRs(ctx context.Context, maxPingTries uint) (uint, error) {
+	row := db.db.QueryRowContext(ctx, sqlCountENRs, maxPingTries)
+	var count uint
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("CountENRs failed: %w", err)
+	}
+	return count, nil
+}
+
+func (db *DBSQLite) EnumerateENREntryCounts(
+	ctx context.Context,
+	maxPingTries uint,
+	enumFunc func(key string, value string, count uint),
+) error {
+	cursor, err := db.db.QueryContext(ctx, sqlEnumerateENREntryCounts, maxPingTries)
+	if err != nil {
+		return fmt.Errorf("EnumerateENREntryCounts failed to query: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.Next() {
+		var key, value string
+		var count uint
+		err := cursor.Scan(&key, &value, &count)
+		if err != nil {
+			return fmt.Errorf("EnumerateENREntryCounts failed to read data: %w", err)
+		}
+		enumFunc(key, value, count)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("EnumerateENREntryCounts failed to iterate: %w", err)
+	}
+	return nil
+}
+
 func stringsToAny(strValues []NodeID) []interface{} {
 	values := make([]interface{}, 0, len(strValues))
 	for _, value := range strValues {
@@ -38,3 +38,93 @@ func TestDBSQLiteInsertAndFind(t *testing.T) {
 	assert.Equal(t, addr.PortDisc, candidate.PortDisc)
 	assert.Equal(t, addr.PortRLPx, candidate.PortRLPx)
 }
+
+func TestDBSQLiteUpdateENR(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDBSQLite(filepath.Join(t.TempDir(), "observer.sqlite"))
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	var id NodeID = "ba85011c70bcc5c04d8607d3a0ed29aa6179c092cbdda10d5d32684fb33ed01bd94f588ca8f91ac48318087dcb02eaf36773a7a453f0eedd6742af668097b29c"
+	var addr NodeAddr
+	addr.IP = net.ParseIP("10.0.1.16")
+	err = db.UpsertNodeAddr(ctx, id, addr)
+	require.Nil(t, err)
+
+	err = db.UpdateENR(ctx, id, 2, "enr:2", []ENREntry{{"eth2", "a", "01"}, {"attnets", "1/64", "02"}})
+	require.Nil(t, err)
+	// older record is ignored
+	err = db.UpdateENR(ctx, id, 1, "enr:1", []ENREntry{{"eth2", "b", "03"}})
+	require.Nil(t, err)
+
+	count, err := db.CountENRs(ctx, 1)
+	require.Nil(t, err)
+	assert.Equal(t, uint(1), count)
+
+	counts := make(map[string]uint)
+	err = db.EnumerateENREntryCounts(ctx, 1, func(key string, value string, count uint) {
+		counts[key+"="+value] += count
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]uint{"eth2=a": 1, "attnets=1/64": 1}, counts)
+}
+
+func TestDBSQLiteGeoIP(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "observer.sqlite")
+	db, err := NewDBSQLite(filePath)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	// schema migration is idempotent
+	db2, err := NewDBSQLite(filePath)
+	require.Nil(t, err)
+	_ = db2.Close()
+
+	var id NodeID = "ba85011c70bcc5c04d8607d3a0ed29aa6179c092cbdda10d5d32684fb33ed01bd94f588ca8f91ac48318087dcb02eaf36773a7a453f0eedd6742af668097b29c"
+	var addr NodeAddr
+	addr.IP = net.ParseIP("10.0.1.16")
+	err = db.UpsertNodeAddr(ctx, id, addr)
+	require.Nil(t, err)
+
+	candidates, err := db.FindCountryCandidates(ctx, 10)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(candidates))
+	assert.True(t, addr.IP.Equal(candidates[id]))
+
+	err = db.UpdateCountry(ctx, id, candidates[id], "DE")
+	require.Nil(t, err)
+	err = db.UpdateASN(ctx, id, candidates[id], 24940, "Hetzner Online GmbH")
+	require.Nil(t, err)
+
+	candidates, err = db.FindCountryCandidates(ctx, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(candidates))
+	candidates, err = db.FindASNCandidates(ctx, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(candidates))
+
+	countries := make(map[string]uint)
+	err = db.EnumerateCountryCounts(ctx, 1, 1, func(country *string, count uint) {
+		require.NotNil(t, country)
+		countries[*country] += count
+	})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]uint{"DE": 1}, countries)
+
+	err = db.EnumerateASNCounts(ctx, 1, 1, func(asn *uint, asOrg string, count uint) {
+		require.NotNil(t, asn)
+		assert.Equal(t, uint(24940), *asn)
+		assert.Equal(t, "Hetzner Online GmbH", asOrg)
+		assert.Equal(t, uint(1), count)
+	})
+	require.Nil(t, err)
+
+	// IP change requires a new lookup
+	addr.IP = net.ParseIP("10.0.1.17")
+	err = db.UpsertNodeAddr(ctx, id, addr)
+	require.Nil(t, err)
+	candidates, err = db.FindCountryCandidates(ctx, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(candidates))
+}
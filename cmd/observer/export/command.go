@@ -0,0 +1,114 @@
+package export
+
+import (
+	"context"
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/spf13/cobra"
+	"github.com/urfave/cli"
+	"time"
+)
+
+type CommandFlags struct {
+	DataDir      string
+	Chain        string
+	MaxPingTries uint
+	Table        string
+	Format       string
+	Since        time.Duration
+	Until        time.Duration
+}
+
+type Command struct {
+	command cobra.Command
+	flags   CommandFlags
+}
+
+func NewCommand() *Command {
+	command := cobra.Command{
+		Use:   "export",
+		Short: "P2P network crawler database export",
+	}
+
+	instance := Command{
+		command: command,
+	}
+	instance.withDatadir()
+	instance.withChain()
+	instance.withMaxPingTries()
+	instance.withTable()
+	instance.withFormat()
+	instance.withSince()
+	instance.withUntil()
+
+	return &instance
+}
+
+func (command *Command) withDatadir() {
+	flag := utils.DataDirFlag
+	command.command.Flags().StringVar(&command.flags.DataDir, flag.Name, flag.Value.String(), flag.Usage)
+	must(command.command.MarkFlagDirname(utils.DataDirFlag.Name))
+}
+
+func (command *Command) withChain() {
+	flag := utils.ChainFlag
+	command.command.Flags().StringVar(&command.flags.Chain, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withMaxPingTries() {
+	flag := cli.UintFlag{
+		Name:  "max-ping-tries",
+		Usage: "A number of PING failures for a node to be considered dead (used by clients and network-ids)",
+		Value: 3,
+	}
+	command.command.Flags().UintVar(&command.flags.MaxPingTries, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withTable() {
+	flag := cli.StringFlag{
+		Name:  "table",
+		Usage: "What to export: nodes, clients, network-ids or handshake-errors",
+		Value: TableNodes,
+	}
+	command.command.Flags().StringVar(&command.flags.Table, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withFormat() {
+	flag := cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format: json or csv",
+		Value: FormatJSON,
+	}
+	command.command.Flags().StringVar(&command.flags.Format, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withSince() {
+	flag := cli.DurationFlag{
+		Name:  "since",
+		Usage: "Export only data updated within this time ago (0 means no limit)",
+	}
+	command.command.Flags().DurationVar(&command.flags.Since, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withUntil() {
+	flag := cli.DurationFlag{
+		Name:  "until",
+		Usage: "Export only data updated earlier than this time ago (0 means no limit)",
+	}
+	command.command.Flags().DurationVar(&command.flags.Until, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) RawCommand() *cobra.Command {
+	return &command.command
+}
+
+func (command *Command) OnRun(runFunc func(ctx context.Context, flags CommandFlags) error) {
+	command.command.RunE = func(cmd *cobra.Command, args []string) error {
+		return runFunc(cmd.Context(), command.flags)
+	}
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
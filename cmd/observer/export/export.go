@@ -0,0 +1,322 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/cmd/observer/observer"
+)
+
+const (
+	TableNodes           = "nodes"
+	TableClients         = "clients"
+	TableNetworkIDs      = "network-ids"
+	TableHandshakeErrors = "handshake-errors"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+type Filter struct {
+	Since        time.Time // zero means no limit
+	Until        time.Time // zero means no limit
+	MaxPingTries uint
+	NetworkID    uint
+}
+
+// Export writes the table as a JSON array or a CSV with a header.
+// Nodes are filtered by the time they were seen or handshaken, handshake errors by the time they happened.
+// Clients and network IDs are counted over alive nodes, clients only for the given network ID.
+func Export(ctx context.Context, db database.DB, w io.Writer, table string, format string, filter Filter) error {
+	var exportFunc func(ctx context.Context, db database.DB, writer recordWriter, filter Filter) error
+	var header []string
+	switch table {
+	case TableNodes:
+		exportFunc, header = exportNodes, NodeRecord{}.csvHeader()
+	case TableClients:
+		exportFunc, header = exportClients, CountRecord{}.csvHeader()
+	case TableNetworkIDs:
+		exportFunc, header = exportNetworkIDs, CountRecord{}.csvHeader()
+	case TableHandshakeErrors:
+		exportFunc, header = exportHandshakeErrors, HandshakeErrorRecord{}.csvHeader()
+	default:
+		return fmt.Errorf("unknown table %s", table)
+	}
+
+	writer, err := newRecordWriter(w, format, header)
+	if err != nil {
+		return err
+	}
+	if err = exportFunc(ctx, db, writer, filter); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+type NodeRecord struct {
+	ID               database.NodeID `json:"id"`
+	IP               string          `json:"ip,omitempty"`
+	PortDisc         uint16          `json:"portDisc,omitempty"`
+	PortRLPx         uint16          `json:"portRLPx,omitempty"`
+	IPv6             string          `json:"ipV6,omitempty"`
+	IPv6PortDisc     uint16          `json:"ipV6PortDisc,omitempty"`
+	IPv6PortRLPx     uint16          `json:"ipV6PortRLPx,omitempty"`
+	AddrUpdated      time.Time       `json:"addrUpdated"`
+	PingTries        uint            `json:"pingTries"`
+	IsCompatFork     *bool           `json:"compatFork,omitempty"`
+	ClientID         *string         `json:"clientID,omitempty"`
+	NetworkID        *uint           `json:"networkID,omitempty"`
+	EthVersion       *uint           `json:"ethVersion,omitempty"`
+	HandshakeUpdated *time.Time      `json:"handshakeUpdated,omitempty"`
+}
+
+func (NodeRecord) csvHeader() []string {
+	return []string{"id", "ip", "port_disc", "port_rlpx", "ip_v6", "ip_v6_port_disc", "ip_v6_port_rlpx", "addr_updated",
+		"ping_tries", "compat_fork", "client_id", "network_id", "eth_version", "handshake_updated"}
+}
+
+func (record NodeRecord) csvRow() []string {
+	row := []string{
+		string(record.ID),
+		record.IP,
+		formatPort(record.PortDisc),
+		formatPort(record.PortRLPx),
+		record.IPv6,
+		formatPort(record.IPv6PortDisc),
+		formatPort(record.IPv6PortRLPx),
+		record.AddrUpdated.UTC().Format(time.RFC3339),
+		strconv.FormatUint(uint64(record.PingTries), 10),
+		"", "", "", "", "",
+	}
+	if record.IsCompatFork != nil {
+		row[9] = strconv.FormatBool(*record.IsCompatFork)
+	}
+	if record.ClientID != nil {
+		row[10] = *record.ClientID
+	}
+	if record.NetworkID != nil {
+		row[11] = strconv.FormatUint(uint64(*record.NetworkID), 10)
+	}
+	if record.EthVersion != nil {
+		row[12] = strconv.FormatUint(uint64(*record.EthVersion), 10)
+	}
+	if record.HandshakeUpdated != nil {
+		row[13] = record.HandshakeUpdated.UTC().Format(time.RFC3339)
+	}
+	return row
+}
+
+type CountRecord struct {
+	Name  string `json:"name"`
+	Count uint   `json:"count"`
+}
+
+func (CountRecord) csvHeader() []string {
+	return []string{"name", "count"}
+}
+
+func (record CountRecord) csvRow() []string {
+	return []string{record.Name, strconv.FormatUint(uint64(record.Count), 10)}
+}
+
+type HandshakeErrorRecord struct {
+	ID    database.NodeID `json:"id"`
+	Error string          `json:"err"`
+	Time  time.Time       `json:"time"`
+}
+
+func (HandshakeErrorRecord) csvHeader() []string {
+	return []string{"id", "err", "time"}
+}
+
+func (record HandshakeErrorRecord) csvRow() []string {
+	return []string{string(record.ID), record.Error, record.Time.UTC().Format(time.RFC3339)}
+}
+
+func exportNodes(ctx context.Context, db database.DB, writer recordWriter, filter Filter) error {
+	var writeErr error
+	err := db.EnumerateNodes(ctx, filter.Since, filter.Until, func(node database.NodeInfo) {
+		if writeErr != nil {
+			return
+		}
+		record := NodeRecord{
+			ID:               node.ID,
+			PortDisc:         node.Addr.PortDisc,
+			PortRLPx:         node.Addr.PortRLPx,
+			IPv6PortDisc:     node.Addr.IPv6.PortDisc,
+			IPv6PortRLPx:     node.Addr.IPv6.PortRLPx,
+			AddrUpdated:      node.AddrUpdated,
+			PingTries:        node.PingTries,
+			IsCompatFork:     node.IsCompatFork,
+			ClientID:         node.ClientID,
+			NetworkID:        node.NetworkID,
+			EthVersion:       node.EthVersion,
+			HandshakeUpdated: node.HandshakeUpdated,
+		}
+		if node.Addr.IP != nil {
+			record.IP = node.Addr.IP.String()
+		}
+		if node.Addr.IPv6.IP != nil {
+			record.IPv6 = node.Addr.IPv6.IP.String()
+		}
+		writeErr = writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	return writeErr
+}
+
+func exportClients(ctx context.Context, db database.DB, writer recordWriter, filter Filter) error {
+	groups := make(map[string]uint)
+	err := db.EnumerateNodes(ctx, filter.Since, filter.Until, func(node database.NodeInfo) {
+		if !isAliveNode(node, filter) || ((node.NetworkID != nil) && (*node.NetworkID != filter.NetworkID)) {
+			return
+		}
+		if (node.ClientID == nil) || observer.IsClientIDBlacklisted(*node.ClientID) {
+			return
+		}
+		groups[observer.NameFromClientID(*node.ClientID)]++
+	})
+	if err != nil {
+		return err
+	}
+	return writeCounts(writer, groups)
+}
+
+func exportNetworkIDs(ctx context.Context, db database.DB, writer recordWriter, filter Filter) error {
+	groups := make(map[string]uint)
+	err := db.EnumerateNodes(ctx, filter.Since, filter.Until, func(node database.NodeInfo) {
+		if !isAliveNode(node, filter) || (node.NetworkID == nil) {
+			return
+		}
+		groups[strconv.FormatUint(uint64(*node.NetworkID), 10)]++
+	})
+	if err != nil {
+		return err
+	}
+	return writeCounts(writer, groups)
+}
+
+func exportHandshakeErrors(ctx context.Context, db database.DB, writer recordWriter, filter Filter) error {
+	var writeErr error
+	err := db.EnumerateHandshakeErrors(ctx, filter.Since, filter.Until, func(id database.NodeID, handshakeErr database.HandshakeError) {
+		if writeErr != nil {
+			return
+		}
+		writeErr = writer.Write(HandshakeErrorRecord{id, handshakeErr.StringCode, handshakeErr.Time})
+	})
+	if err != nil {
+		return err
+	}
+	return writeErr
+}
+
+func isAliveNode(node database.NodeInfo, filter Filter) bool {
+	return (node.PingTries < filter.MaxPingTries) && ((node.IsCompatFork == nil) || *node.IsCompatFork)
+}
+
+func writeCounts(writer recordWriter, groups map[string]uint) error {
+	records := make([]CountRecord, 0, len(groups))
+	for name, count := range groups {
+		records = append(records, CountRecord{name, count})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Count != records[j].Count {
+			return records[i].Count > records[j].Count
+		}
+		return records[i].Name < records[j].Name
+	})
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatPort(port uint16) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(port), 10)
+}
+
+type record interface {
+	csvHeader() []string
+	csvRow() []string
+}
+
+type recordWriter interface {
+	Write(record record) error
+	Close() error
+}
+
+func newRecordWriter(w io.Writer, format string, csvHeader []string) (recordWriter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonRecordWriter{w: w}, nil
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return &csvRecordWriter{writer}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %s", format)
+	}
+}
+
+// jsonRecordWriter writes records as a JSON array without keeping them in memory
+type jsonRecordWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (writer *jsonRecordWriter) Write(record record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if writer.count == 0 {
+		separator = "[\n"
+	}
+	writer.count++
+	if _, err = io.WriteString(writer.w, separator); err != nil {
+		return err
+	}
+	_, err = writer.w.Write(data)
+	return err
+}
+
+func (writer *jsonRecordWriter) Close() error {
+	end := "\n]\n"
+	if writer.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(writer.w, end)
+	return err
+}
+
+type csvRecordWriter struct {
+	w *csv.Writer
+}
+
+func (writer *csvRecordWriter) Write(record record) error {
+	return writer.w.Write(record.csvRow())
+}
+
+func (writer *csvRecordWriter) Close() error {
+	writer.w.Flush()
+	return writer.w.Error()
+}
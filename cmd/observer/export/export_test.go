@@ -0,0 +1,62 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewDBSQLite(filepath.Join(t.TempDir(), "observer.sqlite"))
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	var id database.NodeID = "ba85011c70bcc5c04d8607d3a0ed29aa6179c092cbdda10d5d32684fb33ed01bd94f588ca8f91ac48318087dcb02eaf36773a7a453f0eedd6742af668097b29c"
+	var addr database.NodeAddr
+	addr.IP = net.ParseIP("10.0.1.16")
+	addr.PortRLPx = 30303
+	require.Nil(t, db.UpsertNodeAddr(ctx, id, addr))
+	require.Nil(t, db.UpdateClientID(ctx, id, "erigon/v2.4.0/linux-amd64/go1.18"))
+	require.Nil(t, db.UpdateNetworkID(ctx, id, 1))
+	require.Nil(t, db.InsertHandshakeError(ctx, id, "too-many-peers"))
+
+	filter := Filter{MaxPingTries: 3, NetworkID: 1}
+
+	var buffer bytes.Buffer
+	require.Nil(t, Export(ctx, db, &buffer, TableClients, FormatCSV, filter))
+	assert.Equal(t, "name,count\nerigon,1\n", buffer.String())
+
+	buffer.Reset()
+	require.Nil(t, Export(ctx, db, &buffer, TableNetworkIDs, FormatJSON, filter))
+	var counts []CountRecord
+	require.Nil(t, json.Unmarshal(buffer.Bytes(), &counts))
+	assert.Equal(t, []CountRecord{{"1", 1}}, counts)
+
+	buffer.Reset()
+	require.Nil(t, Export(ctx, db, &buffer, TableNodes, FormatJSON, filter))
+	var nodes []NodeRecord
+	require.Nil(t, json.Unmarshal(buffer.Bytes(), &nodes))
+	require.Equal(t, 1, len(nodes))
+	assert.Equal(t, id, nodes[0].ID)
+	assert.Equal(t, "10.0.1.16", nodes[0].IP)
+	assert.Equal(t, uint16(30303), nodes[0].PortRLPx)
+
+	buffer.Reset()
+	require.Nil(t, Export(ctx, db, &buffer, TableHandshakeErrors, FormatCSV, filter))
+	assert.Contains(t, buffer.String(), "too-many-peers")
+
+	// nothing is updated in the future
+	filter.Since = time.Now().Add(time.Hour)
+	buffer.Reset()
+	require.Nil(t, Export(ctx, db, &buffer, TableNodes, FormatJSON, filter))
+	assert.Equal(t, "[]\n", buffer.String())
+}
@@ -0,0 +1,31 @@
+package geoip
+
+import "net"
+
+// LookupCountry returns ISO 3166-1 country code of the IP from Country/City databases,
+// or an empty string if it's unknown.
+func (reader *Reader) LookupCountry(ip net.IP) (string, error) {
+	record, err := reader.Lookup(ip)
+	if (err != nil) || (record == nil) {
+		return "", err
+	}
+	for _, key := range []string{"country", "registered_country"} {
+		country, _ := record[key].(map[string]interface{})
+		if code, ok := country["iso_code"].(string); ok && (code != "") {
+			return code, nil
+		}
+	}
+	return "", nil
+}
+
+// LookupASN returns autonomous system number and organization of the IP from ASN databases,
+// or 0 if it's unknown.
+func (reader *Reader) LookupASN(ip net.IP) (uint, string, error) {
+	record, err := reader.Lookup(ip)
+	if (err != nil) || (record == nil) {
+		return 0, "", err
+	}
+	asn, _ := asUint(record["autonomous_system_number"])
+	org, _ := record["autonomous_system_organization"].(string)
+	return asn, org, nil
+}
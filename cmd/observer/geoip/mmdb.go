@@ -0,0 +1,343 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// Reader of MaxMind DB files (GeoLite2/GeoIP2 Country, City and ASN databases and compatible ones).
+// See https://maxmind.github.io/MaxMind-DB/ for the format specification.
+type Reader struct {
+	buffer       []byte
+	data         []byte // data section
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	ipv4Start    uint
+	DatabaseType string
+}
+
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+const dataSectionSeparatorSize = 16
+
+func Open(filePath string) (*Reader, error) {
+	buffer, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP DB: %w", err)
+	}
+	reader, err := NewReader(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP DB %s: %w", filePath, err)
+	}
+	return reader, nil
+}
+
+func NewReader(buffer []byte) (*Reader, error) {
+	metadataStart := bytes.LastIndex(buffer, metadataStartMarker)
+	if metadataStart < 0 {
+		return nil, errors.New("metadata not found")
+	}
+	metadataStart += len(metadataStartMarker)
+	metadataValue, _, err := decoder{buffer[metadataStart:]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	metadata, ok := metadataValue.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid metadata")
+	}
+
+	reader := Reader{buffer: buffer}
+	reader.nodeCount, _ = asUint(metadata["node_count"])
+	reader.recordSize, _ = asUint(metadata["record_size"])
+	reader.ipVersion, _ = asUint(metadata["ip_version"])
+	reader.DatabaseType, _ = metadata["database_type"].(string)
+
+	if (reader.recordSize != 24) && (reader.recordSize != 28) && (reader.recordSize != 32) {
+		return nil, fmt.Errorf("unsupported record size %d", reader.recordSize)
+	}
+	if (reader.ipVersion != 4) && (reader.ipVersion != 6) {
+		return nil, fmt.Errorf("unsupported IP version %d", reader.ipVersion)
+	}
+	treeSize := reader.nodeCount * reader.recordSize / 4
+	dataStart := treeSize + dataSectionSeparatorSize
+	if dataStart > uint(metadataStart-len(metadataStartMarker)) {
+		return nil, errors.New("invalid search tree size")
+	}
+	reader.data = buffer[dataStart : metadataStart-len(metadataStartMarker)]
+
+	// IPv4 addresses are looked up in IPv6 trees under ::/96
+	if reader.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; (i < 96) && (node < reader.nodeCount); i++ {
+			node, err = reader.readRecord(node, 0)
+			if err != nil {
+				return nil, err
+			}
+		}
+		reader.ipv4Start = node
+	}
+	return &reader, nil
+}
+
+// Lookup returns the data record of the network containing the IP, or nil if it's not found.
+func (reader *Reader) Lookup(ip net.IP) (map[string]interface{}, error) {
+	node := uint(0)
+	bitCount := 128
+	if ipV4 := ip.To4(); ipV4 != nil {
+		ip = ipV4
+		bitCount = 32
+		node = reader.ipv4Start
+	} else if reader.ipVersion == 4 {
+		return nil, nil
+	}
+
+	var err error
+	for i := 0; (i < bitCount) && (node < reader.nodeCount); i++ {
+		bit := uint(ip[i/8]>>(7-uint(i%8))) & 1
+		node, err = reader.readRecord(node, bit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if node <= reader.nodeCount {
+		// not found
+		return nil, nil
+	}
+
+	offset := node - reader.nodeCount - dataSectionSeparatorSize
+	value, _, err := decoder{reader.data}.decode(offset, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data record: %w", err)
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid data record: not a map")
+	}
+	return record, nil
+}
+
+func (reader *Reader) readRecord(node uint, bit uint) (uint, error) {
+	nodeSize := reader.recordSize / 4
+	offset := node * nodeSize
+	if offset+nodeSize > uint(len(reader.buffer)) {
+		return 0, errors.New("invalid search tree node")
+	}
+	b := reader.buffer[offset : offset+nodeSize]
+	switch reader.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[0:4])), nil
+		}
+		return uint(binary.BigEndian.Uint32(b[4:8])), nil
+	}
+}
+
+// data section field types
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// decoding depth limit protects from pointer loops in corrupted files
+const maxDecodeDepth = 32
+
+var errInvalidData = errors.New("unexpected end of data")
+
+type decoder struct {
+	data []byte
+}
+
+// decode returns the value at offset and the offset of the next value
+func (d decoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("data is nested too deeply")
+	}
+	if offset >= uint(len(d.data)) {
+		return nil, 0, errInvalidData
+	}
+	ctrl := d.data[offset]
+	offset++
+	fieldType := uint(ctrl >> 5)
+
+	if fieldType == typePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	if fieldType == typeExtended {
+		if offset >= uint(len(d.data)) {
+			return nil, 0, errInvalidData
+		}
+		fieldType = 7 + uint(d.data[offset])
+		offset++
+	}
+
+	size := uint(ctrl & 0x1F)
+	if size >= 29 {
+		extraSize := size - 28
+		if offset+extraSize > uint(len(d.data)) {
+			return nil, 0, errInvalidData
+		}
+		extra := uint(0)
+		for _, b := range d.data[offset : offset+extraSize] {
+			extra = extra<<8 | uint(b)
+		}
+		offset += extraSize
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+
+	switch fieldType {
+	case typeMap:
+		value := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("map key is not a string")
+			}
+			value[keyStr], offset, err = d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		return value, offset, nil
+	case typeArray:
+		value := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			item, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			value = append(value, item)
+			offset = next
+		}
+		return value, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, offset, nil
+	}
+
+	if offset+size > uint(len(d.data)) {
+		return nil, 0, errInvalidData
+	}
+	payload := d.data[offset : offset+size]
+	next := offset + size
+
+	switch fieldType {
+	case typeString:
+		return string(payload), next, nil
+	case typeBytes:
+		return append([]byte{}, payload...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errors.New("invalid double size")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errors.New("invalid float size")
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(payload)), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, errors.New("invalid unsigned integer size")
+		}
+		value := uint64(0)
+		for _, b := range payload {
+			value = value<<8 | uint64(b)
+		}
+		return value, next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, errors.New("invalid int32 size")
+		}
+		value := uint32(0)
+		for _, b := range payload {
+			value = value<<8 | uint32(b)
+		}
+		return int64(int32(value)), next, nil
+	case typeUint128:
+		// not used by the fields we need
+		return append([]byte{}, payload...), next, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown field type %d", fieldType)
+	}
+}
+
+func (d decoder) decodePointer(ctrl byte, offset uint) (uint, uint, error) {
+	pointerSize := uint((ctrl>>3)&0x3) + 1
+	if offset+pointerSize > uint(len(d.data)) {
+		return 0, 0, errInvalidData
+	}
+	b := d.data[offset : offset+pointerSize]
+	next := offset + pointerSize
+	prefix := uint(ctrl & 0x7)
+
+	switch pointerSize {
+	case 1:
+		return prefix<<8 | uint(b[0]), next, nil
+	case 2:
+		return (prefix<<16 | uint(b[0])<<8 | uint(b[1])) + 2048, next, nil
+	case 3:
+		return (prefix<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336, next, nil
+	default:
+		return uint(binary.BigEndian.Uint32(b)), next, nil
+	}
+}
+
+func asUint(value interface{}) (uint, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return uint(v), true
+	case int64:
+		return uint(v), v >= 0
+	default:
+		return 0, false
+	}
+}
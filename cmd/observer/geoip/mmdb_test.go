@@ -0,0 +1,125 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeField supports sizes < 285
+func encodeField(fieldType byte, size int, payload []byte) []byte {
+	sizeBits := byte(size)
+	var sizeExtra []byte
+	if size >= 29 {
+		sizeBits = 29
+		sizeExtra = []byte{byte(size - 29)}
+	}
+	var out []byte
+	if fieldType <= 7 {
+		out = append(out, fieldType<<5|sizeBits)
+	} else {
+		out = append(out, sizeBits, fieldType-7)
+	}
+	out = append(out, sizeExtra...)
+	return append(out, payload...)
+}
+
+func encodeString(s string) []byte {
+	return encodeField(typeString, len(s), []byte(s))
+}
+
+func encodeUint(fieldType byte, value uint64) []byte {
+	var payload []byte
+	for ; value > 0; value >>= 8 {
+		payload = append([]byte{byte(value)}, payload...)
+	}
+	return encodeField(fieldType, len(payload), payload)
+}
+
+func encodeMap(pairs ...[]byte) []byte {
+	var out []byte
+	for _, pair := range pairs {
+		out = append(out, pair...)
+	}
+	return append(encodeField(typeMap, len(pairs)/2, nil), out...)
+}
+
+// makeTestDB makes an IPv4 DB with 10.0.0.0/8 and 11.0.0.0/8 networks
+func makeTestDB() []byte {
+	countryDE := encodeMap(encodeString("iso_code"), encodeString("DE"))
+	recordA := encodeMap(
+		encodeString("country"), countryDE,
+		encodeString("autonomous_system_number"), encodeUint(typeUint32, 24940),
+		encodeString("autonomous_system_organization"), encodeString("Hetzner Online GmbH"),
+	)
+	countryOffset := len(encodeMap()) + len(encodeString("country"))
+	recordB := encodeMap(
+		// pointer to the country map of recordA
+		encodeString("registered_country"), []byte{typePointer << 5, byte(countryOffset)},
+		encodeString("extra"), encodeUint(typeUint64, 1),
+	)
+	data := append(append([]byte{}, recordA...), recordB...)
+
+	const nodeCount = 8
+	dataRecord := func(offset int) uint { return uint(nodeCount + dataSectionSeparatorSize + offset) }
+	prefix := []uint{0, 0, 0, 0, 1, 0, 1} // first 7 bits of 10 and 11
+	var tree []byte
+	appendNode := func(left, right uint) {
+		tree = append(tree, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+	}
+	for i, bit := range prefix {
+		if bit == 0 {
+			appendNode(uint(i+1), nodeCount)
+		} else {
+			appendNode(nodeCount, uint(i+1))
+		}
+	}
+	appendNode(dataRecord(0), dataRecord(len(recordA)))
+
+	metadata := encodeMap(
+		encodeString("node_count"), encodeUint(typeUint32, nodeCount),
+		encodeString("record_size"), encodeUint(typeUint16, 24),
+		encodeString("ip_version"), encodeUint(typeUint16, 4),
+		encodeString("database_type"), encodeString("Test"),
+	)
+
+	var db []byte
+	db = append(db, tree...)
+	db = append(db, make([]byte, dataSectionSeparatorSize)...)
+	db = append(db, data...)
+	db = append(db, metadataStartMarker...)
+	return append(db, metadata...)
+}
+
+func TestReader(t *testing.T) {
+	reader, err := NewReader(makeTestDB())
+	require.Nil(t, err)
+	assert.Equal(t, "Test", reader.DatabaseType)
+
+	country, err := reader.LookupCountry(net.ParseIP("10.1.2.3"))
+	require.Nil(t, err)
+	assert.Equal(t, "DE", country)
+
+	asn, org, err := reader.LookupASN(net.ParseIP("10.1.2.3"))
+	require.Nil(t, err)
+	assert.Equal(t, uint(24940), asn)
+	assert.Equal(t, "Hetzner Online GmbH", org)
+
+	country, err = reader.LookupCountry(net.ParseIP("11.0.0.1"))
+	require.Nil(t, err)
+	assert.Equal(t, "DE", country)
+	record, err := reader.Lookup(net.ParseIP("11.0.0.1"))
+	require.Nil(t, err)
+	assert.Equal(t, uint64(1), record["extra"])
+
+	record, err = reader.Lookup(net.ParseIP("12.0.0.1"))
+	require.Nil(t, err)
+	assert.Nil(t, record)
+
+	// IPv6 is not in IPv4 DB
+	record, err = reader.Lookup(net.ParseIP("2001:db8::1"))
+	require.Nil(t, err)
+	assert.Nil(t, record)
+}
@@ -3,15 +3,19 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/cmd/observer/export"
+	"github.com/ledgerwatch/erigon/cmd/observer/geoip"
 	"github.com/ledgerwatch/erigon/cmd/observer/observer"
 	"github.com/ledgerwatch/erigon/cmd/observer/reports"
 	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/metrics"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/log/v3"
+	"os"
 	"path/filepath"
+	"time"
 )
 
 func mainWithFlags(ctx context.Context, flags observer.CommandFlags) error {
@@ -26,13 +30,21 @@ func mainWithFlags(ctx context.Context, flags observer.CommandFlags) error {
 	}
 	defer func() { _ = db.Close() }()
 
-	discV4, err := server.Listen(ctx)
+	var transport observer.DiscTransport
+	if flags.DiscV5 {
+		transport, err = server.ListenV5(ctx)
+	} else {
+		transport, err = server.Listen(ctx)
+	}
 	if err != nil {
 		return err
 	}
 
 	networkID := uint(params.NetworkIDByChainName(flags.Chain))
 	go observer.StatusLoggerLoop(ctx, db, networkID, flags.StatusLogPeriod, log.Root())
+	if metrics.Enabled {
+		go reports.MetricsLoop(ctx, db, networkID, flags.MaxPingTries, 10, flags.MetricsPeriod, log.Root())
+	}
 
 	crawlerConfig := observer.CrawlerConfig{
 		Chain:            flags.Chain,
@@ -51,7 +63,7 @@ func mainWithFlags(ctx context.Context, flags observer.CommandFlags) error {
 		KeygenConcurrency: flags.KeygenConcurrency,
 	}
 
-	crawler, err := observer.NewCrawler(discV4, db, crawlerConfig, log.Root())
+	crawler, err := observer.NewCrawler(transport, db, crawlerConfig, log.Root())
 	if err != nil {
 		return err
 	}
@@ -73,8 +85,7 @@ func reportWithFlags(ctx context.Context, flags reports.CommandFlags) error {
 		if err != nil {
 			return err
 		}
-		fmt.Println(report)
-		return nil
+		return reports.WriteReports(os.Stdout, flags.Format, []reports.NamedReport{{Name: "clientsEstimate", Report: report}})
 	}
 
 	statusReport, err := reports.CreateStatusReport(ctx, db, flags.MaxPingTries, networkID)
@@ -85,10 +96,81 @@ func reportWithFlags(ctx context.Context, flags reports.CommandFlags) error {
 	if err != nil {
 		return err
 	}
+	enrReport, err := reports.CreateENRReport(ctx, db, flags.ClientsLimit, flags.MaxPingTries)
+	if err != nil {
+		return err
+	}
+
+	namedReports := []reports.NamedReport{
+		{Name: "status", Report: statusReport},
+		{Name: "clients", Report: clientsReport},
+		{Name: "enr", Report: enrReport},
+	}
+
+	geoIPReports, err := createGeoIPReports(ctx, db, flags, networkID)
+	if err != nil {
+		return err
+	}
+	namedReports = append(namedReports, geoIPReports...)
+
+	return reports.WriteReports(os.Stdout, flags.Format, namedReports)
+}
+
+func createGeoIPReports(ctx context.Context, db database.DB, flags reports.CommandFlags, networkID uint) ([]reports.NamedReport, error) {
+	var countryReader, asnReader *geoip.Reader
+	var err error
+	if flags.GeoIPCountryDB != "" {
+		if countryReader, err = geoip.Open(flags.GeoIPCountryDB); err != nil {
+			return nil, err
+		}
+	}
+	if flags.GeoIPASNDB != "" {
+		if asnReader, err = geoip.Open(flags.GeoIPASNDB); err != nil {
+			return nil, err
+		}
+	}
+	if err = reports.UpdateGeoIP(ctx, db, countryReader, asnReader); err != nil {
+		return nil, err
+	}
+
+	var namedReports []reports.NamedReport
+	if countryReader != nil {
+		report, err := reports.CreateCountriesReport(ctx, db, flags.ClientsLimit, flags.MaxPingTries, networkID)
+		if err != nil {
+			return nil, err
+		}
+		namedReports = append(namedReports, reports.NamedReport{Name: "countries", Report: report})
+	}
+	if asnReader != nil {
+		report, err := reports.CreateASNsReport(ctx, db, flags.ClientsLimit, flags.MaxPingTries, networkID)
+		if err != nil {
+			return nil, err
+		}
+		namedReports = append(namedReports, reports.NamedReport{Name: "asns", Report: report})
+	}
+	return namedReports, nil
+}
+
+func exportWithFlags(ctx context.Context, flags export.CommandFlags) error {
+	db, err := database.NewDBSQLite(filepath.Join(flags.DataDir, "observer.sqlite"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
 
-	fmt.Println(statusReport)
-	fmt.Println(clientsReport)
-	return nil
+	filter := export.Filter{
+		MaxPingTries: flags.MaxPingTries,
+		NetworkID:    uint(params.NetworkIDByChainName(flags.Chain)),
+	}
+	now := time.Now()
+	if flags.Since > 0 {
+		filter.Since = now.Add(-flags.Since)
+	}
+	if flags.Until > 0 {
+		filter.Until = now.Add(-flags.Until)
+	}
+
+	return export.Export(ctx, db, os.Stdout, flags.Table, flags.Format, filter)
 }
 
 func main() {
@@ -101,6 +183,10 @@ func main() {
 	reportCommand.OnRun(reportWithFlags)
 	command.AddSubCommand(reportCommand.RawCommand())
 
+	exportCommand := export.NewCommand()
+	exportCommand.OnRun(exportWithFlags)
+	command.AddSubCommand(exportCommand.RawCommand())
+
 	err := command.ExecuteContext(ctx, mainWithFlags)
 	if (err != nil) && !errors.Is(err, context.Canceled) {
 		utils.Fatalf("%v", err)
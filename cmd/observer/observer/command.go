@@ -14,9 +14,11 @@ import (
 type CommandFlags struct {
 	DataDir         string
 	StatusLogPeriod time.Duration
+	MetricsPeriod   time.Duration
 
 	Chain     string
 	Bootnodes string
+	DiscV5    bool
 
 	ListenPort  int
 	NATDesc     string
@@ -56,9 +58,11 @@ func NewCommand() *Command {
 
 	instance.withDatadir()
 	instance.withStatusLogPeriod()
+	instance.withMetricsPeriod()
 
 	instance.withChain()
 	instance.withBootnodes()
+	instance.withDiscV5()
 
 	instance.withListenPort()
 	instance.withNAT()
@@ -96,6 +100,15 @@ func (command *Command) withStatusLogPeriod() {
 	command.command.Flags().DurationVar(&command.flags.StatusLogPeriod, flag.Name, flag.Value, flag.Usage)
 }
 
+func (command *Command) withMetricsPeriod() {
+	flag := cli.DurationFlag{
+		Name:  "metrics-period",
+		Usage: "How often to update the node and client metrics (if --metrics is enabled)",
+		Value: time.Minute,
+	}
+	command.command.Flags().DurationVar(&command.flags.MetricsPeriod, flag.Name, flag.Value, flag.Usage)
+}
+
 func (command *Command) withChain() {
 	flag := utils.ChainFlag
 	command.command.Flags().StringVar(&command.flags.Chain, flag.Name, flag.Value, flag.Usage)
@@ -106,6 +119,14 @@ func (command *Command) withBootnodes() {
 	command.command.Flags().StringVar(&command.flags.Bootnodes, flag.Name, flag.Value, flag.Usage)
 }
 
+func (command *Command) withDiscV5() {
+	flag := cli.BoolFlag{
+		Name:  "discv5",
+		Usage: "Crawl discv5 network instead of discv4 (default bootnodes are discv5 bootnodes)",
+	}
+	command.command.Flags().BoolVar(&command.flags.DiscV5, flag.Name, false, flag.Usage)
+}
+
 func (command *Command) withListenPort() {
 	flag := utils.ListenPortFlag
 	command.command.Flags().IntVar(&command.flags.ListenPort, flag.Name, flag.Value, flag.Usage)
@@ -18,7 +18,7 @@ import (
 )
 
 type Crawler struct {
-	transport DiscV4Transport
+	transport DiscTransport
 
 	db        database.DBRetrier
 	saveQueue *utils.TaskQueue
@@ -49,7 +49,7 @@ type CrawlerConfig struct {
 }
 
 func NewCrawler(
-	transport DiscV4Transport,
+	transport DiscTransport,
 	db database.DB,
 	config CrawlerConfig,
 	logger log.Logger,
@@ -376,6 +376,13 @@ func (crawler *Crawler) Run(ctx context.Context) error {
 	return nil
 }
 
+func (crawler *Crawler) saveENR(ctx context.Context, id database.NodeID, node *enode.Node) error {
+	if !isSignedENR(node) {
+		return nil
+	}
+	return crawler.db.UpdateENR(ctx, id, node.Seq(), node.String(), decodeENREntries(node))
+}
+
 func (crawler *Crawler) saveInterrogationResult(
 	ctx context.Context,
 	id database.NodeID,
@@ -401,6 +408,19 @@ func (crawler *Crawler) saveInterrogationResult(
 		if dbErr != nil {
 			return dbErr
 		}
+
+		// discv5 neighbors are signed records
+		dbErr = crawler.saveENR(ctx, peerID, peer)
+		if dbErr != nil {
+			return dbErr
+		}
+	}
+
+	if (result != nil) && (result.ENR != nil) {
+		dbErr := crawler.saveENR(ctx, id, result.ENR)
+		if dbErr != nil {
+			return dbErr
+		}
 	}
 
 	if (result != nil) && (len(result.KeygenKeys) >= 15) {
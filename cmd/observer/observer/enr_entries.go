@@ -0,0 +1,99 @@
+package observer
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/eth/protocols/eth"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// address entries are stored in the nodes table
+var enrSkippedKeys = map[string]bool{
+	"id":        true,
+	"secp256k1": true,
+	"ip":        true,
+	"ip6":       true,
+	"tcp":       true,
+	"tcp6":      true,
+	"udp":       true,
+	"udp6":      true,
+}
+
+// isSignedENR returns false for records without a signature:
+// nodes made from database addresses and discv4 neighbors.
+func isSignedENR(node *enode.Node) bool {
+	record := node.Record()
+	return (record.IdentityScheme() == "v4") && (len(record.Signature()) > 0)
+}
+
+// decodeENREntries returns all non-address ENR entries.
+// Value is a human-readable decoded value for the known keys, and a hex of the RLP value otherwise.
+func decodeENREntries(node *enode.Node) []database.ENREntry {
+	var entries []database.ENREntry
+	elements := node.Record().AppendElements(nil)
+	// elements[0] is seq, the rest are key-value pairs
+	for i := 1; i+1 < len(elements); i += 2 {
+		key := elements[i].(string)
+		if enrSkippedKeys[key] {
+			continue
+		}
+		raw := elements[i+1].(rlp.RawValue)
+		value, err := decodeENREntryValue(node, key, raw)
+		if err != nil {
+			value = "invalid"
+		}
+		entries = append(entries, database.ENREntry{
+			Key:   key,
+			Value: value,
+			Raw:   hex.EncodeToString(raw),
+		})
+	}
+	return entries
+}
+
+func decodeENREntryValue(node *enode.Node, key string, raw rlp.RawValue) (string, error) {
+	switch key {
+	case "eth":
+		forkID, err := eth.LoadENRForkID(node.Record())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%x/%d", forkID.Hash, forkID.Next), nil
+	case "eth2":
+		// SSZ ENRForkID: fork_digest (4 bytes), next_fork_version (4 bytes), next_fork_epoch (uint64 LE)
+		var data []byte
+		if err := rlp.DecodeBytes(raw, &data); err != nil {
+			return "", err
+		}
+		if len(data) != 16 {
+			return "", fmt.Errorf("invalid eth2 entry length %d", len(data))
+		}
+		return fmt.Sprintf("%x/%x/%d", data[:4], data[4:8], binary.LittleEndian.Uint64(data[8:])), nil
+	case "attnets", "syncnets":
+		// SSZ bitvector of subscribed subnets
+		var data []byte
+		if err := rlp.DecodeBytes(raw, &data); err != nil {
+			return "", err
+		}
+		count := 0
+		for _, b := range data {
+			count += bits.OnesCount8(b)
+		}
+		return fmt.Sprintf("%d/%d", count, len(data)*8), nil
+	case "client":
+		// [name, version, build]
+		var parts []string
+		if err := rlp.DecodeBytes(raw, &parts); err != nil {
+			return "", err
+		}
+		return strings.Join(parts, "/"), nil
+	default:
+		return hex.EncodeToString(raw), nil
+	}
+}
@@ -0,0 +1,44 @@
+package observer
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/enr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeENREntries(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	var record enr.Record
+	record.Set(enr.IPv4{10, 0, 1, 16})
+	record.Set(enr.UDP(30303))
+	record.Set(enr.WithEntry("eth2", []byte{0xb5, 0x30, 0x3f, 0x2a, 2, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+	record.Set(enr.WithEntry("attnets", []byte{0x81, 0, 0, 0, 0, 0, 0, 0x01}))
+	record.Set(enr.WithEntry("client", []string{"Lighthouse", "v2.5.1"}))
+	record.Set(enr.WithEntry("snap", []uint{}))
+	require.Nil(t, enode.SignV4(&record, key))
+	node, err := enode.New(enode.ValidSchemes, &record)
+	require.Nil(t, err)
+
+	require.True(t, isSignedENR(node))
+	assert.False(t, isSignedENR(enode.NewV4(&key.PublicKey, node.IP(), 0, 30303)))
+
+	entries := decodeENREntries(node)
+	values := make(map[string]string)
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+	assert.Equal(t, map[string]string{
+		"attnets": "3/64",
+		"client":  "Lighthouse/v2.5.1",
+		"eth2":    "b5303f2a/02000000/18446744073709551615",
+		"snap":    "c0",
+	}, values)
+	assert.Contains(t, entries, database.ENREntry{Key: "snap", Value: "c0", Raw: "c0"})
+}
@@ -15,15 +15,24 @@ import (
 	"time"
 )
 
-type DiscV4Transport interface {
+type DiscTransport interface {
 	RequestENR(*enode.Node) (*enode.Node, error)
 	Ping(*enode.Node) error
+}
+
+type DiscV4Transport interface {
+	DiscTransport
 	FindNode(toNode *enode.Node, targetKey *ecdsa.PublicKey) ([]*enode.Node, error)
 }
 
+type DiscV5Transport interface {
+	DiscTransport
+	FindNode(toNode *enode.Node, distances []uint) ([]*enode.Node, error)
+}
+
 type Interrogator struct {
 	node       *enode.Node
-	transport  DiscV4Transport
+	transport  DiscTransport
 	forkFilter forkid.Filter
 
 	diplomat           *Diplomat
@@ -42,13 +51,14 @@ type InterrogationResult struct {
 	IsCompatFork       *bool
 	HandshakeResult    *DiplomatResult
 	HandshakeRetryTime *time.Time
+	ENR                *enode.Node
 	KeygenKeys         []*ecdsa.PublicKey
 	Peers              []*enode.Node
 }
 
 func NewInterrogator(
 	node *enode.Node,
-	transport DiscV4Transport,
+	transport DiscTransport,
 	forkFilter forkid.Filter,
 	diplomat *Diplomat,
 	handshakeRetryTime *time.Time,
@@ -131,44 +141,81 @@ func (interrogator *Interrogator) Run(ctx context.Context) (*InterrogationResult
 		}
 	}
 
-	// keygen
-	keys, err := interrogator.keygen(ctx)
+	var keys []*ecdsa.PublicKey
+	var peers []*enode.Node
+	if transportV5, ok := interrogator.transport.(DiscV5Transport); ok {
+		peers, err = interrogator.findNodesV5(ctx, transportV5)
+	} else {
+		// keygen
+		keys, err = interrogator.keygen(ctx)
+		if err != nil {
+			return nil, NewInterrogationError(InterrogationErrorKeygen, err)
+		}
+		peers, err = interrogator.findNodesV4(ctx, interrogator.transport.(DiscV4Transport), keys)
+	}
 	if err != nil {
-		return nil, NewInterrogationError(InterrogationErrorKeygen, err)
+		if isFindNodeTimeoutError(err) {
+			return nil, NewInterrogationError(InterrogationErrorFindNodeTimeout, err)
+		}
+		return nil, NewInterrogationError(InterrogationErrorFindNode, err)
 	}
 
-	// FindNode
+	result := InterrogationResult{
+		interrogator.node,
+		isCompatFork,
+		handshakeResult,
+		handshakeRetryTime,
+		enr,
+		keys,
+		peers,
+	}
+	return &result, nil
+}
+
+func (interrogator *Interrogator) findNodesV4(ctx context.Context, transport DiscV4Transport, keys []*ecdsa.PublicKey) ([]*enode.Node, error) {
 	peersByID := make(map[enode.ID]*enode.Node)
 	for _, key := range keys {
-		neighbors, err := interrogator.findNode(ctx, key)
+		neighbors, err := interrogator.findNode(ctx, func() ([]*enode.Node, error) {
+			return transport.FindNode(interrogator.node, key)
+		})
 		if err != nil {
-			if isFindNodeTimeoutError(err) {
-				return nil, NewInterrogationError(InterrogationErrorFindNodeTimeout, err)
-			}
-			return nil, NewInterrogationError(InterrogationErrorFindNode, err)
+			return nil, err
 		}
+		addPeers(peersByID, neighbors)
 
-		for _, node := range neighbors {
-			if node.Incomplete() {
-				continue
-			}
-			peersByID[node.ID()] = node
+		utils.Sleep(ctx, 1*time.Second)
+	}
+	return valuesOfIDToNodeMap(peersByID), nil
+}
+
+// findNodesV5 - discv5 FINDNODE asks for nodes at given log-distances, no keygen is needed.
+// Buckets of the farthest distances contain almost all nodes known to the remote side.
+func (interrogator *Interrogator) findNodesV5(ctx context.Context, transport DiscV5Transport) ([]*enode.Node, error) {
+	peersByID := make(map[enode.ID]*enode.Node)
+	for distance := uint(256); distance > 256-findNodeV5Distances; distance -= 3 {
+		distances := []uint{distance, distance - 1, distance - 2}
+		neighbors, err := interrogator.findNode(ctx, func() ([]*enode.Node, error) {
+			return transport.FindNode(interrogator.node, distances)
+		})
+		if err != nil {
+			return nil, err
 		}
+		addPeers(peersByID, neighbors)
 
 		utils.Sleep(ctx, 1*time.Second)
 	}
+	return valuesOfIDToNodeMap(peersByID), nil
+}
 
-	peers := valuesOfIDToNodeMap(peersByID)
+const findNodeV5Distances = 15
 
-	result := InterrogationResult{
-		interrogator.node,
-		isCompatFork,
-		handshakeResult,
-		handshakeRetryTime,
-		keys,
-		peers,
+func addPeers(peersByID map[enode.ID]*enode.Node, neighbors []*enode.Node) {
+	for _, node := range neighbors {
+		if node.Incomplete() {
+			continue
+		}
+		peersByID[node.ID()] = node
 	}
-	return &result, nil
 }
 
 func (interrogator *Interrogator) keygen(ctx context.Context) ([]*ecdsa.PublicKey, error) {
@@ -197,10 +244,10 @@ func (interrogator *Interrogator) keygen(ctx context.Context) ([]*ecdsa.PublicKe
 	return keys, ctx.Err()
 }
 
-func (interrogator *Interrogator) findNode(ctx context.Context, targetKey *ecdsa.PublicKey) ([]*enode.Node, error) {
+func (interrogator *Interrogator) findNode(ctx context.Context, findNode func() ([]*enode.Node, error)) ([]*enode.Node, error) {
 	delayForAttempt := func(attempt int) time.Duration { return 2 * time.Second }
 	resultAny, err := utils.Retry(ctx, 2, delayForAttempt, isFindNodeTimeoutError, interrogator.log, "FindNode", func(ctx context.Context) (interface{}, error) {
-		return interrogator.transport.FindNode(interrogator.node, targetKey)
+		return findNode()
 	})
 
 	if resultAny == nil {
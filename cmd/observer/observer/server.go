@@ -60,7 +60,12 @@ func NewServer(flags CommandFlags) (*Server, error) {
 		}
 	}
 
-	bootnodes, err := utils.GetBootnodesFromFlags(flags.Bootnodes, flags.Chain)
+	var bootnodes []*enode.Node
+	if flags.DiscV5 && (flags.Bootnodes == "") {
+		bootnodes, err = utils.ParseNodesFromURLs(params.V5Bootnodes)
+	} else {
+		bootnodes, err = utils.GetBootnodesFromFlags(flags.Bootnodes, flags.Chain)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("bootnodes parse error: %w", err)
 	}
@@ -153,6 +158,22 @@ func (server *Server) detectNATExternalIP() (net.IP, error) {
 }
 
 func (server *Server) Listen(ctx context.Context) (*discover.UDPv4, error) {
+	conn, err := server.listenUDP(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return discover.ListenV4(ctx, conn, server.localNode, server.discConfig)
+}
+
+func (server *Server) ListenV5(ctx context.Context) (*discover.UDPv5, error) {
+	conn, err := server.listenUDP(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return discover.ListenV5(ctx, conn, server.localNode, server.discConfig)
+}
+
+func (server *Server) listenUDP(ctx context.Context) (*net.UDPConn, error) {
 	if server.natInterface != nil {
 		ip, err := server.detectNATExternalIP()
 		if err != nil {
@@ -179,5 +200,5 @@ func (server *Server) Listen(ctx context.Context) (*discover.UDPv4, error) {
 
 	server.log.Debug("Discovery UDP listener is up", "addr", realAddr)
 
-	return discover.ListenV4(ctx, conn, server.localNode, server.discConfig)
+	return conn, nil
 }
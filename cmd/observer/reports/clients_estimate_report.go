@@ -9,13 +9,13 @@ import (
 )
 
 type ClientsEstimateReportEntry struct {
-	Name      string
-	CountLow  uint
-	CountHigh uint
+	Name      string `json:"name"`
+	CountLow  uint   `json:"countLow"`
+	CountHigh uint   `json:"countHigh"`
 }
 
 type ClientsEstimateReport struct {
-	Clients []ClientsEstimateReportEntry
+	Clients []ClientsEstimateReportEntry `json:"clients"`
 }
 
 func CreateClientsEstimateReport(
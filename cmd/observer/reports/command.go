@@ -13,6 +13,10 @@ type CommandFlags struct {
 	ClientsLimit uint
 	MaxPingTries uint
 	Estimate     bool
+	Format       string
+
+	GeoIPCountryDB string
+	GeoIPASNDB     string
 }
 
 type Command struct {
@@ -34,6 +38,9 @@ func NewCommand() *Command {
 	instance.withClientsLimit()
 	instance.withMaxPingTries()
 	instance.withEstimate()
+	instance.withFormat()
+	instance.withGeoIPCountryDB()
+	instance.withGeoIPASNDB()
 
 	return &instance
 }
@@ -75,6 +82,33 @@ func (command *Command) withEstimate() {
 	command.command.Flags().BoolVar(&command.flags.Estimate, flag.Name, false, flag.Usage)
 }
 
+func (command *Command) withFormat() {
+	flag := cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format: text, json or csv",
+		Value: FormatText,
+	}
+	command.command.Flags().StringVar(&command.flags.Format, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withGeoIPCountryDB() {
+	flag := cli.StringFlag{
+		Name:  "geoip-country-db",
+		Usage: "A path to MaxMind-format (mmdb) GeoIP Country or City DB to report node countries",
+	}
+	command.command.Flags().StringVar(&command.flags.GeoIPCountryDB, flag.Name, flag.Value, flag.Usage)
+	must(command.command.MarkFlagFilename(flag.Name))
+}
+
+func (command *Command) withGeoIPASNDB() {
+	flag := cli.StringFlag{
+		Name:  "geoip-asn-db",
+		Usage: "A path to MaxMind-format (mmdb) GeoIP ASN DB to report node hosting providers",
+	}
+	command.command.Flags().StringVar(&command.flags.GeoIPASNDB, flag.Name, flag.Value, flag.Usage)
+	must(command.command.MarkFlagFilename(flag.Name))
+}
+
 func (command *Command) RawCommand() *cobra.Command {
 	return &command.command
 }
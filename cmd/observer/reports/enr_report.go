@@ -0,0 +1,100 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+)
+
+// ENR keys which values are broken down in the report, other keys are only counted
+var enrReportKeys = []string{"eth", "eth2", "attnets", "syncnets", "client"}
+
+type ENRReportValue struct {
+	Value string `json:"value"`
+	Count uint   `json:"count"`
+}
+
+type ENRReportKey struct {
+	Key    string           `json:"key"`
+	Count  uint             `json:"count"`
+	Values []ENRReportValue `json:"values,omitempty"`
+}
+
+type ENRReport struct {
+	TotalCount uint           `json:"total"`
+	Keys       []ENRReportKey `json:"keys"`
+	OtherKeys  []ENRReportKey `json:"otherKeys"`
+}
+
+func CreateENRReport(ctx context.Context, db database.DB, limit uint, maxPingTries uint) (*ENRReport, error) {
+	totalCount, err := db.CountENRs(ctx, maxPingTries)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]map[string]uint)
+	enumFunc := func(key string, value string, count uint) {
+		if groups[key] == nil {
+			groups[key] = make(map[string]uint)
+		}
+		groups[key][value] += count
+	}
+	if err := db.EnumerateENREntryCounts(ctx, maxPingTries, enumFunc); err != nil {
+		return nil, err
+	}
+
+	report := ENRReport{TotalCount: totalCount}
+
+	for _, key := range enrReportKeys {
+		values := groups[key]
+		delete(groups, key)
+
+		keyReport := ENRReportKey{Key: key, Count: sumMapValues(values)}
+		for i := uint(0); i < limit; i++ {
+			value, count := takeMapMaxValue(values)
+			if count == 0 {
+				break
+			}
+			keyReport.Values = append(keyReport.Values, ENRReportValue{value, count})
+		}
+		if othersCount := sumMapValues(values); othersCount > 0 {
+			keyReport.Values = append(keyReport.Values, ENRReportValue{"...", othersCount})
+		}
+		report.Keys = append(report.Keys, keyReport)
+	}
+
+	for key, values := range groups {
+		report.OtherKeys = append(report.OtherKeys, ENRReportKey{Key: key, Count: sumMapValues(values)})
+	}
+	sort.Slice(report.OtherKeys, func(i, j int) bool {
+		return report.OtherKeys[i].Count > report.OtherKeys[j].Count
+	})
+
+	return &report, nil
+}
+
+func (report *ENRReport) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("enr: %d records", report.TotalCount))
+	builder.WriteRune('\n')
+	for _, key := range report.Keys {
+		builder.WriteString(fmt.Sprintf("%s: %d", key.Key, key.Count))
+		builder.WriteRune('\n')
+		for _, value := range key.Values {
+			builder.WriteString(fmt.Sprintf("%6d %s", value.Count, value.Value))
+			builder.WriteRune('\n')
+		}
+	}
+	if len(report.OtherKeys) > 0 {
+		builder.WriteString("other keys:")
+		builder.WriteRune('\n')
+		for _, key := range report.OtherKeys {
+			builder.WriteString(fmt.Sprintf("%6d %s", key.Count, key.Key))
+			builder.WriteRune('\n')
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,108 @@
+package reports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+type Report interface {
+	fmt.Stringer
+	// CSVRows returns rows of "key, value, count" columns
+	CSVRows() [][]string
+}
+
+type NamedReport struct {
+	Name   string
+	Report Report
+}
+
+// WriteReports writes reports in the given format:
+// text - as printed by String(),
+// json - an object with a field per report name,
+// csv - rows of "report, key, value, count" columns.
+func WriteReports(w io.Writer, format string, reports []NamedReport) error {
+	switch format {
+	case FormatText:
+		for _, report := range reports {
+			if _, err := fmt.Fprintln(w, report.Report); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		object := make(map[string]Report, len(reports))
+		for _, report := range reports {
+			object[report.Name] = report.Report
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(object)
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"report", "key", "value", "count"}); err != nil {
+			return err
+		}
+		for _, report := range reports {
+			for _, row := range report.Report.CSVRows() {
+				if err := writer.Write(append([]string{report.Name}, row...)); err != nil {
+					return err
+				}
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+func (report *StatusReport) CSVRows() [][]string {
+	return [][]string{
+		{"total", "", formatCount(report.TotalCount)},
+		{"distinct IPs", "", formatCount(report.DistinctIPCount)},
+	}
+}
+
+func (report *ClientsReport) CSVRows() [][]string {
+	rows := make([][]string, 0, len(report.Clients))
+	for _, client := range report.Clients {
+		rows = append(rows, []string{"client", client.Name, formatCount(client.Count)})
+	}
+	return rows
+}
+
+func (report *ClientsEstimateReport) CSVRows() [][]string {
+	rows := make([][]string, 0, len(report.Clients)*2)
+	for _, client := range report.Clients {
+		rows = append(rows,
+			[]string{"client low", client.Name, formatCount(client.CountLow)},
+			[]string{"client high", client.Name, formatCount(client.CountHigh)})
+	}
+	return rows
+}
+
+func (report *ENRReport) CSVRows() [][]string {
+	rows := [][]string{{"total", "", formatCount(report.TotalCount)}}
+	for _, key := range report.Keys {
+		for _, value := range key.Values {
+			rows = append(rows, []string{key.Key, value.Value, formatCount(value.Count)})
+		}
+	}
+	for _, key := range report.OtherKeys {
+		rows = append(rows, []string{key.Key, "", formatCount(key.Count)})
+	}
+	return rows
+}
+
+func formatCount(count uint) string {
+	return strconv.FormatUint(uint64(count), 10)
+}
@@ -0,0 +1,171 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/cmd/observer/geoip"
+)
+
+const geoIPUpdateBatchSize = 1000
+
+// UpdateGeoIP looks up countries and ASNs of nodes which IPs were not looked up yet, and saves them to the nodes table.
+// Readers may be nil.
+func UpdateGeoIP(ctx context.Context, db database.DB, countryReader *geoip.Reader, asnReader *geoip.Reader) error {
+	if countryReader != nil {
+		err := updateGeoIP(ctx, db.FindCountryCandidates, func(id database.NodeID, ip net.IP) error {
+			country, err := countryReader.LookupCountry(ip)
+			if err != nil {
+				return err
+			}
+			return db.UpdateCountry(ctx, id, ip, country)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if asnReader != nil {
+		err := updateGeoIP(ctx, db.FindASNCandidates, func(id database.NodeID, ip net.IP) error {
+			asn, asOrg, err := asnReader.LookupASN(ip)
+			if err != nil {
+				return err
+			}
+			return db.UpdateASN(ctx, id, ip, asn, asOrg)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateGeoIP(
+	ctx context.Context,
+	findCandidates func(ctx context.Context, limit uint) (map[database.NodeID]net.IP, error),
+	update func(id database.NodeID, ip net.IP) error,
+) error {
+	for {
+		candidates, err := findCandidates(ctx, geoIPUpdateBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+		for id, ip := range candidates {
+			if err := update(id, ip); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type GeoIPReportEntry struct {
+	Name            string  `json:"name"`
+	Count           uint    `json:"count"`
+	Share           float64 `json:"share"`
+	CumulativeShare float64 `json:"cumulativeShare"`
+}
+
+// GeoIPReport - distribution of nodes by country or by autonomous system (hosting provider).
+// Shares are relative to the nodes with a known country/ASN,
+// cumulative shares of top entries show how much the network is centralized.
+type GeoIPReport struct {
+	Title        string `json:"-"`
+	csvKey       string
+	Entries      []GeoIPReportEntry `json:"entries"`
+	TotalCount   uint               `json:"total"`
+	UnknownCount uint               `json:"unknown"`
+}
+
+func CreateCountriesReport(ctx context.Context, db database.DB, limit uint, maxPingTries uint, networkID uint) (*GeoIPReport, error) {
+	groups := make(map[string]uint)
+	unknownCount := uint(0)
+	enumFunc := func(country *string, count uint) {
+		if country != nil {
+			groups[*country] += count
+		} else {
+			unknownCount += count
+		}
+	}
+	if err := db.EnumerateCountryCounts(ctx, maxPingTries, networkID, enumFunc); err != nil {
+		return nil, err
+	}
+	return makeGeoIPReport("countries", "country", groups, unknownCount, limit), nil
+}
+
+func CreateASNsReport(ctx context.Context, db database.DB, limit uint, maxPingTries uint, networkID uint) (*GeoIPReport, error) {
+	groups := make(map[string]uint)
+	unknownCount := uint(0)
+	enumFunc := func(asn *uint, asOrg string, count uint) {
+		if asn != nil {
+			groups[fmt.Sprintf("AS%d %s", *asn, asOrg)] += count
+		} else {
+			unknownCount += count
+		}
+	}
+	if err := db.EnumerateASNCounts(ctx, maxPingTries, networkID, enumFunc); err != nil {
+		return nil, err
+	}
+	return makeGeoIPReport("ASNs", "asn", groups, unknownCount, limit), nil
+}
+
+func makeGeoIPReport(title string, csvKey string, groups map[string]uint, unknownCount uint, limit uint) *GeoIPReport {
+	totalCount := sumMapValues(groups)
+	share := func(count uint) float64 {
+		if totalCount == 0 {
+			return 0
+		}
+		return float64(count) / float64(totalCount)
+	}
+
+	report := GeoIPReport{
+		Title:        title,
+		csvKey:       csvKey,
+		TotalCount:   totalCount,
+		UnknownCount: unknownCount,
+	}
+
+	var cumulativeCount uint
+	for i := uint(0); i < limit; i++ {
+		name, count := takeMapMaxValue(groups)
+		if count == 0 {
+			break
+		}
+		cumulativeCount += count
+		report.Entries = append(report.Entries, GeoIPReportEntry{name, count, share(count), share(cumulativeCount)})
+	}
+
+	if othersCount := sumMapValues(groups); othersCount > 0 {
+		report.Entries = append(report.Entries, GeoIPReportEntry{"...", othersCount, share(othersCount), 1})
+	}
+	return &report
+}
+
+func (report *GeoIPReport) String() string {
+	var builder strings.Builder
+	builder.WriteString(report.Title + ":")
+	builder.WriteRune('\n')
+	for _, entry := range report.Entries {
+		builder.WriteString(fmt.Sprintf("%6d %5.1f%% %5.1f%% %s", entry.Count, entry.Share*100, entry.CumulativeShare*100, entry.Name))
+		builder.WriteRune('\n')
+	}
+	builder.WriteString(fmt.Sprintf("%6d total", report.TotalCount))
+	builder.WriteRune('\n')
+	builder.WriteString(fmt.Sprintf("%6d unknown", report.UnknownCount))
+	builder.WriteRune('\n')
+	return builder.String()
+}
+
+func (report *GeoIPReport) CSVRows() [][]string {
+	rows := make([][]string, 0, len(report.Entries)+2)
+	for _, entry := range report.Entries {
+		rows = append(rows, []string{report.csvKey, entry.Name, formatCount(entry.Count)})
+	}
+	return append(rows,
+		[]string{"total", "", formatCount(report.TotalCount)},
+		[]string{"unknown", "", formatCount(report.UnknownCount)})
+}
@@ -0,0 +1,123 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/cmd/observer/utils"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// metricsValues keeps the latest report values for the gauges
+type metricsValues struct {
+	lock            sync.RWMutex
+	totalCount      uint
+	distinctIPCount uint
+	clientsTotal    uint
+	clients         map[string]uint
+}
+
+func (values *metricsValues) get(f func() uint) float64 {
+	values.lock.RLock()
+	defer values.lock.RUnlock()
+	return float64(f())
+}
+
+// MetricsLoop periodically updates Prometheus gauges (served with --metrics):
+// observer_nodes, observer_ips, observer_clients{client="..."} and observer_clients_share{client="..."}.
+// Clients outside of the top clientsLimit are counted as "...".
+func MetricsLoop(
+	ctx context.Context,
+	db database.DB,
+	networkID uint,
+	maxPingTries uint,
+	clientsLimit uint,
+	period time.Duration,
+	logger log.Logger,
+) {
+	values := metricsValues{clients: make(map[string]uint)}
+	metrics.GetOrCreateGauge("observer_nodes", func() float64 {
+		return values.get(func() uint { return values.totalCount })
+	})
+	metrics.GetOrCreateGauge("observer_ips", func() float64 {
+		return values.get(func() uint { return values.distinctIPCount })
+	})
+
+	for ctx.Err() == nil {
+		statusReport, err := CreateStatusReport(ctx, db, maxPingTries, networkID)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("Failed to update metrics", "err", err)
+			}
+			utils.Sleep(ctx, period)
+			continue
+		}
+		clientsReport, err := CreateClientsReport(ctx, db, clientsLimit, maxPingTries, networkID)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error("Failed to update metrics", "err", err)
+			}
+			utils.Sleep(ctx, period)
+			continue
+		}
+
+		values.lock.Lock()
+		values.totalCount = statusReport.TotalCount
+		values.distinctIPCount = statusReport.DistinctIPCount
+		// clients which dropped out of the top are reported as 0
+		for name := range values.clients {
+			values.clients[name] = 0
+		}
+		var newClients []string
+		for _, client := range clientsReport.Clients {
+			switch client.Name {
+			case "total":
+				values.clientsTotal = client.Count
+			case "unknown":
+			default:
+				if _, ok := values.clients[client.Name]; !ok {
+					newClients = append(newClients, client.Name)
+				}
+				values.clients[client.Name] = client.Count
+			}
+		}
+		values.lock.Unlock()
+
+		for _, name := range newClients {
+			name := name
+			label := metricsLabelValue(name)
+			metrics.GetOrCreateGauge(fmt.Sprintf(`observer_clients{client="%s"}`, label), func() float64 {
+				return values.get(func() uint { return values.clients[name] })
+			})
+			metrics.GetOrCreateGauge(fmt.Sprintf(`observer_clients_share{client="%s"}`, label), func() float64 {
+				values.lock.RLock()
+				defer values.lock.RUnlock()
+				if values.clientsTotal == 0 {
+					return 0
+				}
+				return float64(values.clients[name]) / float64(values.clientsTotal)
+			})
+		}
+		logger.Debug("Metrics updated", "nodes", statusReport.TotalCount, "clients", len(clientsReport.Clients))
+
+		utils.Sleep(ctx, period)
+	}
+}
+
+// metricsLabelValue replaces characters which are not allowed in the metric name,
+// client names are reported by remote nodes and can be arbitrary
+func metricsLabelValue(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.+ ", r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
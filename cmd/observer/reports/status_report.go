@@ -8,8 +8,8 @@ import (
 )
 
 type StatusReport struct {
-	TotalCount      uint
-	DistinctIPCount uint
+	TotalCount      uint `json:"total"`
+	DistinctIPCount uint `json:"distinctIPs"`
 }
 
 func CreateStatusReport(ctx context.Context, db database.DB, maxPingTries uint, networkID uint) (*StatusReport, error) {
@@ -24,9 +24,13 @@ type HttpCfg struct {
 	API                     []string
 	Gascap                  uint64
 	MaxTraces               uint64
+	LogsMaxBlockRange       uint64 // Limits block range of eth_getLogs queries
+	LogsMaxResults          uint64 // Limits amount of logs returned by eth_getLogs, the rest is available by cursor
 	WebsocketEnabled        bool
+	GraphQLEnabled          bool
 	WebsocketCompression    bool
 	RpcAllowListFilePath    string
+	RpcRateLimitsFilePath   string
 	RpcBatchConcurrency     uint
 	DBReadConcurrency       int
 	TraceCompatibility      bool // Bug for bug compatibility for trace_ routines with OpenEthereum
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+func parseRateLimitsForRPC(path string) (rpc.RateLimits, error) {
+	path = strings.TrimSpace(path)
+	if path == "" { // no file is provided
+		return rpc.RateLimits{}, nil
+	}
+
+	fileContents, err := os.ReadFile(path)
+	if err != nil {
+		return rpc.RateLimits{}, err
+	}
+
+	var limits rpc.RateLimits
+	if err = json.Unmarshal(fileContents, &limits); err != nil {
+		return rpc.RateLimits{}, err
+	}
+	return limits, nil
+}
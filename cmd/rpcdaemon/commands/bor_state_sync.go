@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus/bor"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// maxStateSyncEventsRange limits the number of events requested by one bor_getStateSyncEvents call
+const maxStateSyncEventsRange = 1000
+
+// stateCommittedTopic - StateCommitted(uint256 indexed stateId, bool success) is logged by the state receiver contract for every committed event
+var stateCommittedTopic = crypto.Keccak256Hash([]byte("StateCommitted(uint256,bool)"))
+
+// BlockStateSyncEvents is the state-sync of a block: the committed events and the receipt of the state-sync transaction
+type BlockStateSyncEvents struct {
+	BlockNumber     hexutil.Uint64         `json:"blockNumber"`
+	BlockHash       common.Hash            `json:"blockHash"`
+	TransactionHash common.Hash            `json:"transactionHash"`
+	Events          []*bor.StateSyncEvent  `json:"events"`
+	Receipt         map[string]interface{} `json:"receipt"`
+}
+
+// GetStateSyncEvents returns the committed state-sync events with ids from the range [from, to].
+// Events committed before the node started to index them are not returned.
+func (api *BorImpl) GetStateSyncEvents(ctx context.Context, from, to uint64) ([]*bor.StateSyncEvent, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid event range: from %d is greater than to %d", from, to)
+	}
+	if to-from >= maxStateSyncEventsRange {
+		return nil, fmt.Errorf("event range is too large: %d, limit %d", to-from+1, maxStateSyncEventsRange)
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return bor.ReadStateSyncEvents(tx, from, to)
+}
+
+// GetBlockStateSyncEvents returns the state-sync events committed by a block and the receipt of its state-sync transaction,
+// nil if the block doesn't commit any event.
+func (api *BorImpl) GetBlockStateSyncEvents(ctx context.Context, number rpc.BlockNumber) (*BlockStateSyncEvents, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNum, err := getBlockNumber(number, tx)
+	if err != nil {
+		return nil, err
+	}
+	block, err := api.blockByNumberWithSenders(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil // not error, see https://github.com/ledgerwatch/erigon/issues/1645
+	}
+	receipt := rawdb.ReadBorReceipt(tx, block.Hash(), blockNum)
+	if receipt == nil {
+		return nil, nil
+	}
+	cc, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*bor.StateSyncEvent, 0)
+	for _, id := range stateSyncEventIDs(receipt, common.HexToAddress(cc.Bor.StateReceiverContract)) {
+		event, err := bor.ReadStateSyncEvent(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if event == nil || event.BlockNumber != blockNum {
+			// not indexed, only the id is known from the receipt
+			event = &bor.StateSyncEvent{EventRecordWithTime: bor.EventRecordWithTime{EventRecord: bor.EventRecord{ID: id}}, BlockNumber: blockNum}
+		}
+		events = append(events, event)
+	}
+
+	return &BlockStateSyncEvents{
+		BlockNumber:     hexutil.Uint64(blockNum),
+		BlockHash:       block.Hash(),
+		TransactionHash: receipt.TxHash,
+		Events:          events,
+		Receipt:         marshalReceipt(receipt, types.NewBorTransaction(), cc, block, receipt.TxHash),
+	}, nil
+}
+
+// stateSyncEventIDs returns ids of the events committed by the state-sync transaction of the receipt
+func stateSyncEventIDs(receipt *types.Receipt, stateReceiver common.Address) []uint64 {
+	var ids []uint64
+	for _, l := range receipt.Logs {
+		if l.Address == stateReceiver && len(l.Topics) == 2 && l.Topics[0] == stateCommittedTopic {
+			ids = append(ids, l.Topics[1].Big().Uint64())
+		}
+	}
+	return ids
+}
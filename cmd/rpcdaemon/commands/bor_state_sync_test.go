@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateSyncEventIDs(t *testing.T) {
+	stateReceiver := common.HexToAddress("0x0000000000000000000000000000000000001001")
+	committed := func(id int64) *types.Log {
+		return &types.Log{Address: stateReceiver, Topics: []common.Hash{stateCommittedTopic, common.BigToHash(big.NewInt(id))}, Data: common.LeftPadBytes([]byte{1}, 32)}
+	}
+	receipt := &types.Receipt{Logs: []*types.Log{
+		committed(7),
+		{Address: common.HexToAddress("0x1234"), Topics: []common.Hash{{1}}}, // logged by the receiver of the event
+		committed(8),
+		{Address: common.HexToAddress("0x1234"), Topics: []common.Hash{stateCommittedTopic, common.BigToHash(big.NewInt(100))}},
+	}}
+	assert.Equal(t, []uint64{7, 8}, stateSyncEventIDs(receipt, stateReceiver))
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // APIList describes the list of available RPC apis
-func APIList(db kv.RoDB, borDb kv.RoDB, eth services.ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient,
+func APIList(db kv.RoDB, borDb kv.RoDB, parliaDb kv.RoDB, eth services.ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient,
 	starknet starknet.CAIROVMClient, filters *filters.Filters, stateCache kvcache.Cache,
 	blockReader interfaces.BlockAndTxnReader, cfg httpcfg.HttpCfg) (list []rpc.API) {
 
@@ -21,6 +21,7 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth services.ApiBackend, txPool txpool.T
 	if cfg.TevmEnabled {
 		base.EnableTevmExperiment()
 	}
+	base.SetLogsLimits(cfg.LogsMaxBlockRange, cfg.LogsMaxResults)
 	ethImpl := NewEthAPI(base, db, eth, txPool, mining, cfg.Gascap)
 	erigonImpl := NewErigonAPI(base, db, eth)
 	starknetImpl := NewStarknetAPI(base, db, starknet, txPool)
@@ -33,7 +34,9 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth services.ApiBackend, txPool txpool.T
 	engineImpl := NewEngineAPI(base, db, eth)
 	adminImpl := NewAdminAPI(eth)
 	parityImpl := NewParityAPIImpl(db)
-	borImpl := NewBorAPI(base, db, borDb) // bor (consensus) specific
+	borImpl := NewBorAPI(base, db, borDb)          // bor (consensus) specific
+	parliaImpl := NewParliaAPI(base, db, parliaDb) // parlia (consensus) specific
+	graphQLImpl := NewGraphQLAPI(base, db)
 
 	for _, enabledAPI := range cfg.API {
 		switch enabledAPI {
@@ -114,6 +117,13 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth services.ApiBackend, txPool txpool.T
 				Service:   BorAPI(borImpl),
 				Version:   "1.0",
 			})
+		case "parlia":
+			list = append(list, rpc.API{
+				Namespace: "parlia",
+				Public:    true,
+				Service:   ParliaAPI(parliaImpl),
+				Version:   "1.0",
+			})
 		case "admin":
 			list = append(list, rpc.API{
 				Namespace: "admin",
@@ -130,6 +140,14 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth services.ApiBackend, txPool txpool.T
 			})
 		}
 	}
+	if cfg.GraphQLEnabled {
+		list = append(list, rpc.API{
+			Namespace: "graphql",
+			Public:    true,
+			Service:   GraphQLAPI(graphQLImpl),
+			Version:   "1.0",
+		})
+	}
 
 	return list
 }
@@ -34,7 +34,10 @@ type PrivateDebugAPI interface {
 	GetModifiedAccountsByNumber(ctx context.Context, startNum rpc.BlockNumber, endNum *rpc.BlockNumber) ([]common.Address, error)
 	GetModifiedAccountsByHash(_ context.Context, startHash common.Hash, endHash *common.Hash) ([]common.Address, error)
 	TraceCall(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, config *tracers.TraceConfig, stream *jsoniter.Stream) error
+	TraceCallMany(ctx context.Context, bundles []Bundle, simulateContext StateContext, config *tracers.TraceConfig, stream *jsoniter.Stream) error
+	TraceBlock(ctx context.Context, blockRlp hexutil.Bytes, config *tracers.TraceConfig, stream *jsoniter.Stream) error
 	AccountAt(ctx context.Context, blockHash common.Hash, txIndex uint64, account common.Address) (*AccountResult, error)
+	DumpBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, stream *jsoniter.Stream) error
 }
 
 // PrivateDebugAPIImpl is implementation of the PrivateDebugAPI interface based on remote Db access
@@ -97,32 +100,9 @@ func (api *PrivateDebugAPIImpl) AccountRange(ctx context.Context, blockNrOrHash
 	}
 	defer tx.Rollback()
 
-	var blockNumber uint64
-
-	if number, ok := blockNrOrHash.Number(); ok {
-		if number == rpc.PendingBlockNumber {
-			return state.IteratorDump{}, fmt.Errorf("accountRange for pending block not supported")
-		}
-		if number == rpc.LatestBlockNumber {
-			var err error
-
-			blockNumber, err = stages.GetStageProgress(tx, stages.Execution)
-			if err != nil {
-				return state.IteratorDump{}, fmt.Errorf("last block has not found: %w", err)
-			}
-		} else {
-			blockNumber = uint64(number)
-		}
-
-	} else if hash, ok := blockNrOrHash.Hash(); ok {
-		block, err1 := api.blockByHashWithSenders(tx, hash)
-		if err1 != nil {
-			return state.IteratorDump{}, err1
-		}
-		if block == nil {
-			return state.IteratorDump{}, fmt.Errorf("block %s not found", hash.Hex())
-		}
-		blockNumber = block.NumberU64()
+	blockNumber, err := api.dumpBlockNumber(tx, blockNrOrHash)
+	if err != nil {
+		return state.IteratorDump{}, err
 	}
 
 	if maxResults > AccountRangeMaxResults || maxResults <= 0 {
@@ -149,6 +129,94 @@ func (api *PrivateDebugAPIImpl) AccountRange(ctx context.Context, blockNrOrHash
 	return res, nil
 }
 
+// DumpBlock implements debug_dumpBlock. Streams the full state (accounts, code and storage) after the given block,
+// every account is flushed to the stream as soon as it is read.
+func (api *PrivateDebugAPIImpl) DumpBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, stream *jsoniter.Stream) error {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	defer tx.Rollback()
+
+	blockNumber, err := api.dumpBlockNumber(tx, blockNrOrHash)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	header := rawdb.ReadHeaderByNumber(tx, blockNumber)
+	if header == nil {
+		stream.WriteNil()
+		return fmt.Errorf("block %d not found", blockNumber)
+	}
+
+	stream.WriteObjectStart()
+	stream.WriteObjectField("root")
+	stream.WriteString(header.Root.String())
+	stream.WriteMore()
+	stream.WriteObjectField("accounts")
+	stream.WriteObjectStart()
+	collector := &dumpStream{stream: stream}
+	if _, err = state.NewDumper(tx, blockNumber).DumpToCollector(collector, false, false, common.Address{}, 0); err != nil {
+		return err
+	}
+	if collector.err != nil {
+		return collector.err
+	}
+	stream.WriteObjectEnd()
+	stream.WriteObjectEnd()
+	return stream.Flush()
+}
+
+// dumpStream writes the accounts of a state dump as fields of a JSON object
+type dumpStream struct {
+	stream *jsoniter.Stream
+	count  int
+	err    error
+}
+
+func (d *dumpStream) OnRoot(common.Hash) {}
+
+func (d *dumpStream) OnAccount(addr common.Address, account state.DumpAccount) {
+	if d.err != nil {
+		return
+	}
+	if d.count > 0 {
+		d.stream.WriteMore()
+	}
+	d.count++
+	d.stream.WriteObjectField(hexutil.Encode(addr[:]))
+	d.stream.WriteVal(account)
+	d.err = d.stream.Flush()
+}
+
+// dumpBlockNumber resolves the block of debug_accountRange and debug_dumpBlock and checks that its state is available
+func (api *PrivateDebugAPIImpl) dumpBlockNumber(tx kv.Tx, blockNrOrHash rpc.BlockNumberOrHash) (uint64, error) {
+	var blockNumber uint64
+	if number, ok := blockNrOrHash.Number(); ok {
+		if number == rpc.PendingBlockNumber {
+			return 0, fmt.Errorf("state dump for pending block not supported")
+		}
+		var err error
+		if blockNumber, err = getBlockNumber(number, tx); err != nil {
+			return 0, err
+		}
+	} else if hash, ok := blockNrOrHash.Hash(); ok {
+		block, err := api.blockByHashWithSenders(tx, hash)
+		if err != nil {
+			return 0, err
+		}
+		if block == nil {
+			return 0, fmt.Errorf("block %s not found", hash.Hex())
+		}
+		blockNumber = block.NumberU64()
+	}
+	if err := state.CheckDumpBlock(tx, blockNumber); err != nil {
+		return 0, err
+	}
+	return blockNumber, nil
+}
+
 // GetModifiedAccountsByNumber implements debug_getModifiedAccountsByNumber. Returns a list of accounts modified in the given block.
 func (api *PrivateDebugAPIImpl) GetModifiedAccountsByNumber(ctx context.Context, startNumber rpc.BlockNumber, endNumber *rpc.BlockNumber) ([]common.Address, error) {
 	tx, err := api.db.BeginRo(ctx)
@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"math/big"
 	"testing"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/internal/ethapi"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
 )
@@ -183,3 +189,257 @@ func TestTraceTransactionNoRefund(t *testing.T) {
 		}
 	}
 }
+
+func TestTraceBlockRlp(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewPrivateDebugAPI(
+		NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false),
+		db, 0)
+	for _, tt := range debugTraceTransactionTests {
+		tx, err := db.BeginRo(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockNum, err := rawdb.ReadTxLookupEntry(tx, common.HexToHash(tt.txHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockHash, err := rawdb.ReadCanonicalHash(tx, *blockNum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, _, err := rawdb.ReadBlockWithSenders(tx, blockHash, *blockNum)
+		tx.Rollback()
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockRlp, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected, buf bytes.Buffer
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &expected, 4096)
+		if err = api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(*blockNum), &tracers.TraceConfig{}, stream); err != nil {
+			t.Errorf("traceBlockByNumber %d: %v", *blockNum, err)
+		}
+		if err = stream.Flush(); err != nil {
+			t.Fatalf("error flusing: %v", err)
+		}
+		stream = jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+		if err = api.TraceBlock(context.Background(), blockRlp, &tracers.TraceConfig{}, stream); err != nil {
+			t.Errorf("traceBlock %d: %v", *blockNum, err)
+		}
+		if err = stream.Flush(); err != nil {
+			t.Fatalf("error flusing: %v", err)
+		}
+		if !bytes.Equal(expected.Bytes(), buf.Bytes()) {
+			t.Errorf("traces of block %d differ, got %s, expected %s", *blockNum, buf.String(), expected.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	if err := api.TraceBlock(context.Background(), hexutil.Bytes{0x01, 0x02}, &tracers.TraceConfig{}, stream); err == nil {
+		t.Errorf("expected error for invalid RLP")
+	}
+
+	// the parent is not the canonical block at its height
+	header := &types.Header{Number: big.NewInt(5), ParentHash: common.HexToHash("0x01"), Difficulty: big.NewInt(1), Extra: []byte{}}
+	blockRlp, err := rlp.EncodeToBytes(types.NewBlockWithHeader(header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.TraceBlock(context.Background(), blockRlp, &tracers.TraceConfig{}, stream); err == nil {
+		t.Errorf("expected error for block with non-canonical parent")
+	}
+}
+
+func TestTraceCallMany(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewPrivateDebugAPI(
+		NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false),
+		db, 0)
+	for _, tt := range debugTraceTransactionTests {
+		tx, err := db.BeginRo(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockNum, err := rawdb.ReadTxLookupEntry(tx, common.HexToHash(tt.txHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockHash, err := rawdb.ReadCanonicalHash(tx, *blockNum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, senders, err := rawdb.ReadBlockWithSenders(tx, blockHash, *blockNum)
+		tx.Rollback()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Transactions of the block sent as calls on top of its beginning, split into two bundles
+		var calls []ethapi.CallArgs
+		for i, txn := range block.Transactions() {
+			from := senders[i]
+			gas := hexutil.Uint64(txn.GetGas())
+			data := hexutil.Bytes(txn.GetData())
+			calls = append(calls, ethapi.CallArgs{
+				From:     &from,
+				To:       txn.GetTo(),
+				Gas:      &gas,
+				GasPrice: (*hexutil.Big)(txn.GetPrice().ToBig()),
+				Value:    (*hexutil.Big)(txn.GetValue().ToBig()),
+				Data:     &data,
+			})
+		}
+		half := len(calls) / 2
+		bundles := []Bundle{{Transactions: calls[:half]}, {Transactions: calls[half:]}}
+		txIndex := 0
+
+		var buf bytes.Buffer
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+		err = api.TraceCallMany(context.Background(), bundles, StateContext{BlockNumber: rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*blockNum)), TransactionIndex: &txIndex}, &tracers.TraceConfig{}, stream)
+		if err != nil {
+			t.Errorf("traceCallMany %d: %v", *blockNum, err)
+		}
+		if err = stream.Flush(); err != nil {
+			t.Fatalf("error flusing: %v", err)
+		}
+		var er [][]ethapi.ExecutionResult
+		if err = json.Unmarshal(buf.Bytes(), &er); err != nil {
+			t.Fatalf("parsing result: %v", err)
+		}
+		if len(er) != 2 || len(er[0]) != half || len(er[1]) != len(calls)-half {
+			t.Fatalf("incorrect length: %s", buf.String())
+		}
+		for i, txn := range block.Transactions() {
+			if txn.Hash() != common.HexToHash(tt.txHash) {
+				continue
+			}
+			result := er[0]
+			if i >= half {
+				result, i = er[1], i-half
+			}
+			if result[i].Gas != tt.gas {
+				t.Errorf("wrong gas for transaction %s, got %d, expected %d", tt.txHash, result[i].Gas, tt.gas)
+			}
+			if result[i].Failed != tt.failed {
+				t.Errorf("wrong failed flag for transaction %s, got %t, expected %t", tt.txHash, result[i].Failed, tt.failed)
+			}
+			if result[i].ReturnValue != tt.returnValue {
+				t.Errorf("wrong return value for transaction %s, got %s, expected %s", tt.txHash, result[i].ReturnValue, tt.returnValue)
+			}
+		}
+	}
+}
+
+func TestDumpBlock(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewPrivateDebugAPI(
+		NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false),
+		db, 0)
+
+	var withStorage int
+	for blockNum := uint64(0); blockNum <= 10; blockNum++ {
+		var out flushCounter
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &out, 4096)
+		if err := api.DumpBlock(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNum)), stream); err != nil {
+			t.Fatalf("dumpBlock %d: %v", blockNum, err)
+		}
+		var dump state.Dump
+		if err := json.Unmarshal(out.Bytes(), &dump); err != nil {
+			t.Fatalf("parsing dump of block %d: %v, %s", blockNum, err, out.String())
+		}
+		if out.writes < len(dump.Accounts) {
+			t.Errorf("dump of block %d is not streamed: %d writes for %d accounts", blockNum, out.writes, len(dump.Accounts))
+		}
+
+		tx, err := db.BeginRo(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dump.Root != rawdb.ReadHeaderByNumber(tx, blockNum).Root.String() {
+			t.Errorf("wrong root of block %d: %s", blockNum, dump.Root)
+		}
+
+		// Every account and storage slot of the dump is the one of the historical state after the block
+		reader := state.NewPlainState(tx, blockNum+1)
+		for addr, account := range dump.Accounts {
+			acc, err := reader.ReadAccountData(addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if acc == nil || acc.Balance.ToBig().String() != account.Balance || acc.Nonce != account.Nonce {
+				t.Errorf("wrong account %x after block %d: %+v", addr, blockNum, account)
+				continue
+			}
+			for k, v := range account.Storage {
+				key := common.HexToHash(k)
+				value, err := reader.ReadAccountStorage(addr, acc.Incarnation, &key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(value, common.FromHex(v)) {
+					t.Errorf("wrong storage %x of %x after block %d: %s, expected %x", key, addr, blockNum, v, value)
+				}
+				withStorage++
+			}
+		}
+		tx.Rollback()
+
+		_, transferred := dump.Accounts[common.HexToAddress("0x0000000000000004000000000000000000000000")]
+		if transferred != (blockNum >= 6) {
+			t.Errorf("unexpected transfer recipient in the dump of block %d", blockNum)
+		}
+	}
+	if withStorage == 0 {
+		t.Errorf("no storage dumped")
+	}
+
+	var out flushCounter
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &out, 4096)
+	if err := api.DumpBlock(context.Background(), rpc.BlockNumberOrHashWithNumber(11), stream); err == nil {
+		t.Errorf("expected error for a block which is not executed")
+	}
+}
+
+// flushCounter counts the flushes of a stream into the buffer
+type flushCounter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (f *flushCounter) Write(p []byte) (int, error) {
+	f.writes++
+	return f.Buffer.Write(p)
+}
+
+func TestAccountRangeBlockCheck(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewPrivateDebugAPI(
+		NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false),
+		db, 0)
+
+	latest, err := api.AccountRange(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil, 0, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := api.AccountRange(context.Background(), rpc.BlockNumberOrHashWithNumber(10), nil, 0, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Root != last.Root || len(latest.Accounts) != len(last.Accounts) {
+		t.Errorf("latest is not the last executed block: %s, expected %s", latest.Root, last.Root)
+	}
+
+	// accountRange used to return the state of the tip for a block which is not executed
+	if _, err = api.AccountRange(context.Background(), rpc.BlockNumberOrHashWithNumber(11), nil, 0, true, true); err == nil {
+		t.Errorf("expected error for a block which is not executed")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -36,6 +37,12 @@ type ExecutionPayload struct {
 	Transactions  []hexutil.Bytes `json:"transactions"  gencodec:"required"`
 }
 
+// ExecutionPayloadV2 is an execution payload with EIP-4895 withdrawals
+type ExecutionPayloadV2 struct {
+	ExecutionPayload
+	Withdrawals []*types.Withdrawal `json:"withdrawals"   gencodec:"required"`
+}
+
 // PayloadAttributes represent the attributes required to start assembling a payload
 type ForkChoiceState struct {
 	HeadHash           common.Hash `json:"headBlockHash"             gencodec:"required"`
@@ -50,6 +57,12 @@ type PayloadAttributes struct {
 	SuggestedFeeRecipient common.Address `json:"suggestedFeeRecipient" gencodec:"required"`
 }
 
+// PayloadAttributesV2 are payload attributes with EIP-4895 withdrawals
+type PayloadAttributesV2 struct {
+	PayloadAttributes
+	Withdrawals []*types.Withdrawal `json:"withdrawals"           gencodec:"required"`
+}
+
 // TransitionConfiguration represents the correct configurations of the CL and the EL
 type TransitionConfiguration struct {
 	TerminalTotalDifficulty *hexutil.Big `json:"terminalTotalDifficulty" gencodec:"required"`
@@ -63,6 +76,9 @@ type EngineAPI interface {
 	NewPayloadV1(context.Context, *ExecutionPayload) (map[string]interface{}, error)
 	GetPayloadV1(ctx context.Context, payloadID hexutil.Bytes) (*ExecutionPayload, error)
 	ExchangeTransitionConfigurationV1(ctx context.Context, transitionConfiguration TransitionConfiguration) (TransitionConfiguration, error)
+	ForkchoiceUpdatedV2(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributesV2) (map[string]interface{}, error)
+	NewPayloadV2(context.Context, *ExecutionPayloadV2) (map[string]interface{}, error)
+	GetPayloadV2(ctx context.Context, payloadID hexutil.Bytes) (*ExecutionPayloadV2, error)
 }
 
 // EngineImpl is implementation of the EngineAPI interface
@@ -87,6 +103,19 @@ func convertPayloadStatus(x *remote.EnginePayloadStatus) map[string]interface{}
 }
 
 func (e *EngineImpl) ForkchoiceUpdatedV1(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes) (map[string]interface{}, error) {
+	return e.forkchoiceUpdated(ctx, forkChoiceState, payloadAttributes, nil)
+}
+
+// ForkchoiceUpdatedV2 is ForkchoiceUpdatedV1 with withdrawals in the payload attributes.
+// See https://github.com/ethereum/execution-apis/blob/main/src/engine/shanghai.md#engine_forkchoiceupdatedv2
+func (e *EngineImpl) ForkchoiceUpdatedV2(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributesV2) (map[string]interface{}, error) {
+	if payloadAttributes == nil {
+		return e.forkchoiceUpdated(ctx, forkChoiceState, nil, nil)
+	}
+	return e.forkchoiceUpdated(ctx, forkChoiceState, &payloadAttributes.PayloadAttributes, payloadAttributes.Withdrawals)
+}
+
+func (e *EngineImpl) forkchoiceUpdated(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes, withdrawals []*types.Withdrawal) (map[string]interface{}, error) {
 	log.Trace("Received ForkchoiceUpdated", "head", forkChoiceState.HeadHash, "safe", forkChoiceState.HeadHash, "finalized", forkChoiceState.FinalizedBlockHash,
 		"build", payloadAttributes != nil)
 
@@ -98,14 +127,27 @@ func (e *EngineImpl) ForkchoiceUpdatedV1(ctx context.Context, forkChoiceState *F
 			SuggestedFeeRecipient: gointerfaces.ConvertAddressToH160(payloadAttributes.SuggestedFeeRecipient),
 		}
 	}
-	reply, err := e.api.EngineForkchoiceUpdatedV1(ctx, &remote.EngineForkChoiceUpdatedRequest{
-		ForkchoiceState: &remote.EngineForkChoiceState{
-			HeadBlockHash:      gointerfaces.ConvertHashToH256(forkChoiceState.HeadHash),
-			SafeBlockHash:      gointerfaces.ConvertHashToH256(forkChoiceState.SafeBlockHash),
-			FinalizedBlockHash: gointerfaces.ConvertHashToH256(forkChoiceState.FinalizedBlockHash),
-		},
-		PayloadAttributes: prepareParameters,
-	})
+	forkchoiceState := &remote.EngineForkChoiceState{
+		HeadBlockHash:      gointerfaces.ConvertHashToH256(forkChoiceState.HeadHash),
+		SafeBlockHash:      gointerfaces.ConvertHashToH256(forkChoiceState.SafeBlockHash),
+		FinalizedBlockHash: gointerfaces.ConvertHashToH256(forkChoiceState.FinalizedBlockHash),
+	}
+	var reply *remote.EngineForkChoiceUpdatedReply
+	var err error
+	if withdrawals == nil {
+		reply, err = e.api.EngineForkchoiceUpdatedV1(ctx, &remote.EngineForkChoiceUpdatedRequest{
+			ForkchoiceState:   forkchoiceState,
+			PayloadAttributes: prepareParameters,
+		})
+	} else {
+		reply, err = e.api.EngineForkchoiceUpdatedV2(ctx, &remote.EngineForkChoiceUpdatedRequestV2{
+			ForkchoiceState: forkchoiceState,
+			PayloadAttributes: &remote.EnginePayloadAttributesV2{
+				Attributes:  prepareParameters,
+				Withdrawals: engineapi.ConvertWithdrawalsToRpc(withdrawals),
+			},
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +167,16 @@ func (e *EngineImpl) ForkchoiceUpdatedV1(ctx context.Context, forkChoiceState *F
 // NewPayloadV1 processes new payloads (blocks) from the beacon chain.
 // See https://github.com/ethereum/execution-apis/blob/main/src/engine/specification.md#engine_newpayloadv1
 func (e *EngineImpl) NewPayloadV1(ctx context.Context, payload *ExecutionPayload) (map[string]interface{}, error) {
+	return e.newPayload(ctx, payload, nil)
+}
+
+// NewPayloadV2 processes new payloads (blocks) with withdrawals from the beacon chain.
+// See https://github.com/ethereum/execution-apis/blob/main/src/engine/shanghai.md#engine_newpayloadv2
+func (e *EngineImpl) NewPayloadV2(ctx context.Context, payload *ExecutionPayloadV2) (map[string]interface{}, error) {
+	return e.newPayload(ctx, &payload.ExecutionPayload, payload.Withdrawals)
+}
+
+func (e *EngineImpl) newPayload(ctx context.Context, payload *ExecutionPayload, withdrawals []*types.Withdrawal) (map[string]interface{}, error) {
 	log.Trace("Received NewPayload", "height", uint64(payload.BlockNumber), "hash", payload.BlockHash)
 
 	var baseFee *uint256.Int
@@ -142,7 +194,7 @@ func (e *EngineImpl) NewPayloadV1(ctx context.Context, payload *ExecutionPayload
 	for i, transaction := range payload.Transactions {
 		transactions[i] = ([]byte)(transaction)
 	}
-	res, err := e.api.EngineNewPayloadV1(ctx, &types2.ExecutionPayload{
+	request := &types2.ExecutionPayload{
 		ParentHash:    gointerfaces.ConvertHashToH256(payload.ParentHash),
 		Coinbase:      gointerfaces.ConvertAddressToH160(payload.FeeRecipient),
 		StateRoot:     gointerfaces.ConvertHashToH256(payload.StateRoot),
@@ -157,7 +209,17 @@ func (e *EngineImpl) NewPayloadV1(ctx context.Context, payload *ExecutionPayload
 		BaseFeePerGas: gointerfaces.ConvertUint256IntToH256(baseFee),
 		BlockHash:     gointerfaces.ConvertHashToH256(payload.BlockHash),
 		Transactions:  transactions,
-	})
+	}
+	var res *remote.EnginePayloadStatus
+	var err error
+	if withdrawals == nil {
+		res, err = e.api.EngineNewPayloadV1(ctx, request)
+	} else {
+		res, err = e.api.EngineNewPayloadV2(ctx, &types2.ExecutionPayloadV2{
+			Payload:     request,
+			Withdrawals: engineapi.ConvertWithdrawalsToRpc(withdrawals),
+		})
+	}
 	if err != nil {
 		log.Warn("NewPayload", "err", err)
 		return nil, err
@@ -174,6 +236,26 @@ func (e *EngineImpl) GetPayloadV1(ctx context.Context, payloadID hexutil.Bytes)
 	if err != nil {
 		return nil, err
 	}
+	return convertPayloadFromRpc(payload), nil
+}
+
+// GetPayloadV2 is GetPayloadV1 that also returns the withdrawals of the payload.
+// See https://github.com/ethereum/execution-apis/blob/main/src/engine/shanghai.md#engine_getpayloadv2
+func (e *EngineImpl) GetPayloadV2(ctx context.Context, payloadID hexutil.Bytes) (*ExecutionPayloadV2, error) {
+	decodedPayloadId := binary.BigEndian.Uint64(payloadID)
+	log.Info("Received GetPayloadV2", "payloadId", decodedPayloadId)
+
+	payload, err := e.api.EngineGetPayloadV2(ctx, decodedPayloadId)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutionPayloadV2{
+		ExecutionPayload: *convertPayloadFromRpc(payload.Payload),
+		Withdrawals:      engineapi.ConvertWithdrawalsFromRpc(payload.Withdrawals),
+	}, nil
+}
+
+func convertPayloadFromRpc(payload *types2.ExecutionPayload) *ExecutionPayload {
 	var bloom types.Bloom = gointerfaces.ConvertH2048ToBloom(payload.LogsBloom)
 
 	var baseFee *big.Int
@@ -201,7 +283,7 @@ func (e *EngineImpl) GetPayloadV1(ctx context.Context, payloadID hexutil.Bytes)
 		BaseFeePerGas: (*hexutil.Big)(baseFee),
 		BlockHash:     gointerfaces.ConvertH256ToHash(payload.BlockHash),
 		Transactions:  transactions,
-	}, nil
+	}
 }
 
 // Receives consensus layer's transition configuration and checks if the execution layer has the correct configuration.
@@ -3,10 +3,13 @@ package commands
 import (
 	"context"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/services"
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/p2p"
 	"github.com/ledgerwatch/erigon/rpc"
 )
@@ -15,6 +18,7 @@ import (
 type ErigonAPI interface {
 	// System related (see ./erigon_system.go)
 	Forks(ctx context.Context) (Forks, error)
+	SyncStatus(ctx context.Context) (*SyncStatus, error)
 
 	// Blocks related (see ./erigon_blocks.go)
 	GetHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
@@ -23,8 +27,12 @@ type ErigonAPI interface {
 
 	// Receipt related (see ./erigon_receipts.go)
 	GetLogsByHash(ctx context.Context, hash common.Hash) ([][]*types.Log, error)
+	GetLogs(ctx context.Context, crit filters.FilterCriteria, cursor *hexutil.Bytes, stream *jsoniter.Stream) error
 	//GetLogsByNumber(ctx context.Context, number rpc.BlockNumber) ([][]*types.Log, error)
 
+	// Address related (see ./erigon_appearances.go)
+	GetTransactionsByAddress(ctx context.Context, address common.Address, query AddressTransactionsQuery) (*AddressTransactionsPage, error)
+
 	// WatchTheBurn / reward related (see ./erigon_issuance.go)
 	WatchTheBurn(ctx context.Context, blockNr rpc.BlockNumber) (Issuance, error)
 
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/eth/appearances"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+const (
+	defaultAddressTransactionsPageSize = 25
+	maxAddressTransactionsPageSize     = 1000
+)
+
+// AddressTransactionsQuery - arguments of erigon_getTransactionsByAddress
+type AddressTransactionsQuery struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock"` // earliest by default
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`   // latest by default
+	Reverse   bool             `json:"reverse"`   // return the newest transactions first
+	PageSize  uint64           `json:"pageSize"`
+	Cursor    hexutil.Bytes    `json:"cursor"` // NextCursor of the previous page
+}
+
+// AddressTransactionsPage - result of erigon_getTransactionsByAddress
+type AddressTransactionsPage struct {
+	Transactions []*RPCTransaction `json:"transactions"`
+	NextCursor   hexutil.Bytes     `json:"nextCursor,omitempty"` // not set on the last page
+}
+
+// GetTransactionsByAddress implements erigon_getTransactionsByAddress. Returns a page of transactions in which the
+// address appeared as the sender, the recipient, in an emitted log or in an internal call.
+// Requires the AddressAppearances stage, enabled by adding `appearances` to --experiments.
+func (api *ErigonImpl) GetTransactionsByAddress(ctx context.Context, address common.Address, query AddressTransactionsQuery) (*AddressTransactionsPage, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	indexed, err := stages.GetStageProgress(tx, stages.AddressAppearances)
+	if err != nil {
+		return nil, err
+	}
+	if indexed == 0 {
+		return nil, fmt.Errorf("address appearances are not indexed, enable by adding `appearances` to --experiments")
+	}
+
+	fromBlock, toBlock := uint64(0), indexed
+	if query.FromBlock != nil {
+		if fromBlock, err = getBlockNumber(*query.FromBlock, tx); err != nil {
+			return nil, err
+		}
+	}
+	if query.ToBlock != nil {
+		if toBlock, err = getBlockNumber(*query.ToBlock, tx); err != nil {
+			return nil, err
+		}
+		if toBlock > indexed {
+			toBlock = indexed
+		}
+	}
+
+	pageSize := query.PageSize
+	if pageSize == 0 {
+		pageSize = defaultAddressTransactionsPageSize
+	}
+	if pageSize > maxAddressTransactionsPageSize {
+		return nil, fmt.Errorf("page size %d is greater than the maximum %d", pageSize, maxAddressTransactionsPageSize)
+	}
+
+	var after *appearances.Appearance
+	if len(query.Cursor) > 0 {
+		a, err := appearances.DecodeAppearance(query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		after = &a
+	}
+
+	page := &AddressTransactionsPage{Transactions: []*RPCTransaction{}}
+	if fromBlock > toBlock {
+		return page, nil
+	}
+	found, more, err := appearances.ReadAppearances(tx, address, fromBlock, toBlock, after, query.Reverse, int(pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range found {
+		block, err := api.blockByNumberWithSenders(tx, a.BlockNum)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", a.BlockNum)
+		}
+		txs := block.Transactions()
+		if int(a.TxIndex) >= len(txs) {
+			return nil, fmt.Errorf("transaction %d not found in block %d", a.TxIndex, a.BlockNum)
+		}
+		page.Transactions = append(page.Transactions, newRPCTransaction(txs[a.TxIndex], block.Hash(), a.BlockNum, uint64(a.TxIndex), block.BaseFee()))
+	}
+	if more {
+		page.NextCursor = found[len(found)-1].Encode()
+	}
+	return page, nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransactionsByAddress(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewErigonAPI(NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false), m.DB, nil)
+	ctx := context.Background()
+
+	var addr common.Address
+	binary.BigEndian.PutUint64(addr[:], 4)
+
+	_, err := api.GetTransactionsByAddress(ctx, addr, AddressTransactionsQuery{})
+	require.Error(t, err, "index is not built yet")
+
+	cfg := stagedsync.StageAddressAppearancesCfg(m.DB, prune.DefaultMode, m.ChainConfig, m.Engine, snapshotsync.NewBlockReader())
+	sync := stagedsync.New([]*stagedsync.Stage{{
+		ID: stages.AddressAppearances,
+		Forward: func(firstCycle bool, badBlockUnwind bool, s *stagedsync.StageState, u stagedsync.Unwinder, tx kv.RwTx) error {
+			return stagedsync.SpawnAddressAppearances(s, tx, cfg, ctx)
+		},
+		Unwind: func(firstCycle bool, u *stagedsync.UnwindState, s *stagedsync.StageState, tx kv.RwTx) error {
+			return stagedsync.UnwindAddressAppearances(u, s, tx, cfg, ctx)
+		},
+		Prune: func(firstCycle bool, p *stagedsync.PruneState, tx kv.RwTx) error {
+			return stagedsync.PruneAddressAppearances(p, tx, cfg, ctx)
+		},
+	}}, stagedsync.UnwindOrder{stages.AddressAppearances}, stagedsync.PruneOrder{stages.AddressAppearances})
+	require.NoError(t, sync.Run(m.DB, nil, true))
+
+	// The address receives ether in blocks 6 and 8, token transfers to it in block 7 are not visible (no logs)
+	page, err := api.GetTransactionsByAddress(ctx, addr, AddressTransactionsQuery{})
+	require.NoError(t, err)
+	require.Nil(t, page.NextCursor)
+	type position struct{ block, index uint64 }
+	var all []position
+	for _, txn := range page.Transactions {
+		all = append(all, position{txn.BlockNumber.ToInt().Uint64(), uint64(*txn.TransactionIndex)})
+	}
+	require.Equal(t, []position{{6, 3}, {8, 0}}, all)
+	require.Equal(t, addr, *page.Transactions[0].To)
+
+	// pages from the newest one
+	page, err = api.GetTransactionsByAddress(ctx, addr, AddressTransactionsQuery{Reverse: true, PageSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(page.Transactions))
+	require.Equal(t, uint64(8), page.Transactions[0].BlockNumber.ToInt().Uint64())
+	require.NotNil(t, page.NextCursor)
+	page, err = api.GetTransactionsByAddress(ctx, addr, AddressTransactionsQuery{Reverse: true, PageSize: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Nil(t, page.NextCursor)
+	require.Equal(t, 1, len(page.Transactions))
+	require.Equal(t, uint64(6), page.Transactions[0].BlockNumber.ToInt().Uint64())
+
+	// Contract deployed by Poly.deployAndDestruct in block 10 self-destructs to the address equal to the block number
+	beneficiary := common.BigToAddress(big.NewInt(10))
+	page, err = api.GetTransactionsByAddress(ctx, beneficiary, AddressTransactionsQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(page.Transactions))
+	require.Equal(t, uint64(10), page.Transactions[0].BlockNumber.ToInt().Uint64())
+	require.NotEqual(t, beneficiary, *page.Transactions[0].To)
+
+	// block range
+	fromBlock, toBlock := rpc.BlockNumber(7), rpc.BlockNumber(9)
+	page, err = api.GetTransactionsByAddress(ctx, addr, AddressTransactionsQuery{FromBlock: &fromBlock, ToBlock: &toBlock})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(page.Transactions))
+	require.Equal(t, uint64(8), page.Transactions[0].BlockNumber.ToInt().Uint64())
+
+	_, err = api.GetTransactionsByAddress(ctx, addr, AddressTransactionsQuery{PageSize: maxAddressTransactionsPageSize + 1})
+	require.Error(t, err)
+}
@@ -54,16 +54,14 @@ func (api *ErigonImpl) WatchTheBurn(ctx context.Context, blockNr rpc.BlockNumber
 	if err != nil {
 		return Issuance{}, err
 	}
-	header := rawdb.ReadHeader(tx, hash, uint64(blockNr))
-	if header == nil {
-		return Issuance{}, fmt.Errorf("could not find block header")
+	block, err := api.blockWithSenders(tx, hash, uint64(blockNr))
+	if err != nil {
+		return Issuance{}, err
 	}
-
-	body := rawdb.ReadCanonicalBodyWithTransactions(tx, hash, uint64(blockNr))
-
-	if body == nil {
-		return Issuance{}, fmt.Errorf("could not find block body")
+	if block == nil {
+		return Issuance{}, fmt.Errorf("could not find block")
 	}
+	header, body := block.Header(), block.Body()
 
 	minerReward, uncleRewards := ethash.AccumulateRewards(chainConfig, header, body.Uncles)
 	issuance := minerReward
@@ -102,7 +100,7 @@ func (api *ErigonImpl) WatchTheBurn(ctx context.Context, blockNr rpc.BlockNumber
 	tips := big.NewInt(0)
 
 	if header.BaseFee != nil {
-		receipts, err := rawdb.ReadReceiptsByHash(tx, hash)
+		receipts, err := api.getReceipts(ctx, tx, chainConfig, block, body.SendersFromTxs())
 		if err != nil {
 			return Issuance{}, err
 		}
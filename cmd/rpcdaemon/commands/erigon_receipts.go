@@ -2,12 +2,42 @@ package commands
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 )
 
+// defaultLogsPageSize - size of erigon_getLogs pages when --rpc.logs.maxresults is not set
+const defaultLogsPageSize = 10_000
+
+// logsCursor - position of the first log of the next erigon_getLogs page
+type logsCursor struct {
+	blockNum uint64
+	logIndex uint32
+}
+
+func (c logsCursor) encode() hexutil.Bytes {
+	v := make([]byte, 8+4)
+	binary.BigEndian.PutUint64(v, c.blockNum)
+	binary.BigEndian.PutUint32(v[8:], c.logIndex)
+	return v
+}
+
+func decodeLogsCursor(v []byte) (logsCursor, error) {
+	if len(v) != 8+4 {
+		return logsCursor{}, fmt.Errorf("invalid cursor: %x", v)
+	}
+	return logsCursor{blockNum: binary.BigEndian.Uint64(v), logIndex: binary.BigEndian.Uint32(v[8:])}, nil
+}
+
+var errLogsPageFull = errors.New("page is full")
+
 // GetLogsByHash implements erigon_getLogsByHash. Returns an array of arrays of logs generated by the transactions in the block given by the block's hash.
 func (api *ErigonImpl) GetLogsByHash(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
 	tx, err := api.db.BeginRo(ctx)
@@ -28,7 +58,7 @@ func (api *ErigonImpl) GetLogsByHash(ctx context.Context, hash common.Hash) ([][
 	if block == nil {
 		return nil, nil
 	}
-	receipts, err := getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
+	receipts, err := api.getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %w", err)
 	}
@@ -40,6 +70,83 @@ func (api *ErigonImpl) GetLogsByHash(ctx context.Context, hash common.Hash) ([][
 	return logs, nil
 }
 
+// GetLogs implements erigon_getLogs. Returns a page of logs matching a given filter object, along with the cursor
+// to pass to get the next page: {"logs": [...], "cursor": "0x..."}. The cursor is null on the last page.
+// Pages are limited by --rpc.logs.maxresults, the block range of each page by --rpc.logs.maxrange.
+func (api *ErigonImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria, cursor *hexutil.Bytes, stream *jsoniter.Stream) error {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	defer tx.Rollback()
+
+	begin, end, err := api.logsBlockRange(tx, crit)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	var from logsCursor
+	if cursor != nil {
+		if from, err = decodeLogsCursor(*cursor); err != nil {
+			stream.WriteNil()
+			return err
+		}
+		if from.blockNum < begin || from.blockNum > end {
+			stream.WriteNil()
+			return fmt.Errorf("cursor block %d is out of the range %d-%d", from.blockNum, begin, end)
+		}
+		begin = from.blockNum
+	}
+	// Each page is limited separately, so that wide ranges can be paged through as well
+	pageEnd := end
+	if api.LogsMaxBlockRange > 0 && end-begin >= api.LogsMaxBlockRange {
+		pageEnd = begin + api.LogsMaxBlockRange - 1
+	}
+	pageSize := api.LogsMaxResults
+	if pageSize == 0 {
+		pageSize = defaultLogsPageSize
+	}
+
+	stream.WriteObjectStart()
+	stream.WriteObjectField("logs")
+	stream.WriteArrayStart()
+	var count uint64
+	var next *logsCursor
+	err = api.forEachLog(ctx, tx, crit, begin, pageEnd, func(log *types.Log) error {
+		if log.BlockNumber == from.blockNum && uint32(log.Index) < from.logIndex {
+			return nil
+		}
+		if count == pageSize {
+			next = &logsCursor{blockNum: log.BlockNumber, logIndex: uint32(log.Index)}
+			return errLogsPageFull
+		}
+		if count > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteVal(log)
+		count++
+		return stream.Flush()
+	})
+	stream.WriteArrayEnd()
+	if err != nil && !errors.Is(err, errLogsPageFull) {
+		stream.WriteObjectEnd()
+		return err
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("cursor")
+	if next == nil && pageEnd < end {
+		next = &logsCursor{blockNum: pageEnd + 1}
+	}
+	if next != nil {
+		stream.WriteVal(next.encode())
+	} else {
+		stream.WriteNil()
+	}
+	stream.WriteObjectEnd()
+	return stream.Flush()
+}
+
 // GetLogsByNumber implements erigon_getLogsByHash. Returns all the logs that appear in a block given the block's hash.
 // func (api *ErigonImpl) GetLogsByNumber(ctx context.Context, number rpc.BlockNumber) ([][]*types.Log, error) {
 // 	tx, err := api.db.Begin(ctx, false)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/forkid"
 )
 
@@ -29,3 +30,68 @@ func (api *ErigonImpl) Forks(ctx context.Context) (Forks, error) {
 
 	return Forks{genesis.Hash(), forksBlocks}, nil
 }
+
+// SyncStatus is a data type returned by erigon_syncStatus. Durations are in seconds.
+type SyncStatus struct {
+	CurrentBlock hexutil.Uint64    `json:"currentBlock"`
+	HighestBlock hexutil.Uint64    `json:"highestBlock"`
+	CurrentStage string            `json:"currentStage"`
+	Stages       []StageSyncStatus `json:"stages"`
+	Throughput   SyncThroughput    `json:"throughput"`
+	LastCycle    []StageTiming     `json:"lastCycle"`
+	ETA          float64           `json:"eta"`
+}
+
+type StageSyncStatus struct {
+	Stage         string         `json:"stage"`
+	Progress      hexutil.Uint64 `json:"progress"`
+	PruneProgress hexutil.Uint64 `json:"pruneProgress"`
+}
+
+// SyncThroughput is speed of Execution stage, measuredAt is unix timestamp (0 - not measured yet)
+type SyncThroughput struct {
+	Block           hexutil.Uint64 `json:"block"`
+	BlocksPerSecond float64        `json:"blocksPerSecond"`
+	TxsPerSecond    float64        `json:"txsPerSecond"`
+	GasPerSecond    float64        `json:"gasPerSecond"`
+	MeasuredAt      hexutil.Uint64 `json:"measuredAt"`
+}
+
+type StageTiming struct {
+	Stage  string  `json:"stage"`
+	Unwind bool    `json:"unwind,omitempty"`
+	Prune  bool    `json:"prune,omitempty"`
+	Took   float64 `json:"took"`
+}
+
+// SyncStatus implements erigon_syncStatus. Returns progress of all stages, recent Execution throughput,
+// timings of last sync cycle and estimated time to reach highest known block
+func (api *ErigonImpl) SyncStatus(ctx context.Context) (*SyncStatus, error) {
+	status, err := api.ethBackend.SyncStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SyncStatus{
+		CurrentBlock: hexutil.Uint64(status.CurrentBlock),
+		HighestBlock: hexutil.Uint64(status.HighestBlock),
+		CurrentStage: status.CurrentStage,
+		Stages:       make([]StageSyncStatus, len(status.Stages)),
+		Throughput: SyncThroughput{
+			Block:           hexutil.Uint64(status.Throughput.GetBlock()),
+			BlocksPerSecond: status.Throughput.GetBlocksPerSecond(),
+			TxsPerSecond:    status.Throughput.GetTxsPerSecond(),
+			GasPerSecond:    status.Throughput.GetGasPerSecond(),
+			MeasuredAt:      hexutil.Uint64(status.Throughput.GetMeasuredAt()),
+		},
+		LastCycle: make([]StageTiming, len(status.LastCycle)),
+		ETA:       status.Eta,
+	}
+	for i, s := range status.Stages {
+		res.Stages[i] = StageSyncStatus{Stage: s.Stage, Progress: hexutil.Uint64(s.Progress), PruneProgress: hexutil.Uint64(s.PruneProgress)}
+	}
+	for i, t := range status.LastCycle {
+		res.LastCycle[i] = StageTiming{Stage: t.Stage, Unwind: t.Unwind, Prune: t.Prune, Took: t.Took}
+	}
+	return res, nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/services"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core"
+	stages2 "github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncStatus(t *testing.T) {
+	m, require := stages.Mock(t), require.New(t)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 5, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+	}, false /* intermediateHashes */)
+	require.NoError(err)
+	require.NoError(m.InsertChain(chain))
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, m)
+	backend := services.NewRemoteBackend(remote.NewETHBACKENDClient(conn), m.DB, snapshotsync.NewBlockReader())
+	api := NewErigonAPI(NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), snapshotsync.NewBlockReader(), false), m.DB, backend)
+
+	status, err := api.SyncStatus(ctx)
+	require.NoError(err)
+	require.Equal(hexutil.Uint64(5), status.CurrentBlock)
+	require.Equal(hexutil.Uint64(5), status.HighestBlock)
+	require.Equal(len(stages2.AllStages), len(status.Stages))
+	for _, s := range status.Stages {
+		if s.Stage == string(stages2.Execution) {
+			require.Equal(hexutil.Uint64(5), s.Progress)
+		}
+	}
+	require.Zero(status.ETA) // synced
+}
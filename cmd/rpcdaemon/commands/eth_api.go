@@ -8,6 +8,7 @@ import (
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
@@ -44,7 +45,7 @@ type EthAPI interface {
 
 	// Receipt related (see ./eth_receipts.go)
 	GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error)
-	GetLogs(ctx context.Context, crit ethFilters.FilterCriteria) ([]*types.Log, error)
+	GetLogs(ctx context.Context, crit ethFilters.FilterCriteria, stream *jsoniter.Stream) error
 	GetBlockReceipts(ctx context.Context, number rpc.BlockNumber) ([]map[string]interface{}, error)
 
 	// Uncle related (see ./eth_uncles.go)
@@ -81,8 +82,9 @@ type EthAPI interface {
 	SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
 	Sign(ctx context.Context, _ common.Address, _ hexutil.Bytes) (hexutil.Bytes, error)
 	SignTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
-	GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*interface{}, error)
+	GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*ethapi.AccountResult, error)
 	CreateAccessList(ctx context.Context, args ethapi.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash, optimizeGas *bool) (*accessListResult, error)
+	SimulateV1(ctx context.Context, opts SimulationOptions, blockNrOrHash *rpc.BlockNumberOrHash) ([]SimulatedBlockResult, error) // see ./eth_simulate.go
 
 	// Mining related (see ./eth_mining.go)
 	Coinbase(ctx context.Context) (common.Address, error)
@@ -101,9 +103,13 @@ type BaseAPI struct {
 	_genesis     *types.Block
 	_genesisLock sync.RWMutex
 
-	_blockReader interfaces.BlockReader
-	_txnReader   interfaces.TxnReader
-	TevmEnabled  bool // experiment
+	_blockReader    interfaces.BlockReader
+	_txnReader      interfaces.TxnReader
+	_receiptsReader interfaces.ReceiptsReader
+	TevmEnabled     bool // experiment
+
+	LogsMaxBlockRange uint64 // maximum amount of blocks eth_getLogs may query, 0 - no limit
+	LogsMaxResults    uint64 // maximum amount of logs eth_getLogs may return, 0 - no limit
 }
 
 func NewBaseApi(f *filters.Filters, stateCache kvcache.Cache, blockReader interfaces.BlockAndTxnReader, singleNodeMode bool) *BaseAPI {
@@ -116,7 +122,7 @@ func NewBaseApi(f *filters.Filters, stateCache kvcache.Cache, blockReader interf
 		panic(err)
 	}
 
-	return &BaseAPI{filters: f, stateCache: stateCache, blocksLRU: blocksLRU, _blockReader: blockReader, _txnReader: blockReader}
+	return &BaseAPI{filters: f, stateCache: stateCache, blocksLRU: blocksLRU, _blockReader: blockReader, _txnReader: blockReader, _receiptsReader: blockReader}
 }
 
 func (api *BaseAPI) chainConfig(tx kv.Tx) (*params.ChainConfig, error) {
@@ -126,6 +132,11 @@ func (api *BaseAPI) chainConfig(tx kv.Tx) (*params.ChainConfig, error) {
 
 func (api *BaseAPI) EnableTevmExperiment() { api.TevmEnabled = true }
 
+func (api *BaseAPI) SetLogsLimits(maxBlockRange, maxResults uint64) {
+	api.LogsMaxBlockRange = maxBlockRange
+	api.LogsMaxResults = maxResults
+}
+
 // nolint:unused
 func (api *BaseAPI) genesis(tx kv.Tx) (*types.Block, error) {
 	_, genesis, err := api.chainConfigWithGenesis(tx)
@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/internal/ethapi"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/ledgerwatch/erigon/common"
@@ -217,3 +221,47 @@ func TestCall_ByBlockHash_WithRequireCanonicalTrue_NonCanonicalBlock(t *testing.
 		t.Error("error expected")
 	}
 }
+
+func TestSafeAndFinalizedBlockTags(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false), db, nil, nil, nil, 5000000)
+	addr := common.HexToAddress("0x0000000000000004000000000000000000000000")
+	ctx := context.Background()
+
+	// No fork choice has been received before the merge
+	_, err := api.GetBlockByNumber(ctx, rpc.FinalizedBlockNumber, false)
+	require.Error(t, err)
+	_, err = api.GetBalance(ctx, addr, rpc.BlockNumberOrHashWithNumber(rpc.SafeBlockNumber))
+	require.Error(t, err)
+
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		finalized, err := rawdb.ReadCanonicalHash(tx, 5)
+		if err != nil {
+			return err
+		}
+		safe, err := rawdb.ReadCanonicalHash(tx, 6)
+		if err != nil {
+			return err
+		}
+		if err = rawdb.WriteForkchoiceFinalized(tx, finalized); err != nil {
+			return err
+		}
+		return rawdb.WriteForkchoiceSafe(tx, safe)
+	}))
+
+	block, err := api.GetBlockByNumber(ctx, rpc.FinalizedBlockNumber, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), block["number"].(*hexutil.Big).ToInt().Uint64())
+	block, err = api.GetBlockByNumber(ctx, rpc.SafeBlockNumber, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), block["number"].(*hexutil.Big).ToInt().Uint64())
+
+	// Block 6 transfers ether to addr
+	balance, err := api.GetBalance(ctx, addr, rpc.BlockNumberOrHashWithNumber(rpc.FinalizedBlockNumber))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), balance.ToInt().Uint64())
+	balance, err = api.GetBalance(ctx, addr, rpc.BlockNumberOrHashWithNumber(rpc.SafeBlockNumber))
+	require.NoError(t, err)
+	require.NotEqual(t, uint64(0), balance.ToInt().Uint64())
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/eth/tracers/logger"
 	"github.com/ledgerwatch/erigon/ethdb"
 	"github.com/ledgerwatch/erigon/internal/ethapi"
@@ -283,10 +284,36 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi.CallArgs,
 	return hexutil.Uint64(hi), nil
 }
 
-// GetProof not implemented
-func (api *APIImpl) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*interface{}, error) {
-	var stub interface{}
-	return &stub, fmt.Errorf(NotImplemented, "eth_getProof")
+// maxGetProofRewindBlockCount - how deep in history eth_getProof can rewind hashed state, rewind is done in memory
+const maxGetProofRewindBlockCount = 100_000
+
+// GetProof implements eth_getProof (EIP-1186). Returns account and storage values of the specified account including the Merkle-proof.
+func (api *APIImpl) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*ethapi.AccountResult, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNr, hash, _, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	header := rawdb.ReadHeader(tx, hash, blockNr)
+	if header == nil {
+		return nil, fmt.Errorf("block %d(%x) not found", blockNr, hash)
+	}
+	latestBlock, err := stages.GetStageProgress(tx, stages.IntermediateHashes)
+	if err != nil {
+		return nil, err
+	}
+	if blockNr > latestBlock {
+		return nil, fmt.Errorf("block %d is not processed yet, latest block with state root: %d", blockNr, latestBlock)
+	}
+	if latestBlock-blockNr > maxGetProofRewindBlockCount {
+		return nil, fmt.Errorf("requested block is too old, block must be within %d blocks of the head block number (currently %d)", maxGetProofRewindBlockCount, latestBlock)
+	}
+	return ethapi.GetProof(tx, address, storageKeys, blockNr, latestBlock, header.Root, ctx.Done())
 }
 
 // accessListResult returns an optional accesslist
@@ -1,17 +1,24 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/changeset"
 	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/internal/ethapi"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/trie"
 )
 
 func TestEstimateGas(t *testing.T) {
@@ -249,3 +256,105 @@ func TestGetBlockByTimestamp(t *testing.T) {
 		t.Errorf("Retrieved the wrong block.\nexpected block hash: %s expected timestamp: %d\nblock hash retrieved: %s timestamp retrieved: %d", response["hash"], response["timestamp"], block["hash"], block["timestamp"])
 	}
 }
+
+func TestGetProof(t *testing.T) {
+	ctx := context.Background()
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false), db, nil, nil, nil, 5000000)
+
+	// all accounts and storage slots which ever existed in test chain
+	storageKeys := map[common.Address][]string{}
+	var latest uint64
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		seen := map[string]struct{}{}
+		collect := func(k []byte) {
+			addr := common.BytesToAddress(k[:common.AddressLength])
+			if _, ok := storageKeys[addr]; !ok {
+				storageKeys[addr] = []string{}
+			}
+			if _, ok := seen[string(k)]; ok || len(k) == common.AddressLength {
+				return
+			}
+			seen[string(k)] = struct{}{}
+			storageKeys[addr] = append(storageKeys[addr], common.BytesToHash(k[common.AddressLength+common.IncarnationLength:]).Hex())
+		}
+		if err := tx.ForEach(kv.PlainState, nil, func(k, _ []byte) error {
+			collect(k)
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, bucket := range []string{kv.AccountChangeSet, kv.StorageChangeSet} {
+			if err := changeset.ForEach(tx, bucket, nil, func(_ uint64, k, _ []byte) error {
+				collect(k)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		latest = rawdb.ReadCurrentHeader(tx).Number.Uint64()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for blockNr := uint64(0); blockNr <= latest; blockNr++ {
+		for addr, keys := range storageKeys {
+			result, err := api.GetProof(ctx, addr, keys, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNr)))
+			if err != nil {
+				t.Fatalf("block %d, address %x: %v", blockNr, addr, err)
+			}
+			if err = db.View(ctx, func(tx kv.Tx) error {
+				verifyProof(t, rawdb.ReadHeaderByNumber(tx, blockNr).Root, result.AccountProof)
+				reader := state.NewPlainState(tx, blockNr+1)
+				acc, err := reader.ReadAccountData(addr)
+				if err != nil {
+					return err
+				}
+				if acc == nil {
+					if result.Balance.ToInt().Sign() != 0 || result.Nonce != 0 || result.StorageHash != trie.EmptyRoot {
+						t.Errorf("block %d, address %x: expected empty account, got %+v", blockNr, addr, result)
+					}
+					return nil
+				}
+				if result.Balance.ToInt().Cmp(acc.Balance.ToBig()) != 0 || uint64(result.Nonce) != acc.Nonce || result.CodeHash != acc.CodeHash {
+					t.Errorf("block %d, address %x: account mismatch, got %+v", blockNr, addr, result)
+				}
+				for _, storageProof := range result.StorageProof {
+					if len(storageProof.Proof) > 0 {
+						verifyProof(t, result.StorageHash, storageProof.Proof)
+					}
+					key := common.HexToHash(storageProof.Key)
+					v, err := reader.ReadAccountStorage(addr, acc.Incarnation, &key)
+					if err != nil {
+						return err
+					}
+					if storageProof.Value.ToInt().Cmp(new(big.Int).SetBytes(v)) != 0 {
+						t.Errorf("block %d, address %x, key %s: expected value %x, got %s", blockNr, addr, storageProof.Key, v, storageProof.Value)
+					}
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// verifyProof - checks that proof starts from root and every node is referenced by previous one
+func verifyProof(t *testing.T, root common.Hash, proof []string) {
+	t.Helper()
+	expected := root[:]
+	for i, encoded := range proof {
+		node := common.FromHex(encoded)
+		if i > 0 && len(node) < common.HashLength {
+			if !bytes.Contains(expected, node) {
+				t.Fatalf("embedded proof node %d is not referenced by its parent", i)
+			}
+		} else if !bytes.Contains(expected, crypto.Keccak256(node)) {
+			t.Fatalf("proof node %d is not referenced by its parent", i)
+		}
+		expected = node
+	}
+}
@@ -1,18 +1,16 @@
 package commands
 
 import (
-	"bytes"
 	"context"
-	"encoding/binary"
 	"fmt"
 	"math/big"
 
 	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/kv"
 
 	"github.com/RoaringBitmap/roaring"
 	"github.com/ledgerwatch/erigon/common"
-	"github.com/ledgerwatch/erigon/common/dbutils"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/core"
@@ -23,15 +21,25 @@ import (
 	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/ethdb"
 	"github.com/ledgerwatch/erigon/ethdb/bitmapdb"
-	"github.com/ledgerwatch/erigon/ethdb/cbor"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/transactions"
+	"github.com/ledgerwatch/log/v3"
 )
 
-func getReceipts(ctx context.Context, tx kv.Tx, chainConfig *params.ChainConfig, block *types.Block, senders []common.Address) (types.Receipts, error) {
-	if cached := rawdb.ReadReceipts(tx, block, senders); cached != nil {
-		return cached, nil
+// getReceipts reads receipts of the block from snapshots or db, re-executes the block if they are not stored
+func (api *BaseAPI) getReceipts(ctx context.Context, tx kv.Tx, chainConfig *params.ChainConfig, block *types.Block, senders []common.Address) (types.Receipts, error) {
+	stored, err := api._receiptsReader.RawReceipts(ctx, tx, block.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		block.SendersToTxs(senders)
+		if err = stored.DeriveFields(block.Hash(), block.NumberU64(), block.Transactions(), senders); err == nil {
+			return stored, nil
+		}
+		log.Warn("Failed to derive block receipts fields, re-executing the block", "hash", block.Hash(), "number", block.NumberU64(), "err", err)
 	}
 
 	getHeader := func(hash common.Hash, number uint64) *types.Header {
@@ -64,58 +72,135 @@ func getReceipts(ctx context.Context, tx kv.Tx, chainConfig *params.ChainConfig,
 	return receipts, nil
 }
 
-// GetLogs implements eth_getLogs. Returns an array of logs matching a given filter object.
-func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([]*types.Log, error) {
-	var begin, end uint64
-	logs := []*types.Log{}
+// LogsLimitError is returned by eth_getLogs when the query exceeds the limits configured by
+// --rpc.logs.maxrange and --rpc.logs.maxresults
+type LogsLimitError struct {
+	msg     string
+	toBlock *hexutil.Uint64 // the query narrowed to this block fits the limits
+}
 
+func (e *LogsLimitError) Error() string  { return e.msg }
+func (e *LogsLimitError) ErrorCode() int { return -32005 } // limit exceeded
+func (e *LogsLimitError) ErrorData() interface{} {
+	if e.toBlock == nil {
+		return nil
+	}
+	return map[string]interface{}{"toBlock": e.toBlock}
+}
+
+// GetLogs implements eth_getLogs. Returns an array of logs matching a given filter object.
+// Queries exceeding the maximum amount of results fail, data of the error suggests the block range which fits,
+// erigon_getLogs returns the logs page by page instead.
+func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria, stream *jsoniter.Stream) error {
 	tx, beginErr := api.db.BeginRo(ctx)
 	if beginErr != nil {
-		return logs, beginErr
+		stream.WriteNil()
+		return beginErr
 	}
 	defer tx.Rollback()
 
+	begin, end, err := api.logsBlockRange(tx, crit)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	if err = api.checkLogsBlockRange(begin, end); err != nil {
+		stream.WriteNil()
+		return err
+	}
+
+	stream.WriteArrayStart()
+	var count uint64
+	err = api.forEachLog(ctx, tx, crit, begin, end, func(log *types.Log) error {
+		if api.LogsMaxResults > 0 && count == api.LogsMaxResults {
+			limitErr := &LogsLimitError{msg: fmt.Sprintf("query returned more than %d results", api.LogsMaxResults)}
+			if log.BlockNumber > begin {
+				toBlock := hexutil.Uint64(log.BlockNumber - 1)
+				limitErr.toBlock = &toBlock
+			}
+			return limitErr
+		}
+		if count > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteVal(log)
+		count++
+		return stream.Flush()
+	})
+	stream.WriteArrayEnd()
+	if err != nil {
+		return err
+	}
+	return stream.Flush()
+}
+
+// logsBlockRange returns the range of blocks (inclusive) given by the filter
+func (api *BaseAPI) logsBlockRange(tx kv.Tx, crit filters.FilterCriteria) (begin, end uint64, err error) {
 	if crit.BlockHash != nil {
 		number := rawdb.ReadHeaderNumber(tx, *crit.BlockHash)
 		if number == nil {
-			return nil, fmt.Errorf("block not found: %x", *crit.BlockHash)
-		}
-		begin = *number
-		end = *number
-	} else {
-		// Convert the RPC block numbers into internal representations
-		latest, err := getLatestBlockNumber(tx)
-		if err != nil {
-			return nil, err
+			return 0, 0, fmt.Errorf("block not found: %x", *crit.BlockHash)
 		}
+		return *number, *number, nil
+	}
+	// Convert the RPC block numbers into internal representations
+	latest, err := getLatestBlockNumber(tx)
+	if err != nil {
+		return 0, 0, err
+	}
 
-		begin = latest
-		if crit.FromBlock != nil {
-			if crit.FromBlock.Sign() >= 0 {
-				begin = crit.FromBlock.Uint64()
-			} else if !crit.FromBlock.IsInt64() || crit.FromBlock.Int64() != int64(rpc.LatestBlockNumber) {
-				return nil, fmt.Errorf("negative value for FromBlock: %v", crit.FromBlock)
-			}
+	begin = latest
+	if crit.FromBlock != nil {
+		if begin, err = logsBlockNumber(tx, crit.FromBlock, latest); err != nil {
+			return 0, 0, fmt.Errorf("FromBlock: %w", err)
 		}
-		end = latest
-		if crit.ToBlock != nil {
-			if crit.ToBlock.Sign() >= 0 {
-				end = crit.ToBlock.Uint64()
-			} else if !crit.ToBlock.IsInt64() || crit.ToBlock.Int64() != int64(rpc.LatestBlockNumber) {
-				return nil, fmt.Errorf("negative value for ToBlock: %v", crit.ToBlock)
-			}
+	}
+	end = latest
+	if crit.ToBlock != nil {
+		if end, err = logsBlockNumber(tx, crit.ToBlock, latest); err != nil {
+			return 0, 0, fmt.Errorf("ToBlock: %w", err)
 		}
 	}
 	if end < begin {
-		return nil, fmt.Errorf("end (%d) < begin (%d)", end, begin)
+		return 0, 0, fmt.Errorf("end (%d) < begin (%d)", end, begin)
 	}
+	return begin, end, nil
+}
 
+// logsBlockNumber resolves FromBlock or ToBlock of the filter, negative values are block tags
+func logsBlockNumber(tx kv.Tx, number *big.Int, latest uint64) (uint64, error) {
+	if number.Sign() >= 0 {
+		return number.Uint64(), nil
+	}
+	if number.IsInt64() {
+		switch rpc.BlockNumber(number.Int64()) {
+		case rpc.LatestBlockNumber:
+			return latest, nil
+		case rpc.SafeBlockNumber:
+			return rpchelper.GetSafeBlockNumber(tx)
+		case rpc.FinalizedBlockNumber:
+			return rpchelper.GetFinalizedBlockNumber(tx)
+		}
+	}
+	return 0, fmt.Errorf("negative value %v", number)
+}
+
+func (api *BaseAPI) checkLogsBlockRange(begin, end uint64) error {
+	if api.LogsMaxBlockRange > 0 && end-begin >= api.LogsMaxBlockRange {
+		toBlock := hexutil.Uint64(begin + api.LogsMaxBlockRange - 1)
+		return &LogsLimitError{msg: fmt.Sprintf("block range %d is greater than the maximum %d", end-begin+1, api.LogsMaxBlockRange), toBlock: &toBlock}
+	}
+	return nil
+}
+
+// forEachLog calls fn for every log matching the filter in blocks from begin to end (inclusive), in order
+func (api *BaseAPI) forEachLog(ctx context.Context, tx kv.Tx, crit filters.FilterCriteria, begin, end uint64, fn func(log *types.Log) error) error {
 	blockNumbers := roaring.New()
 	blockNumbers.AddRange(begin, end+1) // [min,max)
 
 	topicsBitmap, err := getTopicsBitmap(tx, crit.Topics, uint32(begin), uint32(end))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if topicsBitmap != nil {
 		blockNumbers.And(topicsBitmap)
@@ -125,7 +210,7 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([
 	for _, addr := range crit.Addresses {
 		m, err := bitmapdb.Get(tx, kv.LogAddressIndex, addr[:], uint32(begin), uint32(end))
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if addrBitmap == nil {
 			addrBitmap = m
@@ -139,41 +224,32 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([
 	}
 
 	if blockNumbers.GetCardinality() == 0 {
-		return logs, nil
+		return nil
 	}
 
 	iter := blockNumbers.Iterator()
 	for iter.HasNext() {
 		if err = ctx.Err(); err != nil {
-			return nil, err
+			return err
 		}
 
 		block := uint64(iter.Next())
+		receipts, err := api._receiptsReader.RawReceipts(ctx, tx, block)
+		if err != nil {
+			return err
+		}
 		var logIndex uint
 		var blockLogs []*types.Log
-		err := tx.ForPrefix(kv.Log, dbutils.EncodeBlockNumber(block), func(k, v []byte) error {
-			var logs types.Logs
-			if err := cbor.Unmarshal(&logs, bytes.NewReader(v)); err != nil {
-				return fmt.Errorf("receipt unmarshal failed:  %w", err)
-			}
-			for _, log := range logs {
+		for txIndex, receipt := range receipts {
+			for _, log := range receipt.Logs {
 				log.Index = logIndex
 				logIndex++
 			}
-			filtered := filterLogs(logs, crit.Addresses, crit.Topics)
-			if len(filtered) == 0 {
-				return nil
-			}
-			txIndex := uint(binary.BigEndian.Uint32(k[8:]))
+			filtered := filterLogs(receipt.Logs, crit.Addresses, crit.Topics)
 			for _, log := range filtered {
-				log.TxIndex = txIndex
+				log.TxIndex = uint(txIndex)
 			}
 			blockLogs = append(blockLogs, filtered...)
-
-			return nil
-		})
-		if err != nil {
-			return logs, err
 		}
 		if len(blockLogs) == 0 {
 			continue
@@ -181,21 +257,23 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([
 
 		b, err := api.blockByNumberWithSenders(tx, block)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if b == nil {
-			return nil, fmt.Errorf("block not found %d", block)
+			return fmt.Errorf("block not found %d", block)
 		}
 		blockHash := b.Hash()
 		for _, log := range blockLogs {
 			log.BlockNumber = block
 			log.BlockHash = blockHash
 			log.TxHash = b.Transactions()[log.TxIndex].Hash()
+			if err = fn(log); err != nil {
+				return err
+			}
 		}
-		logs = append(logs, blockLogs...)
 	}
 
-	return logs, nil
+	return nil
 }
 
 // The Topic list restricts matches to particular event topics. Each event has a list
@@ -255,26 +333,21 @@ func (api *APIImpl) GetTransactionReceipt(ctx context.Context, hash common.Hash)
 		return nil, err
 	}
 
-	if chainConfig.Bor != nil {
-		if blockNum == 0 {
-			var blocN uint64
-			borTx, blockHash, blocN, _, err = rawdb.ReadBorTransaction(tx, hash)
-			if err != nil {
-				return nil, err
-			}
-			if borTx == nil {
-				return nil, nil // not error, see https://github.com/ledgerwatch/erigon/issues/1645
-			}
-			blockNum = blocN
-		}
-	} else {
-		blockNum, ok, err = api.txnLookup(ctx, tx, hash)
+	blockNum, ok, err = api.txnLookup(ctx, tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok && chainConfig.Bor != nil {
+		// state-sync transactions are not in the block body and have their own lookup
+		var blocN uint64
+		borTx, blockHash, blocN, _, err = rawdb.ReadBorTransaction(tx, hash)
 		if err != nil {
 			return nil, err
 		}
-		if !ok {
-			return nil, nil // not error, see https://github.com/ledgerwatch/erigon/issues/1645
-		}
+		ok, blockNum = borTx != nil, blocN
+	}
+	if !ok {
+		return nil, nil // not error, see https://github.com/ledgerwatch/erigon/issues/1645
 	}
 
 	block, err := api.blockByNumberWithSenders(tx, blockNum)
@@ -307,7 +380,7 @@ func (api *APIImpl) GetTransactionReceipt(ctx context.Context, hash common.Hash)
 		return nil, nil
 	}
 
-	receipts, err := getReceipts(ctx, tx, cc, block, block.Body().SendersFromTxs())
+	receipts, err := api.getReceipts(ctx, tx, cc, block, block.Body().SendersFromTxs())
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %w", err)
 	}
@@ -341,7 +414,7 @@ func (api *APIImpl) GetBlockReceipts(ctx context.Context, number rpc.BlockNumber
 	if err != nil {
 		return nil, err
 	}
-	receipts, err := getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
+	receipts, err := api.getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
 	if err != nil {
 		return nil, fmt.Errorf("getReceipts error: %w", err)
 	}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+	"github.com/stretchr/testify/require"
+)
+
+// logsTestChain creates 5 blocks with 2 transactions each, every transaction emits 2 logs
+func logsTestChain(t *testing.T) *stages.MockSentry {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	emitter := common.HexToAddress("0xe0")
+	gspec := &core.Genesis{
+		Config: params.AllEthashProtocolChanges,
+		Alloc: core.GenesisAlloc{
+			address: {Balance: big.NewInt(params.Ether)},
+			emitter: {Balance: new(big.Int), Code: common.FromHex("60006000a060006000a0")}, // LOG0 twice
+		},
+		GasLimit: 10000000,
+	}
+	m := stages.MockWithGenesis(t, gspec, key)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 5, func(i int, gen *core.BlockGen) {
+		for j := 0; j < 2; j++ {
+			txn, err := types.SignTx(types.NewTransaction(gen.TxNonce(address), emitter, uint256.NewInt(0), 50000, uint256.NewInt(10*params.GWei), nil), *types.LatestSigner(m.ChainConfig), key)
+			require.NoError(t, err)
+			gen.AddTx(txn)
+		}
+	}, false /* intermediateHashes */)
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+	return m
+}
+
+func TestGetLogsLimits(t *testing.T) {
+	m := logsTestChain(t)
+	base := NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), snapshotsync.NewBlockReader(), false)
+	api := NewEthAPI(base, m.DB, nil, nil, nil, 5000000)
+	ctx := context.Background()
+	crit := filters.FilterCriteria{FromBlock: big.NewInt(1), ToBlock: big.NewInt(5)}
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	require.NoError(t, api.GetLogs(ctx, crit, stream))
+	var logs []*types.Log
+	require.NoError(t, json.Unmarshal(stream.Buffer(), &logs))
+	require.Equal(t, 20, len(logs))
+	require.Equal(t, uint64(5), logs[19].BlockNumber)
+	require.Equal(t, uint(3), logs[19].Index)
+	require.Equal(t, uint(1), logs[19].TxIndex)
+
+	// 7th log is in block 2, so only block 1 fits
+	base.SetLogsLimits(0, 6)
+	stream.Reset(nil)
+	err := api.GetLogs(ctx, crit, stream)
+	require.Error(t, err)
+	limitErr, ok := err.(*LogsLimitError)
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"toBlock": hexutilUint64(1)}, limitErr.ErrorData())
+
+	base.SetLogsLimits(2, 0)
+	stream.Reset(nil)
+	err = api.GetLogs(ctx, crit, stream)
+	require.Error(t, err)
+	require.Equal(t, map[string]interface{}{"toBlock": hexutilUint64(2)}, err.(*LogsLimitError).ErrorData())
+}
+
+func TestErigonGetLogsPages(t *testing.T) {
+	m := logsTestChain(t)
+	base := NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), snapshotsync.NewBlockReader(), false)
+	base.SetLogsLimits(3, 5)
+	api := NewErigonAPI(base, m.DB, nil)
+	ctx := context.Background()
+	crit := filters.FilterCriteria{FromBlock: big.NewInt(1), ToBlock: big.NewInt(5)}
+
+	type position struct {
+		block uint64
+		index uint
+	}
+	var all []position
+	var cursor *hexutil.Bytes
+	var pages int
+	for {
+		stream := jsoniter.ConfigDefault.BorrowStream(nil)
+		require.NoError(t, api.GetLogs(ctx, crit, cursor, stream))
+		var page struct {
+			Logs   []*types.Log   `json:"logs"`
+			Cursor *hexutil.Bytes `json:"cursor"`
+		}
+		require.NoError(t, json.Unmarshal(stream.Buffer(), &page))
+		jsoniter.ConfigDefault.ReturnStream(stream)
+		require.LessOrEqual(t, len(page.Logs), 5)
+		for _, log := range page.Logs {
+			all = append(all, position{log.BlockNumber, log.Index})
+		}
+		pages++
+		if page.Cursor == nil {
+			break
+		}
+		cursor = page.Cursor
+	}
+	require.Equal(t, 20, len(all))
+	for i, p := range all {
+		require.Equal(t, position{uint64(i/4 + 1), uint(i % 4)}, p)
+	}
+	require.Equal(t, 4, pages)
+}
+
+func hexutilUint64(n uint64) *hexutil.Uint64 {
+	v := hexutil.Uint64(n)
+	return &v
+}
@@ -0,0 +1,319 @@
+package commands
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/ethdb"
+	"github.com/ledgerwatch/erigon/internal/ethapi"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+const (
+	// maxSimulateBlocks - max amount of blocks which can be simulated by a single eth_simulateV1 call
+	maxSimulateBlocks = 256
+	// simulateBlockTimeIncrement - default difference between timestamps of consecutive simulated blocks
+	simulateBlockTimeIncrement = 12
+)
+
+// SimulationOptions - arguments of eth_simulateV1
+type SimulationOptions struct {
+	BlockStateCalls []SimulatedBlockCalls `json:"blockStateCalls"`
+}
+
+// SimulatedBlockCalls - calls to be executed in one simulated block, with optional overrides of the block header
+// and of the state (applied before the first call of the block)
+type SimulatedBlockCalls struct {
+	BlockOverrides *BlockOverrides        `json:"blockOverrides"`
+	StateOverrides *ethapi.StateOverrides `json:"stateOverrides"`
+	Calls          []ethapi.CallArgs      `json:"calls"`
+}
+
+// BlockOverrides - header fields of simulated block, which can be set by caller
+type BlockOverrides struct {
+	Number        *hexutil.Big    `json:"number"`
+	Time          *hexutil.Uint64 `json:"time"`
+	GasLimit      *hexutil.Uint64 `json:"gasLimit"`
+	FeeRecipient  *common.Address `json:"feeRecipient"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas"`
+}
+
+// SimulatedBlockResult - header of simulated block and results of its calls
+type SimulatedBlockResult struct {
+	Number        hexutil.Uint64        `json:"number"`
+	Hash          common.Hash           `json:"hash"`
+	ParentHash    common.Hash           `json:"parentHash"`
+	Timestamp     hexutil.Uint64        `json:"timestamp"`
+	GasLimit      hexutil.Uint64        `json:"gasLimit"`
+	GasUsed       hexutil.Uint64        `json:"gasUsed"`
+	FeeRecipient  common.Address        `json:"miner"`
+	BaseFeePerGas *hexutil.Big          `json:"baseFeePerGas,omitempty"`
+	Calls         []SimulatedCallResult `json:"calls"`
+}
+
+// SimulatedCallResult - outcome of one call of simulated block
+type SimulatedCallResult struct {
+	ReturnData hexutil.Bytes       `json:"returnData"`
+	Logs       []*types.Log        `json:"logs"`
+	GasUsed    hexutil.Uint64      `json:"gasUsed"`
+	Status     hexutil.Uint64      `json:"status"`
+	Error      *SimulatedCallError `json:"error,omitempty"`
+}
+
+// SimulatedCallError - reason of failed call, for reverted calls Data contains the revert data
+type SimulatedCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateV1 implements eth_simulateV1. Executes calls in a chain of simulated blocks on top of the given block,
+// state changes made by each call are visible to all following calls, including calls of the following blocks.
+func (api *APIImpl) SimulateV1(ctx context.Context, opts SimulationOptions, blockNrOrHash *rpc.BlockNumberOrHash) ([]SimulatedBlockResult, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, errors.New("empty blockStateCalls")
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, fmt.Errorf("too many blocks: %d, max is %d", len(opts.BlockStateCalls), maxSimulateBlocks)
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	blockNumber, hash, _, err := rpchelper.GetCanonicalBlockNumber(bNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	parent := rawdb.ReadHeader(tx, hash, blockNumber)
+	if parent == nil {
+		return nil, fmt.Errorf("block %d(%x) not found", blockNumber, hash)
+	}
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, bNrOrHash, api.filters, api.stateCache)
+	if err != nil {
+		return nil, err
+	}
+	ibs := state.New(stateReader)
+
+	contractHasTEVM := func(contractHash common.Hash) (bool, error) { return false, nil }
+	if api.TevmEnabled {
+		contractHasTEVM = ethdb.GetHasTEVM(tx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	simulatedHashes := map[uint64]common.Hash{}
+	getHash := func(n uint64) common.Hash {
+		if h, ok := simulatedHashes[n]; ok {
+			return h
+		}
+		if n > blockNumber {
+			return common.Hash{}
+		}
+		h, _ := rawdb.ReadCanonicalHash(tx, n)
+		return h
+	}
+
+	results := make([]SimulatedBlockResult, 0, len(opts.BlockStateCalls))
+	for i, block := range opts.BlockStateCalls {
+		header, err := makeSimulatedHeader(chainConfig, parent, block.BlockOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		if block.StateOverrides != nil {
+			if err := block.StateOverrides.Override(ibs); err != nil {
+				return nil, fmt.Errorf("block %d: %w", i, err)
+			}
+		}
+		calls, err := api.simulateBlockCalls(ctx, tx, chainConfig, header, block.Calls, ibs, getHash, contractHasTEVM)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		blockHash := header.Hash()
+		for _, call := range calls {
+			for _, l := range call.Logs {
+				l.BlockHash = blockHash
+			}
+		}
+		simulatedHashes[header.Number.Uint64()] = blockHash
+
+		result := SimulatedBlockResult{
+			Number:       hexutil.Uint64(header.Number.Uint64()),
+			Hash:         blockHash,
+			ParentHash:   header.ParentHash,
+			Timestamp:    hexutil.Uint64(header.Time),
+			GasLimit:     hexutil.Uint64(header.GasLimit),
+			GasUsed:      hexutil.Uint64(header.GasUsed),
+			FeeRecipient: header.Coinbase,
+			Calls:        calls,
+		}
+		if header.BaseFee != nil {
+			result.BaseFeePerGas = (*hexutil.Big)(header.BaseFee)
+		}
+		results = append(results, result)
+		parent = header
+	}
+	return results, nil
+}
+
+// makeSimulatedHeader - header of the block following parent, with overrides applied
+func makeSimulatedHeader(chainConfig *params.ChainConfig, parent *types.Header, overrides *BlockOverrides) (*types.Header, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + simulateBlockTimeIncrement,
+		Difficulty: new(big.Int).Set(parent.Difficulty),
+		MixDigest:  parent.MixDigest,
+		Coinbase:   parent.Coinbase,
+	}
+	if overrides != nil {
+		if overrides.Number != nil {
+			if overrides.Number.ToInt().Cmp(parent.Number) <= 0 {
+				return nil, fmt.Errorf("block number %d is not greater than parent's %d", overrides.Number.ToInt(), parent.Number)
+			}
+			header.Number = new(big.Int).Set(overrides.Number.ToInt())
+		}
+		if overrides.Time != nil {
+			if uint64(*overrides.Time) <= parent.Time {
+				return nil, fmt.Errorf("block timestamp %d is not greater than parent's %d", uint64(*overrides.Time), parent.Time)
+			}
+			header.Time = uint64(*overrides.Time)
+		}
+		if overrides.GasLimit != nil {
+			header.GasLimit = uint64(*overrides.GasLimit)
+		}
+		if overrides.FeeRecipient != nil {
+			header.Coinbase = *overrides.FeeRecipient
+		}
+	}
+	if chainConfig.IsLondon(header.Number.Uint64()) {
+		header.Eip1559 = true
+		if overrides != nil && overrides.BaseFeePerGas != nil {
+			header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+		} else {
+			header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+		}
+	}
+	return header, nil
+}
+
+// simulateBlockCalls - executes calls one by one on top of ibs, fills GasUsed of the header
+func (api *APIImpl) simulateBlockCalls(ctx context.Context, tx kv.Tx, chainConfig *params.ChainConfig, header *types.Header, calls []ethapi.CallArgs,
+	ibs *state.IntraBlockState, getHash func(uint64) common.Hash, contractHasTEVM func(common.Hash) (bool, error)) ([]SimulatedCallResult, error) {
+	var baseFee *uint256.Int
+	if header.BaseFee != nil {
+		var overflow bool
+		if baseFee, overflow = uint256.FromBig(header.BaseFee); overflow {
+			return nil, fmt.Errorf("header.BaseFee uint256 overflow")
+		}
+	}
+	rules := chainConfig.Rules(header.Number.Uint64())
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	results := make([]SimulatedCallResult, 0, len(calls))
+	for i, args := range calls {
+		if args.Gas == nil || uint64(*args.Gas) > gp.Gas() {
+			gas := hexutil.Uint64(gp.Gas())
+			args.Gas = &gas
+		}
+		msg, err := args.ToMessage(api.GasCap, baseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		blockCtx, txCtx := transactions.GetEvmContext(msg, header, true, tx, contractHasTEVM)
+		blockCtx.GetHash = getHash
+		evm := vm.NewEVM(blockCtx, txCtx, ibs, chainConfig, vm.Config{NoBaseFee: true})
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-stop:
+			}
+		}()
+
+		txHash := simulatedTxHash(msg, header.Number.Uint64(), i)
+		ibs.Prepare(txHash, common.Hash{}, i)
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+		close(stop)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("call %d: execution aborted: %w", i, ctx.Err())
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, err
+		}
+		header.GasUsed += result.UsedGas
+
+		logs := ibs.GetLogs(txHash)
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		for _, l := range logs {
+			l.BlockNumber = header.Number.Uint64()
+		}
+		callResult := SimulatedCallResult{
+			ReturnData: result.Return(),
+			Logs:       logs,
+			GasUsed:    hexutil.Uint64(result.UsedGas),
+			Status:     hexutil.Uint64(types.ReceiptStatusSuccessful),
+		}
+		if result.Failed() {
+			callResult.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			if len(result.Revert()) > 0 {
+				revertErr := ethapi.NewRevertError(result)
+				callResult.ReturnData = result.Revert()
+				callResult.Error = &SimulatedCallError{Code: revertErr.ErrorCode(), Message: revertErr.Error(), Data: revertErr.ErrorData().(string)}
+			} else {
+				callResult.Error = &SimulatedCallError{Code: -32015, Message: result.Err.Error()}
+			}
+		}
+		results = append(results, callResult)
+	}
+	return results, nil
+}
+
+// simulatedTxHash - calls are not signed, so hash of the unsigned transaction made from the message is used to identify them.
+// Block number and position are mixed in, because the same call may be repeated and its logs must not be mixed up.
+func simulatedTxHash(msg types.Message, blockNum uint64, txIndex int) common.Hash {
+	var txn types.Transaction
+	if msg.To() == nil {
+		txn = types.NewContractCreation(msg.Nonce(), msg.Value(), msg.Gas(), msg.GasPrice(), msg.Data())
+	} else {
+		txn = types.NewTransaction(msg.Nonce(), *msg.To(), msg.Value(), msg.Gas(), msg.GasPrice(), msg.Data())
+	}
+	var pos [16]byte
+	binary.BigEndian.PutUint64(pos[:8], blockNum)
+	binary.BigEndian.PutUint64(pos[8:], uint64(txIndex))
+	return crypto.Keccak256Hash(txn.Hash().Bytes(), pos[:])
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/internal/ethapi"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateV1(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false), db, nil, nil, nil, 5000000)
+
+	// counter returns the block number stored by the previous call, stores the current one and logs the block timestamp
+	counter := common.HexToAddress("0x1000")
+	counterCode := hexutil.Bytes(common.FromHex("600054600052436000554260205260206020a060206000f3"))
+	// reverter reverts with 0xaa
+	reverter := common.HexToAddress("0x2000")
+	reverterCode := hexutil.Bytes(common.FromHex("60aa60005360016000fd"))
+
+	base := rpc.BlockNumberOrHashWithNumber(5)
+	opts := SimulationOptions{BlockStateCalls: []SimulatedBlockCalls{
+		{
+			BlockOverrides: &BlockOverrides{Time: (*hexutil.Uint64)(new(uint64))},
+			StateOverrides: &ethapi.StateOverrides{counter: {Code: &counterCode}, reverter: {Code: &reverterCode}},
+			Calls:          []ethapi.CallArgs{{To: &counter}, {To: &reverter}},
+		},
+		{
+			BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(100))},
+			Calls:          []ethapi.CallArgs{{To: &counter}},
+		},
+	}}
+
+	// timestamp must grow
+	_, err := api.SimulateV1(context.Background(), opts, &base)
+	require.Error(t, err)
+
+	tx, err := db.BeginRo(context.Background())
+	require.NoError(t, err)
+	header := rawdb.ReadHeaderByNumber(tx, 5)
+	tx.Rollback()
+	blockTime := hexutil.Uint64(header.Time + 100)
+	opts.BlockStateCalls[0].BlockOverrides.Time = &blockTime
+
+	blocks, err := api.SimulateV1(context.Background(), opts, &base)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(blocks))
+
+	first, second := blocks[0], blocks[1]
+	assert.Equal(t, hexutil.Uint64(6), first.Number)
+	assert.Equal(t, header.Hash(), first.ParentHash)
+	assert.Equal(t, blockTime, first.Timestamp)
+	assert.Equal(t, hexutil.Uint64(100), second.Number)
+	assert.Equal(t, first.Hash, second.ParentHash)
+	assert.Equal(t, blockTime+simulateBlockTimeIncrement, second.Timestamp)
+
+	require.Equal(t, 2, len(first.Calls))
+	call := first.Calls[0]
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), call.Status)
+	assert.Equal(t, common.Hash{}.Bytes(), []byte(call.ReturnData))
+	require.Equal(t, 1, len(call.Logs))
+	assert.Equal(t, counter, call.Logs[0].Address)
+	assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(uint64(blockTime))).Bytes(), call.Logs[0].Data)
+	assert.Equal(t, first.Hash, call.Logs[0].BlockHash)
+	assert.Equal(t, uint64(6), call.Logs[0].BlockNumber)
+
+	call = first.Calls[1]
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusFailed), call.Status)
+	assert.Equal(t, []byte{0xaa}, []byte(call.ReturnData))
+	require.NotNil(t, call.Error)
+	assert.Equal(t, 3, call.Error.Code)
+	assert.Equal(t, "0xaa", call.Error.Data)
+	assert.Equal(t, first.GasUsed, first.Calls[0].GasUsed+first.Calls[1].GasUsed)
+
+	// state of the first block is visible in the second one
+	call = second.Calls[0]
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), call.Status)
+	assert.Equal(t, common.BigToHash(big.NewInt(6)).Bytes(), []byte(call.ReturnData))
+	require.Equal(t, 1, len(call.Logs))
+	assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(uint64(blockTime+simulateBlockTimeIncrement))).Bytes(), call.Logs[0].Data)
+}
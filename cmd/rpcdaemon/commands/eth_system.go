@@ -217,7 +217,14 @@ func (b *GasPriceOracleBackend) ChainConfig() *params.ChainConfig {
 	return b.cc
 }
 func (b *GasPriceOracleBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return rawdb.ReadReceiptsByHash(b.tx, hash)
+	block, err := b.baseApi.blockByHashWithSenders(b.tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil
+	}
+	return b.baseApi.getReceipts(ctx, b.tx, b.cc, block, block.Body().SendersFromTxs())
 }
 func (b *GasPriceOracleBackend) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
 	return nil, nil
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// GraphQLAPI reads the chain for the GraphQL endpoint (see ../graphql), all reads of a single GraphQL query share one transaction
+type GraphQLAPI interface {
+	BeginRo(ctx context.Context) (kv.Tx, error)
+	ChainConfig(tx kv.Tx) (*params.ChainConfig, error)
+	BlockByNumber(tx kv.Tx, number rpc.BlockNumber) (*types.Block, error)
+	BlockByHash(tx kv.Tx, hash common.Hash) (*types.Block, error)
+	TotalDifficulty(tx kv.Tx, block *types.Block) (*big.Int, error)
+	Receipts(ctx context.Context, tx kv.Tx, block *types.Block) (types.Receipts, error)
+	TxnLookup(ctx context.Context, tx kv.Tx, txnHash common.Hash) (uint64, bool, error)
+	Logs(ctx context.Context, tx kv.Tx, crit filters.FilterCriteria) ([]*types.Log, error)
+	StateReader(ctx context.Context, tx kv.Tx, blockNum uint64) (state.StateReader, error)
+}
+
+// GraphQLAPIImpl is implementation of the GraphQLAPI interface
+type GraphQLAPIImpl struct {
+	*BaseAPI
+	db kv.RoDB
+}
+
+// NewGraphQLAPI returns GraphQLAPIImpl instance
+func NewGraphQLAPI(base *BaseAPI, db kv.RoDB) *GraphQLAPIImpl {
+	return &GraphQLAPIImpl{
+		BaseAPI: base,
+		db:      db,
+	}
+}
+
+func (api *GraphQLAPIImpl) BeginRo(ctx context.Context) (kv.Tx, error) {
+	return api.db.BeginRo(ctx)
+}
+
+func (api *GraphQLAPIImpl) ChainConfig(tx kv.Tx) (*params.ChainConfig, error) {
+	return api.chainConfig(tx)
+}
+
+// BlockByNumber returns the canonical block, nil if there is no such block. Pending block is not supported.
+func (api *GraphQLAPIImpl) BlockByNumber(tx kv.Tx, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.PendingBlockNumber {
+		return nil, fmt.Errorf("pending block is not supported")
+	}
+	n, err := getBlockNumber(number, tx)
+	if err != nil {
+		return nil, err
+	}
+	return api.blockByNumberWithSenders(tx, n)
+}
+
+func (api *GraphQLAPIImpl) BlockByHash(tx kv.Tx, hash common.Hash) (*types.Block, error) {
+	return api.blockByHashWithSenders(tx, hash)
+}
+
+func (api *GraphQLAPIImpl) TotalDifficulty(tx kv.Tx, block *types.Block) (*big.Int, error) {
+	td, err := rawdb.ReadTd(tx, block.Hash(), block.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+	if td == nil {
+		return nil, fmt.Errorf("total difficulty of block %d not found", block.NumberU64())
+	}
+	return td, nil
+}
+
+func (api *GraphQLAPIImpl) Receipts(ctx context.Context, tx kv.Tx, block *types.Block) (types.Receipts, error) {
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	return api.getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
+}
+
+func (api *GraphQLAPIImpl) TxnLookup(ctx context.Context, tx kv.Tx, txnHash common.Hash) (uint64, bool, error) {
+	return api.txnLookup(ctx, tx, txnHash)
+}
+
+// Logs returns logs matching the filter, within the same limits as eth_getLogs
+func (api *GraphQLAPIImpl) Logs(ctx context.Context, tx kv.Tx, crit filters.FilterCriteria) ([]*types.Log, error) {
+	begin, end, err := api.logsBlockRange(tx, crit)
+	if err != nil {
+		return nil, err
+	}
+	if err = api.checkLogsBlockRange(begin, end); err != nil {
+		return nil, err
+	}
+	logs := []*types.Log{}
+	err = api.forEachLog(ctx, tx, crit, begin, end, func(log *types.Log) error {
+		if api.LogsMaxResults > 0 && uint64(len(logs)) == api.LogsMaxResults {
+			return &LogsLimitError{msg: fmt.Sprintf("query returned more than %d results", api.LogsMaxResults)}
+		}
+		logs = append(logs, log)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// StateReader returns the reader of the state after the given block
+func (api *GraphQLAPIImpl) StateReader(ctx context.Context, tx kv.Tx, blockNum uint64) (state.StateReader, error) {
+	return rpchelper.CreateStateReader(ctx, tx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNum)), api.filters, api.stateCache)
+}
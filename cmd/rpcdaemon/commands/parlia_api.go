@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// ParliaAPI Parlia specific routines
+type ParliaAPI interface {
+	// Parlia snapshot related (see ./parlia_snapshot.go)
+	GetSnapshot(ctx context.Context, number *rpc.BlockNumber) (*ParliaSnapshot, error)
+	GetValidators(ctx context.Context, number *rpc.BlockNumber) ([]common.Address, error)
+	GetValidatorsAtHash(ctx context.Context, hash common.Hash) ([]common.Address, error)
+}
+
+// ParliaImpl is implementation of the ParliaAPI interface
+type ParliaImpl struct {
+	*BaseAPI
+	db       kv.RoDB // the chain db
+	parliaDb kv.RoDB // the consensus db
+}
+
+// NewParliaAPI returns ParliaImpl instance
+func NewParliaAPI(base *BaseAPI, db kv.RoDB, parliaDb kv.RoDB) *ParliaImpl {
+	return &ParliaImpl{
+		BaseAPI:  base,
+		db:       db,
+		parliaDb: parliaDb,
+	}
+}
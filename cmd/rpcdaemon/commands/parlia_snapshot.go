@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus/parlia"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/log/v3"
+)
+
+var (
+	errNoParliaDb     = errors.New("parlia database is not available, run rpcdaemon with --datadir of a parlia chain")
+	errNotParliaChain = errors.New("chain is not using parlia consensus")
+)
+
+// ParliaSnapshot is the parlia snapshot at a block, with the validator in turn for the next block
+// and the rewards distributed and slashing done by the block
+type ParliaSnapshot struct {
+	*parlia.Snapshot
+	InturnValidator common.Address `json:"inturnValidator"`
+	Rewards         *ParliaRewards `json:"rewards"`
+}
+
+// ParliaRewards is the RPC representation of parlia.Rewards
+type ParliaRewards struct {
+	Validator       common.Address  `json:"validator"`
+	ValidatorReward *hexutil.Big    `json:"validatorReward"`
+	SystemReward    *hexutil.Big    `json:"systemReward"`
+	Slashed         *common.Address `json:"slashed"`
+}
+
+// GetSnapshot retrieves the state snapshot at a given block.
+func (api *ParliaImpl) GetSnapshot(ctx context.Context, number *rpc.BlockNumber) (*ParliaSnapshot, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := api.parliaHeaderByNumber(ctx, number, tx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := api.parliaSnapshot(ctx, tx, header)
+	if err != nil {
+		return nil, err
+	}
+	validators := snap.SortedValidators()
+	result := &ParliaSnapshot{Snapshot: snap}
+	if len(validators) > 0 {
+		result.InturnValidator = validators[(snap.Number+1)%uint64(len(validators))]
+	}
+
+	block, err := api.blockWithSenders(tx, header.Hash(), header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	if block != nil {
+		rewards := parlia.BlockRewards(block.Header(), block.Transactions(), block.Body().SendersFromTxs())
+		result.Rewards = &ParliaRewards{
+			Validator:       rewards.Validator,
+			ValidatorReward: (*hexutil.Big)(rewards.ValidatorReward.ToBig()),
+			SystemReward:    (*hexutil.Big)(rewards.SystemReward.ToBig()),
+			Slashed:         rewards.Slashed,
+		}
+	}
+	return result, nil
+}
+
+// GetValidators retrieves the list of authorized validators at the specified block.
+func (api *ParliaImpl) GetValidators(ctx context.Context, number *rpc.BlockNumber) ([]common.Address, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := api.parliaHeaderByNumber(ctx, number, tx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := api.parliaSnapshot(ctx, tx, header)
+	if err != nil {
+		return nil, err
+	}
+	return snap.SortedValidators(), nil
+}
+
+// GetValidatorsAtHash retrieves the list of authorized validators at the specified block.
+func (api *ParliaImpl) GetValidatorsAtHash(ctx context.Context, hash common.Hash) ([]common.Address, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := rawdb.ReadHeaderByHash(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.parliaSnapshot(ctx, tx, header)
+	if err != nil {
+		return nil, err
+	}
+	return snap.SortedValidators(), nil
+}
+
+// parliaHeaderByNumber returns the header of the requested block, the current one if none requested
+func (api *ParliaImpl) parliaHeaderByNumber(ctx context.Context, number *rpc.BlockNumber, tx kv.Tx) (*types.Header, error) {
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header := rawdb.ReadCurrentHeader(tx)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		return header, nil
+	}
+	if *number == rpc.PendingBlockNumber {
+		return nil, errors.New("snapshot of the pending block is not supported")
+	}
+	blockNum, err := getBlockNumber(*number, tx)
+	if err != nil {
+		return nil, err
+	}
+	header := rawdb.ReadHeaderByNumber(tx, blockNum)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+// parliaSnapshot builds the snapshot at the header from the nearest snapshot persisted by the engine
+func (api *ParliaImpl) parliaSnapshot(ctx context.Context, tx kv.Tx, header *types.Header) (*parlia.Snapshot, error) {
+	cc, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	if cc.Parlia == nil {
+		return nil, errNotParliaChain
+	}
+	if api.parliaDb == nil {
+		return nil, errNoParliaDb
+	}
+	parliaTx, err := api.parliaDb.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer parliaTx.Rollback()
+	chain := parliaHeaderReader{cfg: cc, tx: tx}
+	return parlia.ReadSnapshot(cc, parliaTx, chain, header.Number.Uint64(), header.Hash())
+}
+
+// parliaHeaderReader is consensus.ChainHeaderReader over the chain db
+type parliaHeaderReader struct {
+	cfg *params.ChainConfig
+	tx  kv.Tx
+}
+
+func (cr parliaHeaderReader) Config() *params.ChainConfig { return cr.cfg }
+
+func (cr parliaHeaderReader) CurrentHeader() *types.Header { return rawdb.ReadCurrentHeader(cr.tx) }
+
+func (cr parliaHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return rawdb.ReadHeader(cr.tx, hash, number)
+}
+
+func (cr parliaHeaderReader) GetHeaderByNumber(number uint64) *types.Header {
+	return rawdb.ReadHeaderByNumber(cr.tx, number)
+}
+
+func (cr parliaHeaderReader) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, err := rawdb.ReadHeaderByHash(cr.tx, hash)
+	if err != nil {
+		log.Error("ReadHeaderByHash failed", "err", err)
+		return nil
+	}
+	return header
+}
+
+func (cr parliaHeaderReader) GetTd(hash common.Hash, number uint64) *big.Int {
+	td, err := rawdb.ReadTd(cr.tx, hash, number)
+	if err != nil {
+		log.Error("ReadTd failed", "err", err)
+		return nil
+	}
+	return td
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
 
 func getBlockNumber(number rpc.BlockNumber, tx kv.Tx) (uint64, error) {
@@ -18,6 +19,10 @@ func getBlockNumber(number rpc.BlockNumber, tx kv.Tx) (uint64, error) {
 		}
 	} else if number == rpc.EarliestBlockNumber {
 		blockNum = 0
+	} else if number == rpc.SafeBlockNumber {
+		return rpchelper.GetSafeBlockNumber(tx)
+	} else if number == rpc.FinalizedBlockNumber {
+		return rpchelper.GetFinalizedBlockNumber(tx)
 	} else {
 		blockNum = uint64(number.Int64())
 	}
@@ -14,8 +14,12 @@ import (
 	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracestore"
 	"github.com/ledgerwatch/erigon/ethdb"
 	"github.com/ledgerwatch/erigon/ethdb/bitmapdb"
+	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
@@ -69,27 +73,34 @@ func (api *TraceAPIImpl) Transaction(ctx context.Context, txHash common.Hash) (P
 	}
 	hash := block.Hash()
 
-	// Returns an array of trace arrays, one trace array for each transaction
-	traces, err := api.callManyTransactions(ctx, tx, block.Transactions(), []string{TraceTypeTrace}, block.ParentHash(), rpc.BlockNumber(parentNr), block.Header(), txIndex, types.MakeSigner(chainConfig, blockNumber))
+	var txTraces []*ParityTrace
+	stored, ok, err := readStoredTxTraces(tx, blockNumber, txIndex)
 	if err != nil {
 		return nil, err
 	}
+	if ok {
+		txTraces = api.fromStoredTraces(stored)
+	} else {
+		// Returns an array of trace arrays, one trace array for each transaction
+		traces, err := api.callManyTransactions(ctx, tx, block.Transactions(), []string{TraceTypeTrace}, block.ParentHash(), rpc.BlockNumber(parentNr), block.Header(), txIndex, types.MakeSigner(chainConfig, blockNumber))
+		if err != nil {
+			return nil, err
+		}
+		if txIndex < len(traces) {
+			txTraces = traces[txIndex].Trace
+		}
+	}
 
-	out := make([]ParityTrace, 0, len(traces))
+	out := make([]ParityTrace, 0, len(txTraces))
 	blockno := uint64(bn)
-	for txno, trace := range traces {
-		txhash := block.Transactions()[txno].Hash()
-		// We're only looking for a specific transaction
-		if txno == txIndex {
-			for _, pt := range trace.Trace {
-				pt.BlockHash = &hash
-				pt.BlockNumber = &blockno
-				pt.TransactionHash = &txhash
-				txpos := uint64(txno)
-				pt.TransactionPosition = &txpos
-				out = append(out, *pt)
-			}
-		}
+	txhash := block.Transactions()[txIndex].Hash()
+	txpos := uint64(txIndex)
+	for _, pt := range txTraces {
+		pt.BlockHash = &hash
+		pt.BlockNumber = &blockno
+		pt.TransactionHash = &txhash
+		pt.TransactionPosition = &txpos
+		out = append(out, *pt)
 	}
 
 	return out, err
@@ -143,26 +154,21 @@ func (api *TraceAPIImpl) Block(ctx context.Context, blockNr rpc.BlockNumber) (Pa
 	}
 	hash := block.Hash()
 
-	parentNr := bn
-	if parentNr > 0 {
-		parentNr -= 1
-	}
-
 	chainConfig, err := api.chainConfig(tx)
 	if err != nil {
 		return nil, err
 	}
-	traces, err := api.callManyTransactions(ctx, tx, block.Transactions(), []string{TraceTypeTrace}, block.ParentHash(), rpc.BlockNumber(parentNr), block.Header(), -1 /* all tx indices */, types.MakeSigner(chainConfig, blockNum))
+	traces, err := api.blockTraces(ctx, tx, block, chainConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	out := make([]ParityTrace, 0, len(traces))
 	blockno := uint64(bn)
-	for txno, trace := range traces {
+	for txno, txTraces := range traces {
 		txhash := block.Transactions()[txno].Hash()
 		txpos := uint64(txno)
-		for _, pt := range trace.Trace {
+		for _, pt := range txTraces {
 			pt.BlockHash = &hash
 			pt.BlockNumber = &blockno
 			pt.TransactionHash = &txhash
@@ -206,7 +212,7 @@ func (api *TraceAPIImpl) Block(ctx context.Context, blockNr rpc.BlockNumber) (Pa
 }
 
 // Filter implements trace_filter
-// NOTE: We do not store full traces - we just store index for each address
+// NOTE: Full traces are stored only if the TraceIndex stage is enabled, otherwise we just store index for each address
 // Pull blocks which have txs with matching address
 func (api *TraceAPIImpl) Filter(ctx context.Context, req TraceFilterRequest, stream *jsoniter.Stream) error {
 	dbtx, err1 := api.kv.BeginRo(ctx)
@@ -336,17 +342,17 @@ func (api *TraceAPIImpl) Filter(ctx context.Context, req TraceFilterRequest, str
 		blockHash := block.Hash()
 		blockNumber := block.NumberU64()
 		txs := block.Transactions()
-		t, tErr := api.callManyTransactions(ctx, dbtx, txs, []string{TraceTypeTrace}, block.ParentHash(), rpc.BlockNumber(block.NumberU64()-1), block.Header(), -1 /* all tx indices */, types.MakeSigner(chainConfig, b))
+		t, tErr := api.blockTraces(ctx, dbtx, block, chainConfig)
 		if tErr != nil {
 			stream.WriteNil()
 			return tErr
 		}
 		includeAll := len(fromAddresses) == 0 && len(toAddresses) == 0
-		for i, trace := range t {
+		for i, txTraces := range t {
 			txPosition := uint64(i)
 			txHash := txs[i].Hash()
 			// Check if transaction concerns any of the addresses we wanted
-			for _, pt := range trace.Trace {
+			for _, pt := range txTraces {
 				if includeAll || filter_trace(pt, fromAddresses, toAddresses) {
 					nSeen++
 					pt.BlockHash = &blockHash
@@ -470,6 +476,139 @@ func filter_trace(pt *ParityTrace, fromAddresses map[common.Address]struct{}, to
 	return false
 }
 
+// blockTraces returns traces of all transactions of the block, one trace array for each transaction. Traces stored by
+// the TraceIndex stage are used when available, otherwise the block is re-executed
+func (api *TraceAPIImpl) blockTraces(ctx context.Context, dbtx kv.Tx, block *types.Block, chainConfig *params.ChainConfig) ([][]*ParityTrace, error) {
+	stored, ok, err := readStoredBlockTraces(dbtx, block)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		traces := make([][]*ParityTrace, len(stored))
+		for i := range stored {
+			traces[i] = api.fromStoredTraces(stored[i])
+		}
+		return traces, nil
+	}
+
+	parentNr := block.NumberU64()
+	if parentNr > 0 {
+		parentNr -= 1
+	}
+	results, err := api.callManyTransactions(ctx, dbtx, block.Transactions(), []string{TraceTypeTrace}, block.ParentHash(), rpc.BlockNumber(parentNr), block.Header(), -1 /* all tx indices */, types.MakeSigner(chainConfig, block.NumberU64()))
+	if err != nil {
+		return nil, err
+	}
+	traces := make([][]*ParityTrace, len(results))
+	for i, result := range results {
+		traces[i] = result.Trace
+	}
+	return traces, nil
+}
+
+// readStoredBlockTraces returns traces of all transactions of the block, if they were stored by the TraceIndex stage
+// and not pruned yet
+func readStoredBlockTraces(dbtx kv.Tx, block *types.Block) ([][]tracestore.Trace, bool, error) {
+	progress, err := stages.GetStageProgress(dbtx, stages.TraceIndex)
+	if err != nil {
+		return nil, false, err
+	}
+	if progress < block.NumberU64() {
+		return nil, false, nil
+	}
+	stored, err := tracestore.ReadBlockTraces(dbtx, block.NumberU64())
+	if err != nil {
+		return nil, false, err
+	}
+	// Every transaction has at least one trace, missing ones are pruned or not stored (system transactions)
+	if len(stored) != len(block.Transactions()) {
+		return nil, false, nil
+	}
+	for _, txTraces := range stored {
+		if len(txTraces) == 0 {
+			return nil, false, nil
+		}
+	}
+	return stored, true, nil
+}
+
+// readStoredTxTraces returns traces of the transaction, if they were stored by the TraceIndex stage and not pruned yet
+func readStoredTxTraces(dbtx kv.Tx, blockNum uint64, txIndex int) ([]tracestore.Trace, bool, error) {
+	progress, err := stages.GetStageProgress(dbtx, stages.TraceIndex)
+	if err != nil {
+		return nil, false, err
+	}
+	if progress < blockNum {
+		return nil, false, nil
+	}
+	stored, err := tracestore.ReadTxTraces(dbtx, blockNum, txIndex)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, len(stored) > 0, nil
+}
+
+// fromStoredTraces converts traces of a transaction stored by the TraceIndex stage into Parity traces,
+// the same way as OeTracer produces them
+func (api *TraceAPIImpl) fromStoredTraces(stored []tracestore.Trace) []*ParityTrace {
+	traces := make([]*ParityTrace, 0, len(stored))
+	for i := range stored {
+		st := &stored[i]
+		pt := &ParityTrace{Subtraces: int(st.Subtraces), TraceAddress: make([]int, len(st.TraceAddress))}
+		for j, idx := range st.TraceAddress {
+			pt.TraceAddress[j] = int(idx)
+		}
+		switch st.Kind {
+		case tracestore.KindSuicide:
+			pt.Type = SUICIDE
+			action := &SuicideTraceAction{Address: st.From, RefundAddress: st.To}
+			action.Balance.ToInt().Set(st.Value)
+			pt.Action = action
+			traces = append(traces, pt)
+			continue
+		case tracestore.KindCreate:
+			pt.Type = CREATE
+			action := &CreateTraceAction{From: st.From, Init: st.Input}
+			action.Gas.ToInt().SetUint64(st.Gas)
+			action.Value.ToInt().Set(st.Value)
+			pt.Action = action
+		default:
+			pt.Type = CALL
+			action := &CallTraceAction{From: st.From, To: st.To, Input: st.Input}
+			switch vm.CallType(st.CallType) {
+			case vm.CALLT:
+				action.CallType = CALL
+			case vm.CALLCODET:
+				action.CallType = CALLCODE
+			case vm.DELEGATECALLT:
+				action.CallType = DELEGATECALL
+			case vm.STATICCALLT:
+				action.CallType = STATICCALL
+			}
+			action.Gas.ToInt().SetUint64(st.Gas)
+			action.Value.ToInt().Set(st.Value)
+			pt.Action = action
+		}
+		// Bug for bug compatibility mode ignores errors of the top level create
+		ignoreError := api.compatibility && len(st.TraceAddress) == 0 && st.Kind == tracestore.KindCreate
+		if st.Error != "" && !ignoreError {
+			pt.Error = st.Error
+			traces = append(traces, pt)
+			continue
+		}
+		gasUsed := new(hexutil.Big)
+		gasUsed.ToInt().SetUint64(st.GasUsed)
+		if st.Kind == tracestore.KindCreate {
+			address := st.To
+			pt.Result = &CreateTraceResult{Address: &address, Code: st.Output, GasUsed: gasUsed}
+		} else {
+			pt.Result = &TraceResult{GasUsed: gasUsed, Output: st.Output}
+		}
+		traces = append(traces, pt)
+	}
+	return traces
+}
+
 func (api *TraceAPIImpl) callManyTransactions(ctx context.Context, dbtx kv.Tx, txs []types.Transaction, traceTypes []string, parentHash common.Hash, parentNo rpc.BlockNumber, header *types.Header, txIndex int, signer *types.Signer) ([]*TraceCallResult, error) {
 	callParams := make([]TraceCallParam, 0, len(txs))
 	msgs := make([]types.Message, len(txs))
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracestore"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredTraces(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewTraceAPI(NewBaseApi(nil, stateCache, snapshotsync.NewBlockReader(), false), m.DB, &httpcfg.HttpCfg{})
+	ctx := context.Background()
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	head, err := stages.GetStageProgress(tx, stages.Execution)
+	tx.Rollback()
+	require.NoError(t, err)
+
+	// Traces produced by re-execution of blocks
+	replayed := make([][]byte, head+1)
+	for bn := uint64(1); bn <= head; bn++ {
+		traces, err := api.Block(ctx, rpc.BlockNumber(bn))
+		require.NoError(t, err)
+		replayed[bn], err = json.Marshal(traces)
+		require.NoError(t, err)
+	}
+
+	cfg := stagedsync.StageTraceIndexCfg(m.DB, prune.DefaultMode, m.ChainConfig, m.Engine, snapshotsync.NewBlockReader())
+	sync := stagedsync.New([]*stagedsync.Stage{{
+		ID: stages.TraceIndex,
+		Forward: func(firstCycle bool, badBlockUnwind bool, s *stagedsync.StageState, u stagedsync.Unwinder, tx kv.RwTx) error {
+			return stagedsync.SpawnTraceIndex(s, tx, cfg, ctx)
+		},
+		Unwind: func(firstCycle bool, u *stagedsync.UnwindState, s *stagedsync.StageState, tx kv.RwTx) error {
+			return stagedsync.UnwindTraceIndex(u, s, tx, cfg, ctx)
+		},
+		Prune: func(firstCycle bool, p *stagedsync.PruneState, tx kv.RwTx) error {
+			return stagedsync.PruneTraceIndex(p, tx, cfg, ctx)
+		},
+	}}, stagedsync.UnwindOrder{stages.TraceIndex}, stagedsync.PruneOrder{stages.TraceIndex})
+	require.NoError(t, sync.Run(m.DB, nil, true))
+
+	tx, err = m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	progress, err := stages.GetStageProgress(tx, stages.TraceIndex)
+	require.NoError(t, err)
+	require.Equal(t, head, progress)
+
+	// Same traces are served from the database
+	var storedTxs int
+	for bn := uint64(1); bn <= head; bn++ {
+		stored, err := tracestore.ReadBlockTraces(tx, bn)
+		require.NoError(t, err)
+		storedTxs += len(stored)
+
+		traces, err := api.Block(ctx, rpc.BlockNumber(bn))
+		require.NoError(t, err)
+		fromDb, err := json.Marshal(traces)
+		require.NoError(t, err)
+		require.JSONEq(t, string(replayed[bn]), string(fromDb), "block %d", bn)
+	}
+	require.NotZero(t, storedTxs)
+}
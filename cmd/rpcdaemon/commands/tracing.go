@@ -3,10 +3,13 @@ package commands
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/holiman/uint256"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
@@ -15,6 +18,8 @@ import (
 	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/ethdb"
 	"github.com/ledgerwatch/erigon/internal/ethapi"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/transactions"
@@ -56,7 +61,49 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 		stream.WriteNil()
 		return err
 	}
+	return api.traceBlockTxs(ctx, tx, block, chainConfig, config, stream)
+}
+
+// TraceBlock implements debug_traceBlock. Returns Geth style traces of the block given as RLP,
+// the block does not have to be in the database, but its parent does and has to be canonical.
+func (api *PrivateDebugAPIImpl) TraceBlock(ctx context.Context, blockRlp hexutil.Bytes, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(blockRlp, block); err != nil {
+		stream.WriteNil()
+		return fmt.Errorf("could not decode block: %w", err)
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	defer tx.Rollback()
+
+	if block.NumberU64() == 0 {
+		stream.WriteNil()
+		return fmt.Errorf("genesis is not traceable")
+	}
+	// state is read by block number, so it's the state after the parent only if the parent is canonical
+	canonicalParent, err := rawdb.ReadCanonicalHash(tx, block.NumberU64()-1)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	if canonicalParent != block.ParentHash() {
+		stream.WriteNil()
+		return fmt.Errorf("parent %d(%x) is not canonical", block.NumberU64()-1, block.ParentHash())
+	}
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	return api.traceBlockTxs(ctx, tx, block, chainConfig, config, stream)
+}
 
+// traceBlockTxs - traces transactions of the block one by one on top of the state of its parent
+func (api *PrivateDebugAPIImpl) traceBlockTxs(ctx context.Context, tx kv.Tx, block *types.Block, chainConfig *params.ChainConfig, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
 	contractHasTEVM := func(contractHash common.Hash) (bool, error) { return false, nil }
 	if api.TevmEnabled {
 		contractHasTEVM = ethdb.GetHasTEVM(tx)
@@ -233,3 +280,162 @@ func (api *PrivateDebugAPIImpl) TraceCall(ctx context.Context, args ethapi.CallA
 	// Trace the transaction and return
 	return transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream)
 }
+
+// Bundle - calls executed one after another in the same block
+type Bundle struct {
+	Transactions  []ethapi.CallArgs `json:"transactions"`
+	BlockOverride *BlockOverrides   `json:"blockOverride"`
+}
+
+// StateContext - block on top of which bundles are executed. Transactions of the block are
+// replayed up to (but not including) TransactionIndex, all of them if it is not set or -1.
+type StateContext struct {
+	BlockNumber      rpc.BlockNumberOrHash `json:"blockNumber"`
+	TransactionIndex *int                  `json:"transactionIndex"`
+}
+
+// TraceCallMany implements debug_traceCallMany. Returns Geth style traces of calls of all bundles,
+// executed one after another. State changes made by every call are visible to the following ones.
+func (api *PrivateDebugAPIImpl) TraceCallMany(ctx context.Context, bundles []Bundle, simulateContext StateContext, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	if len(bundles) == 0 {
+		stream.WriteNil()
+		return fmt.Errorf("empty bundles")
+	}
+	empty := true
+	for _, bundle := range bundles {
+		if len(bundle.Transactions) != 0 {
+			empty = false
+		}
+	}
+	if empty {
+		stream.WriteNil()
+		return fmt.Errorf("empty bundles")
+	}
+
+	dbtx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	defer dbtx.Rollback()
+
+	chainConfig, err := api.chainConfig(dbtx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	blockNum, hash, _, err := rpchelper.GetBlockNumber(simulateContext.BlockNumber, dbtx, api.filters)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	block, err := api.blockByNumberWithSenders(dbtx, blockNum)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	if block == nil || block.Hash() != hash {
+		stream.WriteNil()
+		return fmt.Errorf("block %d(%x) not found", blockNum, hash)
+	}
+
+	txIndex := len(block.Transactions())
+	if simulateContext.TransactionIndex != nil && *simulateContext.TransactionIndex != -1 {
+		txIndex = *simulateContext.TransactionIndex
+		if txIndex < 0 || txIndex > len(block.Transactions()) {
+			stream.WriteNil()
+			return fmt.Errorf("transaction index %d out of range for block %d", txIndex, blockNum)
+		}
+	}
+
+	contractHasTEVM := func(contractHash common.Hash) (bool, error) { return false, nil }
+	if api.TevmEnabled {
+		contractHasTEVM = ethdb.GetHasTEVM(dbtx)
+	}
+	getHeader := func(hash common.Hash, number uint64) *types.Header {
+		return rawdb.ReadHeader(dbtx, hash, number)
+	}
+	_, _, _, ibs, reader, err := transactions.ComputeTxEnv(ctx, block, chainConfig, getHeader, contractHasTEVM, ethash.NewFaker(), dbtx, hash, uint64(txIndex))
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	if config != nil && config.StateOverrides != nil {
+		if err = config.StateOverrides.Override(ibs); err != nil {
+			stream.WriteNil()
+			return err
+		}
+	}
+
+	callIndex := txIndex
+	stream.WriteArrayStart()
+	for bundleIndex, bundle := range bundles {
+		header := overrideHeader(block.Header(), bundle.BlockOverride)
+		var baseFee *uint256.Int
+		if header.BaseFee != nil {
+			var overflow bool
+			baseFee, overflow = uint256.FromBig(header.BaseFee)
+			if overflow {
+				stream.WriteArrayEnd()
+				return fmt.Errorf("header.BaseFee uint256 overflow")
+			}
+		}
+		rules := chainConfig.Rules(header.Number.Uint64())
+
+		stream.WriteArrayStart()
+		for i, args := range bundle.Transactions {
+			msg, err := args.ToMessage(api.GasCap, baseFee)
+			if err != nil {
+				stream.WriteArrayEnd()
+				stream.WriteArrayEnd()
+				return fmt.Errorf("bundle %d call %d: %w", bundleIndex, i, err)
+			}
+			blockCtx, txCtx := transactions.GetEvmContext(msg, header, simulateContext.BlockNumber.RequireCanonical, dbtx, contractHasTEVM)
+			txCtx.TxHash = simulatedTxHash(msg, header.Number.Uint64(), callIndex)
+			ibs.Prepare(txCtx.TxHash, common.Hash{}, callIndex)
+			if err = transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream); err != nil {
+				stream.WriteArrayEnd()
+				stream.WriteArrayEnd()
+				return fmt.Errorf("bundle %d call %d: %w", bundleIndex, i, err)
+			}
+			_ = ibs.FinalizeTx(rules, reader)
+			callIndex++
+			if i != len(bundle.Transactions)-1 {
+				stream.WriteMore()
+			}
+			stream.Flush()
+		}
+		stream.WriteArrayEnd()
+		if bundleIndex != len(bundles)-1 {
+			stream.WriteMore()
+		}
+	}
+	stream.WriteArrayEnd()
+	stream.Flush()
+	return nil
+}
+
+// overrideHeader - copy of the header with block overrides of a bundle applied
+func overrideHeader(header *types.Header, overrides *BlockOverrides) *types.Header {
+	header = types.CopyHeader(header)
+	if overrides == nil {
+		return header
+	}
+	if overrides.Number != nil {
+		header.Number = new(big.Int).Set(overrides.Number.ToInt())
+	}
+	if overrides.Time != nil {
+		header.Time = uint64(*overrides.Time)
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.FeeRecipient != nil {
+		header.Coinbase = *overrides.FeeRecipient
+	}
+	if overrides.BaseFeePerGas != nil {
+		header.Eip1559 = true
+		header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+	}
+	return header
+}
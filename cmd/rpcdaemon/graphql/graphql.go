@@ -0,0 +1,661 @@
+// Package graphql serves the EIP-1767 GraphQL schema (read-only part of it) on top of commands.GraphQLAPI
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// maxBlocksRange - maximum amount of blocks returned by the blocks query
+const maxBlocksRange = 1024
+
+var errBlockInvariant = errors.New("block objects must be instantiated with at least one of num or hash")
+
+// Long is a 64 bit integer, accepted as a JSON number, a decimal or a 0x-prefixed hexadecimal string
+type Long int64
+
+// ImplementsGraphQLType returns true if Long implements the provided GraphQL type.
+func (b Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+// UnmarshalGraphQL unmarshals the provided GraphQL query data.
+func (b *Long) UnmarshalGraphQL(input interface{}) error {
+	var err error
+	switch input := input.(type) {
+	case string:
+		var value uint64
+		if len(input) > 1 && input[:2] == "0x" {
+			value, err = hexutil.DecodeUint64(input)
+		} else {
+			value, err = strconv.ParseUint(input, 10, 64)
+		}
+		*b = Long(value)
+	case int32:
+		*b = Long(input)
+	case int64:
+		*b = Long(input)
+	case float64:
+		*b = Long(input)
+	default:
+		err = fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return err
+}
+
+type txKey struct{}
+
+// dbTx returns the transaction of the query
+func dbTx(ctx context.Context) kv.Tx {
+	return ctx.Value(txKey{}).(kv.Tx)
+}
+
+func bigOf(v *uint256.Int) hexutil.Big {
+	return hexutil.Big(*v.ToBig())
+}
+
+// Account represents an Ethereum account at a particular block.
+type Account struct {
+	r        *Resolver
+	address  common.Address
+	blockNum uint64 // state after this block
+}
+
+func (a *Account) stateReader(ctx context.Context) (state.StateReader, error) {
+	return a.r.api.StateReader(ctx, dbTx(ctx), a.blockNum)
+}
+
+func (a *Account) Address(ctx context.Context) (common.Address, error) {
+	return a.address, nil
+}
+
+func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
+	reader, err := a.stateReader(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	acc, err := reader.ReadAccountData(a.address)
+	if err != nil || acc == nil {
+		return hexutil.Big{}, err
+	}
+	return bigOf(&acc.Balance), nil
+}
+
+func (a *Account) TransactionCount(ctx context.Context) (Long, error) {
+	reader, err := a.stateReader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	acc, err := reader.ReadAccountData(a.address)
+	if err != nil || acc == nil {
+		return 0, err
+	}
+	return Long(acc.Nonce), nil
+}
+
+func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
+	reader, err := a.stateReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := reader.ReadAccountData(a.address)
+	if err != nil || acc == nil {
+		return hexutil.Bytes{}, err
+	}
+	return reader.ReadAccountCode(a.address, acc.Incarnation, acc.CodeHash)
+}
+
+func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash }) (common.Hash, error) {
+	reader, err := a.stateReader(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	acc, err := reader.ReadAccountData(a.address)
+	if err != nil || acc == nil {
+		return common.Hash{}, err
+	}
+	v, err := reader.ReadAccountStorage(a.address, acc.Incarnation, &args.Slot)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(v), nil
+}
+
+// Log represents an individual log message. All arguments are mandatory.
+type Log struct {
+	r           *Resolver
+	transaction *Transaction
+	log         *types.Log
+}
+
+func (l *Log) Transaction(ctx context.Context) *Transaction {
+	return l.transaction
+}
+
+func (l *Log) Account(ctx context.Context, args struct{ Block *Long }) *Account {
+	return l.r.account(l.log.Address, args.Block, l.transaction.block.NumberU64())
+}
+
+func (l *Log) Index(ctx context.Context) int32 {
+	return int32(l.log.Index)
+}
+
+func (l *Log) Topics(ctx context.Context) []common.Hash {
+	return l.log.Topics
+}
+
+func (l *Log) Data(ctx context.Context) hexutil.Bytes {
+	return l.log.Data
+}
+
+// Transaction represents an Ethereum transaction included in a block
+type Transaction struct {
+	r     *Resolver
+	txn   types.Transaction
+	block *Block
+	index uint64
+}
+
+func (t *Transaction) receipt(ctx context.Context) (*types.Receipt, error) {
+	receipts, err := t.block.receipts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.index >= uint64(len(receipts)) {
+		return nil, fmt.Errorf("receipt %d not found in block %d", t.index, t.block.NumberU64())
+	}
+	return receipts[t.index], nil
+}
+
+func (t *Transaction) Hash(ctx context.Context) common.Hash {
+	return t.txn.Hash()
+}
+
+func (t *Transaction) InputData(ctx context.Context) hexutil.Bytes {
+	return t.txn.GetData()
+}
+
+func (t *Transaction) Gas(ctx context.Context) Long {
+	return Long(t.txn.GetGas())
+}
+
+func (t *Transaction) GasPrice(ctx context.Context) hexutil.Big {
+	return t.effectiveGasPrice()
+}
+
+func (t *Transaction) effectiveGasPrice() hexutil.Big {
+	if t.block.BaseFee() != nil && t.txn.Type() == types.DynamicFeeTxType {
+		baseFee, _ := uint256.FromBig(t.block.BaseFee())
+		price := t.txn.GetEffectiveGasTip(baseFee)
+		return bigOf(new(uint256.Int).Add(price, baseFee))
+	}
+	return bigOf(t.txn.GetPrice())
+}
+
+func (t *Transaction) EffectiveGasPrice(ctx context.Context) *hexutil.Big {
+	price := t.effectiveGasPrice()
+	return &price
+}
+
+func (t *Transaction) MaxFeePerGas(ctx context.Context) *hexutil.Big {
+	if t.txn.Type() != types.DynamicFeeTxType {
+		return nil
+	}
+	v := bigOf(t.txn.GetFeeCap())
+	return &v
+}
+
+func (t *Transaction) MaxPriorityFeePerGas(ctx context.Context) *hexutil.Big {
+	if t.txn.Type() != types.DynamicFeeTxType {
+		return nil
+	}
+	v := bigOf(t.txn.GetTip())
+	return &v
+}
+
+func (t *Transaction) Value(ctx context.Context) hexutil.Big {
+	return bigOf(t.txn.GetValue())
+}
+
+func (t *Transaction) Nonce(ctx context.Context) Long {
+	return Long(t.txn.GetNonce())
+}
+
+func (t *Transaction) To(ctx context.Context, args struct{ Block *Long }) *Account {
+	to := t.txn.GetTo()
+	if to == nil {
+		return nil
+	}
+	return t.r.account(*to, args.Block, t.block.NumberU64())
+}
+
+func (t *Transaction) From(ctx context.Context, args struct{ Block *Long }) (*Account, error) {
+	from, ok := t.txn.GetSender()
+	if !ok {
+		return nil, fmt.Errorf("sender of transaction %x is not known", t.txn.Hash())
+	}
+	return t.r.account(from, args.Block, t.block.NumberU64()), nil
+}
+
+func (t *Transaction) Block(ctx context.Context) *Block {
+	return t.block
+}
+
+func (t *Transaction) Index(ctx context.Context) *int32 {
+	index := int32(t.index)
+	return &index
+}
+
+func (t *Transaction) Status(ctx context.Context) (*Long, error) {
+	receipt, err := t.receipt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := Long(receipt.Status)
+	return &status, nil
+}
+
+func (t *Transaction) GasUsed(ctx context.Context) (*Long, error) {
+	receipt, err := t.receipt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasUsed := Long(receipt.GasUsed)
+	return &gasUsed, nil
+}
+
+func (t *Transaction) CumulativeGasUsed(ctx context.Context) (*Long, error) {
+	receipt, err := t.receipt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasUsed := Long(receipt.CumulativeGasUsed)
+	return &gasUsed, nil
+}
+
+func (t *Transaction) CreatedContract(ctx context.Context, args struct{ Block *Long }) (*Account, error) {
+	if t.txn.GetTo() != nil {
+		return nil, nil
+	}
+	from, ok := t.txn.GetSender()
+	if !ok {
+		return nil, fmt.Errorf("sender of transaction %x is not known", t.txn.Hash())
+	}
+	return t.r.account(crypto.CreateAddress(from, t.txn.GetNonce()), args.Block, t.block.NumberU64()), nil
+}
+
+func (t *Transaction) Logs(ctx context.Context) (*[]*Log, error) {
+	receipt, err := t.receipt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*Log, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		logs = append(logs, &Log{r: t.r, transaction: t, log: log})
+	}
+	return &logs, nil
+}
+
+func (t *Transaction) Type(ctx context.Context) *int32 {
+	txType := int32(t.txn.Type())
+	return &txType
+}
+
+func (t *Transaction) Raw(ctx context.Context) (hexutil.Bytes, error) {
+	var buf bytes.Buffer
+	if err := t.txn.MarshalBinary(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Block represents an Ethereum block
+type Block struct {
+	r *Resolver
+	*types.Block
+
+	lock         sync.Mutex
+	receiptsList types.Receipts // lazily read
+}
+
+func (b *Block) receipts(ctx context.Context) (types.Receipts, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.receiptsList != nil {
+		return b.receiptsList, nil
+	}
+	receipts, err := b.r.api.Receipts(ctx, dbTx(ctx), b.Block)
+	if err != nil {
+		return nil, err
+	}
+	b.receiptsList = receipts
+	return receipts, nil
+}
+
+func (b *Block) Number(ctx context.Context) Long {
+	return Long(b.NumberU64())
+}
+
+func (b *Block) Hash(ctx context.Context) common.Hash {
+	return b.Block.Hash()
+}
+
+func (b *Block) GasLimit(ctx context.Context) Long {
+	return Long(b.Block.GasLimit())
+}
+
+func (b *Block) GasUsed(ctx context.Context) Long {
+	return Long(b.Block.GasUsed())
+}
+
+func (b *Block) BaseFeePerGas(ctx context.Context) *hexutil.Big {
+	if b.BaseFee() == nil {
+		return nil
+	}
+	return (*hexutil.Big)(b.BaseFee())
+}
+
+func (b *Block) Parent(ctx context.Context) (*Block, error) {
+	if b.NumberU64() == 0 {
+		return nil, nil
+	}
+	return b.r.blockByHash(ctx, b.ParentHash())
+}
+
+func (b *Block) Difficulty(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*b.Block.Difficulty())
+}
+
+func (b *Block) Timestamp(ctx context.Context) Long {
+	return Long(b.Time())
+}
+
+func (b *Block) Nonce(ctx context.Context) hexutil.Bytes {
+	nonce := b.Block.Nonce()
+	return nonce[:]
+}
+
+func (b *Block) MixHash(ctx context.Context) common.Hash {
+	return b.Block.MixDigest()
+}
+
+func (b *Block) TransactionsRoot(ctx context.Context) common.Hash {
+	return b.TxHash()
+}
+
+func (b *Block) StateRoot(ctx context.Context) common.Hash {
+	return b.Root()
+}
+
+func (b *Block) ReceiptsRoot(ctx context.Context) common.Hash {
+	return b.ReceiptHash()
+}
+
+func (b *Block) OmmerHash(ctx context.Context) common.Hash {
+	return b.UncleHash()
+}
+
+func (b *Block) OmmerCount(ctx context.Context) *int32 {
+	count := int32(len(b.Uncles()))
+	return &count
+}
+
+func (b *Block) Ommers(ctx context.Context) *[]*Block {
+	ommers := make([]*Block, 0, len(b.Uncles()))
+	for _, uncle := range b.Uncles() {
+		ommers = append(ommers, &Block{r: b.r, Block: types.NewBlockWithHeader(uncle)})
+	}
+	return &ommers
+}
+
+func (b *Block) ExtraData(ctx context.Context) hexutil.Bytes {
+	return b.Extra()
+}
+
+func (b *Block) LogsBloom(ctx context.Context) hexutil.Bytes {
+	bloom := b.Bloom()
+	return bloom[:]
+}
+
+func (b *Block) TotalDifficulty(ctx context.Context) (hexutil.Big, error) {
+	td, err := b.r.api.TotalDifficulty(dbTx(ctx), b.Block)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*td), nil
+}
+
+func (b *Block) Raw(ctx context.Context) (hexutil.Bytes, error) {
+	return rlp.EncodeToBytes(b.Block)
+}
+
+func (b *Block) Miner(ctx context.Context, args struct{ Block *Long }) *Account {
+	return b.r.account(b.Coinbase(), args.Block, b.NumberU64())
+}
+
+func (b *Block) TransactionCount(ctx context.Context) *int32 {
+	count := int32(len(b.Block.Transactions()))
+	return &count
+}
+
+func (b *Block) Transactions(ctx context.Context) *[]*Transaction {
+	txs := make([]*Transaction, 0, len(b.Block.Transactions()))
+	for i, txn := range b.Block.Transactions() {
+		txs = append(txs, &Transaction{r: b.r, txn: txn, block: b, index: uint64(i)})
+	}
+	return &txs
+}
+
+func (b *Block) TransactionAt(ctx context.Context, args struct{ Index int32 }) *Transaction {
+	txs := b.Block.Transactions()
+	if args.Index < 0 || int(args.Index) >= len(txs) {
+		return nil
+	}
+	return &Transaction{r: b.r, txn: txs[args.Index], block: b, index: uint64(args.Index)}
+}
+
+// BlockFilterCriteria encapsulates criteria passed to a `logs` accessor inside a block.
+type BlockFilterCriteria struct {
+	Addresses *[]common.Address // restricts matches to events created by specific contracts
+	Topics    *[][]common.Hash  // restricts matches to particular event topics
+}
+
+func (b *Block) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
+	hash := b.Block.Hash()
+	crit := filters.FilterCriteria{BlockHash: &hash}
+	if args.Filter.Addresses != nil {
+		crit.Addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		crit.Topics = *args.Filter.Topics
+	}
+	return b.r.logs(ctx, crit)
+}
+
+func (b *Block) Account(ctx context.Context, args struct{ Address common.Address }) *Account {
+	return b.r.account(args.Address, nil, b.NumberU64())
+}
+
+// Resolver is the top-level object in the GraphQL hierarchy.
+type Resolver struct {
+	api commands.GraphQLAPI
+}
+
+func (r *Resolver) account(address common.Address, block *Long, defaultBlock uint64) *Account {
+	blockNum := defaultBlock
+	if block != nil {
+		blockNum = uint64(*block)
+	}
+	return &Account{r: r, address: address, blockNum: blockNum}
+}
+
+func (r *Resolver) blockByNumber(ctx context.Context, number rpc.BlockNumber) (*Block, error) {
+	block, err := r.api.BlockByNumber(dbTx(ctx), number)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return &Block{r: r, Block: block}, nil
+}
+
+func (r *Resolver) blockByHash(ctx context.Context, hash common.Hash) (*Block, error) {
+	block, err := r.api.BlockByHash(dbTx(ctx), hash)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return &Block{r: r, Block: block}, nil
+}
+
+// logs wraps the logs into the blocks and transactions which emitted them
+func (r *Resolver) logs(ctx context.Context, crit filters.FilterCriteria) ([]*Log, error) {
+	logs, err := r.api.Logs(ctx, dbTx(ctx), crit)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Log, 0, len(logs))
+	var block *Block
+	for _, log := range logs {
+		if block == nil || block.Block.Hash() != log.BlockHash {
+			if block, err = r.blockByHash(ctx, log.BlockHash); err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, fmt.Errorf("block %x not found", log.BlockHash)
+			}
+		}
+		txs := block.Block.Transactions()
+		if int(log.TxIndex) >= len(txs) {
+			return nil, fmt.Errorf("transaction %d not found in block %d", log.TxIndex, log.BlockNumber)
+		}
+		txn := &Transaction{r: r, txn: txs[log.TxIndex], block: block, index: uint64(log.TxIndex)}
+		result = append(result, &Log{r: r, transaction: txn, log: log})
+	}
+	return result, nil
+}
+
+func (r *Resolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *common.Hash
+}) (*Block, error) {
+	if args.Hash != nil {
+		block, err := r.blockByHash(ctx, *args.Hash)
+		if err != nil || block == nil {
+			return nil, err
+		}
+		if args.Number != nil && block.NumberU64() != uint64(*args.Number) {
+			return nil, errBlockInvariant
+		}
+		return block, nil
+	}
+	number := rpc.LatestBlockNumber
+	if args.Number != nil {
+		if *args.Number < 0 {
+			return nil, fmt.Errorf("negative block number %d", *args.Number)
+		}
+		number = rpc.BlockNumber(*args.Number)
+	}
+	return r.blockByNumber(ctx, number)
+}
+
+func (r *Resolver) Blocks(ctx context.Context, args struct {
+	From *Long
+	To   *Long
+}) ([]*Block, error) {
+	var from uint64
+	if args.From != nil {
+		from = uint64(*args.From)
+	}
+	latest, err := r.blockByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return []*Block{}, nil
+	}
+	to := latest.NumberU64()
+	if args.To != nil && uint64(*args.To) < to {
+		to = uint64(*args.To)
+	}
+	if to < from {
+		return []*Block{}, nil
+	}
+	if to-from >= maxBlocksRange {
+		return nil, fmt.Errorf("block range %d is greater than the maximum %d", to-from+1, maxBlocksRange)
+	}
+	blocks := make([]*Block, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		block, err := r.blockByNumber(ctx, rpc.BlockNumber(n))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func (r *Resolver) Transaction(ctx context.Context, args struct{ Hash common.Hash }) (*Transaction, error) {
+	blockNum, ok, err := r.api.TxnLookup(ctx, dbTx(ctx), args.Hash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	block, err := r.blockByNumber(ctx, rpc.BlockNumber(blockNum))
+	if err != nil || block == nil {
+		return nil, err
+	}
+	for i, txn := range block.Block.Transactions() {
+		if txn.Hash() == args.Hash {
+			return &Transaction{r: r, txn: txn, block: block, index: uint64(i)}, nil
+		}
+	}
+	return nil, nil
+}
+
+// FilterCriteria encapsulates the arguments to `logs` on the root resolver object.
+type FilterCriteria struct {
+	FromBlock *Long             // beginning of the queried range, nil means latest block
+	ToBlock   *Long             // end of the range, nil means latest block
+	Addresses *[]common.Address // restricts matches to events created by specific contracts
+	Topics    *[][]common.Hash  // restricts matches to particular event topics
+}
+
+func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) ([]*Log, error) {
+	var crit filters.FilterCriteria
+	if args.Filter.FromBlock != nil {
+		crit.FromBlock = new(big.Int).SetInt64(int64(*args.Filter.FromBlock))
+	}
+	if args.Filter.ToBlock != nil {
+		crit.ToBlock = new(big.Int).SetInt64(int64(*args.Filter.ToBlock))
+	}
+	if args.Filter.Addresses != nil {
+		crit.Addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		crit.Topics = *args.Filter.Topics
+	}
+	return r.logs(ctx, crit)
+}
+
+func (r *Resolver) ChainID(ctx context.Context) (hexutil.Big, error) {
+	chainConfig, err := r.api.ChainConfig(dbTx(ctx))
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*chainConfig.ChainID), nil
+}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/stretchr/testify/require"
+)
+
+func query(t *testing.T, h http.Handler, q string) map[string]interface{} {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": q})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, urlPath, strings.NewReader(string(body))))
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data   map[string]interface{}
+		Errors []interface{}
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Empty(t, response.Errors)
+	return response.Data
+}
+
+func TestGraphQL(t *testing.T) {
+	db := rpcdaemontest.CreateTestKV(t)
+	base := commands.NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), snapshotsync.NewBlockReader(), false)
+	h, err := CreateHandler([]rpc.API{{Namespace: "graphql", Service: commands.GraphQLAPI(commands.NewGraphQLAPI(base, db))}})
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	// Block 6 holds 32 ether transfers
+	data := query(t, h, `{ block(number: 6) { number transactionCount parent { number } transactions { index status gasUsed value to { address } logs { index } } } }`)
+	block := data["block"].(map[string]interface{})
+	require.Equal(t, float64(6), block["number"])
+	require.Equal(t, float64(32), block["transactionCount"])
+	require.Equal(t, float64(5), block["parent"].(map[string]interface{})["number"])
+	txs := block["transactions"].([]interface{})
+	require.Equal(t, 32, len(txs))
+	tx := txs[3].(map[string]interface{})
+	require.Equal(t, float64(3), tx["index"])
+	require.Equal(t, float64(1), tx["status"])
+	require.Equal(t, float64(21000), tx["gasUsed"])
+	require.Equal(t, "0x0000000000000004000000000000000000000000", tx["to"].(map[string]interface{})["address"])
+	require.Empty(t, tx["logs"])
+
+	// Block 10 emits the only log of the chain
+	data = query(t, h, `{ block(number: 10) { hash logs(filter: {}) { index data account { address } transaction { hash block { number } } } } }`)
+	block = data["block"].(map[string]interface{})
+	logs := block["logs"].([]interface{})
+	require.Equal(t, 1, len(logs))
+	log := logs[0].(map[string]interface{})
+	txHash := log["transaction"].(map[string]interface{})["hash"].(string)
+	require.Equal(t, float64(10), log["transaction"].(map[string]interface{})["block"].(map[string]interface{})["number"])
+
+	data = query(t, h, `{ logs(filter: {fromBlock: 1, toBlock: 10}) { transaction { hash } } }`)
+	require.Equal(t, 1, len(data["logs"].([]interface{})))
+
+	data = query(t, h, `{ transaction(hash: "`+txHash+`") { hash index logs { index } block { hash } from { address transactionCount } } }`)
+	tx = data["transaction"].(map[string]interface{})
+	require.Equal(t, txHash, tx["hash"])
+	require.Equal(t, block["hash"], tx["block"].(map[string]interface{})["hash"])
+	require.Equal(t, 1, len(tx["logs"].([]interface{})))
+
+	// State is read at the block the account is accessed from
+	data = query(t, h, `{ before: block(number: 5) { account(address: "0x0000000000000004000000000000000000000000") { balance } }
+		after: block(number: 6) { account(address: "0x0000000000000004000000000000000000000000") { balance } } }`)
+	require.Equal(t, "0x0", data["before"].(map[string]interface{})["account"].(map[string]interface{})["balance"])
+	require.NotEqual(t, "0x0", data["after"].(map[string]interface{})["account"].(map[string]interface{})["balance"])
+
+	data = query(t, h, `{ blocks(from: 8) { number } chainID }`)
+	require.Equal(t, 3, len(data["blocks"].([]interface{})))
+	require.Equal(t, "0x539", data["chainID"])
+}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// urlPath - path of the GraphQL endpoint on the HTTP server of the JSON-RPC API
+const urlPath = "/graphql"
+
+type handler struct {
+	api    commands.GraphQLAPI
+	schema *graphql.Schema
+}
+
+// CreateHandler returns the handler of GraphQL queries, or nil if the "graphql" API is not in the list
+func CreateHandler(apiList []rpc.API) (http.Handler, error) {
+	var api commands.GraphQLAPI
+	for _, a := range apiList {
+		if a.Namespace != "graphql" {
+			continue
+		}
+		if graphqlAPI, ok := a.Service.(commands.GraphQLAPI); ok {
+			api = graphqlAPI
+		}
+	}
+	if api == nil {
+		return nil, nil
+	}
+	// All resolvers of a query read through a single database transaction, which can't be used concurrently
+	schema, err := graphql.ParseSchema(schema, &Resolver{api: api}, graphql.MaxParallelism(1))
+	if err != nil {
+		return nil, err
+	}
+	return &handler{api: api, schema: schema}, nil
+}
+
+// IsGraphQLRequest reports whether the request is served by the GraphQL handler
+func IsGraphQLRequest(r *http.Request) bool {
+	return r.URL.Path == urlPath
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if r.Method == http.MethodGet {
+		params.Query = r.URL.Query().Get("query")
+		params.OperationName = r.URL.Query().Get("operationName")
+	} else if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.api.BeginRo(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	ctx := context.WithValue(r.Context(), txKey{}, tx)
+	response := h.schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(response.Errors) > 0 {
+		log.Debug("GraphQL query failed", "query", params.Query, "errors", response.Errors)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(responseJSON)
+}
@@ -34,6 +34,11 @@ type TxnReader interface {
 	//TxnByHashDeprecated(ctx context.Context, tx kv.Getter, txnHash common.Hash) (txn types.Transaction, blockHash common.Hash, blockNum, txnIndex uint64, err error)
 }
 
+type ReceiptsReader interface {
+	// RawReceipts returns receipts of the canonical block without derived fields, nil if they are not stored
+	RawReceipts(ctx context.Context, tx kv.Tx, blockHeight uint64) (types.Receipts, error)
+}
+
 type HeaderAndCanonicalReader interface {
 	HeaderReader
 	CanonicalReader
@@ -43,6 +48,7 @@ type BlockAndTxnReader interface {
 	BlockReader
 	//HeaderReader
 	TxnReader
+	ReceiptsReader
 }
 
 type FullBlockReader interface {
@@ -51,4 +57,5 @@ type FullBlockReader interface {
 	HeaderReader
 	TxnReader
 	CanonicalReader
+	ReceiptsReader
 }
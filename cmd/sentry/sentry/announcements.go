@@ -0,0 +1,80 @@
+package sentry
+
+import (
+	"fmt"
+
+	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/protocols/eth"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// maxAnnouncedTxSize - announced transactions larger than this are not requested from peers,
+// the txpool would reject them anyway
+const maxAnnouncedTxSize = 128 * 1024
+
+// wantedTxTypes - announced transactions of other types are not requested from peers
+var wantedTxTypes = map[byte]bool{
+	types.LegacyTxType:     true,
+	types.AccessListTxType: true,
+	types.DynamicFeeTxType: true,
+}
+
+func decodeAnnouncement68(data []byte) (*eth.NewPooledTransactionHashesPacket68, error) {
+	var packet eth.NewPooledTransactionHashesPacket68
+	if err := rlp.DecodeBytes(data, &packet); err != nil {
+		return nil, fmt.Errorf("decode eth/68 announcement: %w", err)
+	}
+	if (len(packet.Types) != len(packet.Hashes)) || (len(packet.Sizes) != len(packet.Hashes)) {
+		return nil, fmt.Errorf("invalid eth/68 announcement: %d types, %d sizes, %d hashes", len(packet.Types), len(packet.Sizes), len(packet.Hashes))
+	}
+	return &packet, nil
+}
+
+// filterAnnouncement68 converts an eth/68 announcement into the eth/66 one understood by the txpool.
+// Oversized transactions and transactions of unknown types are dropped, so the txpool never requests them.
+func filterAnnouncement68(data []byte) ([]byte, error) {
+	packet, err := decodeAnnouncement68(data)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(eth.NewPooledTransactionHashesPacket, 0, len(packet.Hashes))
+	for i, hash := range packet.Hashes {
+		if (packet.Sizes[i] <= maxAnnouncedTxSize) && wantedTxTypes[packet.Types[i]] {
+			hashes = append(hashes, hash)
+		}
+	}
+	return rlp.EncodeToBytes(hashes)
+}
+
+// announcementForPeer returns a function adapting a transaction announcement from the core to the eth version of a peer.
+// eth/66 announcements lack types and sizes required by eth/68 and are not sent to such peers
+// (they get new transactions with TransactionsMsg), eth/68 announcements are sent to older peers as hashes.
+// Other messages are returned unchanged.
+func announcementForPeer(id proto_sentry.MessageId, data []byte) (func(protocol uint) ([]byte, bool), error) {
+	switch id {
+	case proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_66:
+		return func(protocol uint) ([]byte, bool) {
+			return data, protocol < eth.ETH68
+		}, nil
+	case proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68:
+		packet, err := decodeAnnouncement68(data)
+		if err != nil {
+			return nil, err
+		}
+		hashes, err := rlp.EncodeToBytes(eth.NewPooledTransactionHashesPacket(packet.Hashes))
+		if err != nil {
+			return nil, err
+		}
+		return func(protocol uint) ([]byte, bool) {
+			if protocol < eth.ETH68 {
+				return hashes, true
+			}
+			return data, true
+		}, nil
+	default:
+		return func(uint) ([]byte, bool) {
+			return data, true
+		}, nil
+	}
+}
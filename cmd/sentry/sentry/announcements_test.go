@@ -0,0 +1,73 @@
+package sentry
+
+import (
+	"testing"
+
+	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/protocols/eth"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterAnnouncement68(t *testing.T) {
+	hashes := []common.Hash{{1}, {2}, {3}, {4}}
+	data, err := rlp.EncodeToBytes(&eth.NewPooledTransactionHashesPacket68{
+		Types:  []byte{types.LegacyTxType, types.DynamicFeeTxType, types.DynamicFeeTxType, 0x7f},
+		Sizes:  []uint32{100, 200, maxAnnouncedTxSize + 1, 100},
+		Hashes: hashes,
+	})
+	require.Nil(t, err)
+
+	filtered, err := filterAnnouncement68(data)
+	require.Nil(t, err)
+	var packet eth.NewPooledTransactionHashesPacket
+	require.Nil(t, rlp.DecodeBytes(filtered, &packet))
+	assert.Equal(t, eth.NewPooledTransactionHashesPacket(hashes[:2]), packet)
+
+	invalid, err := rlp.EncodeToBytes(&eth.NewPooledTransactionHashesPacket68{
+		Types:  []byte{types.LegacyTxType},
+		Sizes:  []uint32{100, 200},
+		Hashes: hashes[:2],
+	})
+	require.Nil(t, err)
+	_, err = filterAnnouncement68(invalid)
+	assert.NotNil(t, err)
+}
+
+func TestAnnouncementForPeer(t *testing.T) {
+	hashes := []common.Hash{{1}, {2}}
+	data66, err := rlp.EncodeToBytes(eth.NewPooledTransactionHashesPacket(hashes))
+	require.Nil(t, err)
+	data68, err := rlp.EncodeToBytes(&eth.NewPooledTransactionHashesPacket68{
+		Types:  []byte{types.LegacyTxType, types.AccessListTxType},
+		Sizes:  []uint32{100, 200},
+		Hashes: hashes,
+	})
+	require.Nil(t, err)
+
+	forPeer, err := announcementForPeer(proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_66, data66)
+	require.Nil(t, err)
+	data, ok := forPeer(eth.ETH66)
+	assert.True(t, ok)
+	assert.Equal(t, data66, data)
+	_, ok = forPeer(eth.ETH68)
+	assert.False(t, ok)
+
+	forPeer, err = announcementForPeer(proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68, data68)
+	require.Nil(t, err)
+	data, ok = forPeer(eth.ETH67)
+	assert.True(t, ok)
+	assert.Equal(t, data66, data)
+	data, ok = forPeer(eth.ETH68)
+	assert.True(t, ok)
+	assert.Equal(t, data68, data)
+
+	forPeer, err = announcementForPeer(proto_sentry.MessageId_NEW_BLOCK_66, []byte{1})
+	require.Nil(t, err)
+	data, ok = forPeer(eth.ETH68)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{1}, data)
+}
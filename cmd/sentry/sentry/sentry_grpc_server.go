@@ -22,11 +22,13 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
 	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
 	proto_types "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/core/forkid"
 	"github.com/ledgerwatch/erigon/eth/protocols/eth"
+	"github.com/ledgerwatch/erigon/eth/protocols/snap"
 	"github.com/ledgerwatch/erigon/p2p"
 	"github.com/ledgerwatch/erigon/p2p/dnsdisc"
 	"github.com/ledgerwatch/erigon/p2p/enode"
@@ -54,6 +56,7 @@ type PeerInfo struct {
 	deadlines []time.Time // Request deadlines
 	height    uint64
 	rw        p2p.MsgReadWriter
+	protocol  uint // negotiated eth version
 
 	removed    chan struct{} // close this channel on remove
 	ctx        context.Context
@@ -65,10 +68,10 @@ type PeerInfo struct {
 	tasks chan func()
 }
 
-func NewPeerInfo(peer *p2p.Peer, rw p2p.MsgReadWriter) *PeerInfo {
+func NewPeerInfo(peer *p2p.Peer, rw p2p.MsgReadWriter, protocol uint) *PeerInfo {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	p := &PeerInfo{peer: peer, rw: rw, removed: make(chan struct{}), tasks: make(chan func(), 16), ctx: ctx, ctxCancel: cancel}
+	p := &PeerInfo{peer: peer, rw: rw, protocol: protocol, removed: make(chan struct{}), tasks: make(chan func(), 16), ctx: ctx, ctxCancel: cancel}
 	go func() { // each peer has own worker, then slow
 		for f := range p.tasks {
 			f()
@@ -173,7 +176,7 @@ func ConvertH512ToPeerID(h512 *proto_types.H512) [64]byte {
 func makeP2PServer(
 	p2pConfig p2p.Config,
 	genesisHash common.Hash,
-	protocol p2p.Protocol,
+	protocols []p2p.Protocol,
 ) (*p2p.Server, error) {
 	var urls []string
 	chainConfig := params.ChainConfigByGenesisHash(genesisHash)
@@ -188,7 +191,7 @@ func makeP2PServer(
 		p2pConfig.BootstrapNodes = bootstrapNodes
 		p2pConfig.BootstrapNodesV5 = bootstrapNodes
 	}
-	p2pConfig.Protocols = []p2p.Protocol{protocol}
+	p2pConfig.Protocols = protocols
 	return &p2p.Server{Config: p2pConfig}, nil
 }
 
@@ -383,6 +386,10 @@ func runPeer(
 			}
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
 		case eth.GetNodeDataMsg:
+			if protocol >= eth.ETH67 {
+				msg.Discard()
+				return fmt.Errorf("unexpected GetNodeData message in %s", eth.ProtocolToString[protocol])
+			}
 			if !hasSubscribers(eth.ToProto[protocol][msg.Code]) {
 				continue
 			}
@@ -431,6 +438,29 @@ func runPeer(
 			}
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
 		case eth.NewPooledTransactionHashesMsg:
+			if protocol >= eth.ETH68 {
+				// the txpool subscribes to eth/66 announcements, it gets them filtered by types and sizes
+				legacyID := proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_66
+				if !hasSubscribers(eth.ToProto[protocol][msg.Code]) && !hasSubscribers(legacyID) {
+					continue
+				}
+				b := make([]byte, msg.Size)
+				if _, err := io.ReadFull(msg.Payload, b); err != nil {
+					log.Error(fmt.Sprintf("%s: reading msg into bytes: %v", peerID, err))
+				}
+				if hasSubscribers(eth.ToProto[protocol][msg.Code]) {
+					send(eth.ToProto[protocol][msg.Code], peerID, b)
+				}
+				if hasSubscribers(legacyID) {
+					hashes, err := filterAnnouncement68(b)
+					if err != nil {
+						msg.Discard()
+						return err
+					}
+					send(legacyID, peerID, hashes)
+				}
+				break
+			}
 			if !hasSubscribers(eth.ToProto[protocol][msg.Code]) {
 				continue
 			}
@@ -517,11 +547,20 @@ func NewGrpcServer(ctx context.Context, dialCandidates enode.Iterator, readNodeI
 		peersStreams: NewPeersStreams(),
 	}
 
-	if protocol != eth.ETH66 {
+	if (protocol < eth.ETH66) || (protocol > eth.ETH68) {
 		panic(fmt.Errorf("unexpected p2p protocol: %d", protocol))
 	}
 
-	ss.Protocol = p2p.Protocol{
+	// protocol is the highest supported version, devp2p negotiates the highest version supported by both sides
+	for version := uint(eth.ETH66); version < protocol; version++ {
+		ss.olderProtocols = append(ss.olderProtocols, ss.makeProtocol(ctx, version, nil, readNodeInfo))
+	}
+	ss.Protocol = ss.makeProtocol(ctx, protocol, dialCandidates, readNodeInfo)
+	return ss
+}
+
+func (ss *GrpcServer) makeProtocol(ctx context.Context, protocol uint, dialCandidates enode.Iterator, readNodeInfo func() *eth.NodeInfo) p2p.Protocol {
+	return p2p.Protocol{
 		Name:           eth.ProtocolName,
 		Version:        protocol,
 		Length:         17,
@@ -534,7 +573,7 @@ func NewGrpcServer(ctx context.Context, dialCandidates enode.Iterator, readNodeI
 			}
 			log.Trace(fmt.Sprintf("[%s] Start with peer", peerID))
 
-			peerInfo := NewPeerInfo(peer, rw)
+			peerInfo := NewPeerInfo(peer, rw, protocol)
 
 			defer ss.GoodPeers.Delete(peerID)
 			err := handShake(ctx, ss.GetStatus(), peerID, rw, protocol, protocol, func(bestHash common.Hash) error {
@@ -569,8 +608,6 @@ func NewGrpcServer(ctx context.Context, dialCandidates enode.Iterator, readNodeI
 		},
 		//Attributes: []enr.Entry{eth.CurrentENREntry(chainConfig, genesisHash, headHeight)},
 	}
-
-	return ss
 }
 
 // Sentry creates and runs standalone sentry
@@ -593,7 +630,9 @@ func Sentry(ctx context.Context, datadir string, sentryAddr string, discoveryDNS
 type GrpcServer struct {
 	proto_sentry.UnimplementedSentryServer
 	ctx                  context.Context
-	Protocol             p2p.Protocol
+	Protocol             p2p.Protocol   // the highest supported eth version
+	olderProtocols       []p2p.Protocol // lower eth versions for peers which don't support the highest one
+	snapProtocol         *p2p.Protocol
 	discoveryDNS         []string
 	GoodPeers            sync.Map
 	statusData           *proto_sentry.StatusData
@@ -607,6 +646,13 @@ type GrpcServer struct {
 	p2p                  *p2p.Config
 }
 
+// ServeSnap makes the sentry serve snap/1 requests of other nodes from the state in db.
+// Only an embedded sentry has access to the db, it must be called before the p2p server is started by SetStatus.
+func (ss *GrpcServer) ServeSnap(db kv.RoDB) {
+	protocol := snap.MakeProtocol(ss.ctx, db)
+	ss.snapProtocol = &protocol
+}
+
 func (ss *GrpcServer) rangePeers(f func(peerInfo *PeerInfo) bool) {
 	ss.GoodPeers.Range(func(key, value interface{}) bool {
 		peerInfo, _ := value.(*PeerInfo)
@@ -656,7 +702,7 @@ func (ss *GrpcServer) writePeer(logPrefix string, peerInfo *PeerInfo, msgcode ui
 
 func (ss *GrpcServer) startSync(ctx context.Context, bestHash common.Hash, peerID [64]byte) error {
 	switch ss.Protocol.Version {
-	case eth.ETH66:
+	case eth.ETH66, eth.ETH67, eth.ETH68:
 		b, err := rlp.EncodeToBytes(&eth.GetBlockHeadersPacket66{
 			RequestId: rand.Uint64(),
 			GetBlockHeadersPacket: &eth.GetBlockHeadersPacket{
@@ -719,9 +765,18 @@ func (ss *GrpcServer) findPeer(minBlock uint64) (*PeerInfo, bool) {
 	return foundPeerInfo, maxPermits > 0
 }
 
+// messageCode returns the wire code of a message from the core,
+// the core uses eth/66 message ids with any negotiated version, except for eth/68 announcements
+func messageCode(id proto_sentry.MessageId) uint64 {
+	if id == proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68 {
+		return eth.NewPooledTransactionHashesMsg
+	}
+	return eth.FromProto[eth.ETH66][id]
+}
+
 func (ss *GrpcServer) SendMessageByMinBlock(_ context.Context, inreq *proto_sentry.SendMessageByMinBlockRequest) (*proto_sentry.SentPeers, error) {
 	reply := &proto_sentry.SentPeers{}
-	msgcode := eth.FromProto[ss.Protocol.Version][inreq.Data.Id]
+	msgcode := messageCode(inreq.Data.Id)
 	if msgcode != eth.GetBlockHeadersMsg &&
 		msgcode != eth.GetBlockBodiesMsg &&
 		msgcode != eth.GetPooledTransactionsMsg {
@@ -742,7 +797,7 @@ func (ss *GrpcServer) SendMessageByMinBlock(_ context.Context, inreq *proto_sent
 
 func (ss *GrpcServer) SendMessageById(_ context.Context, inreq *proto_sentry.SendMessageByIdRequest) (*proto_sentry.SentPeers, error) {
 	reply := &proto_sentry.SentPeers{}
-	msgcode := eth.FromProto[ss.Protocol.Version][inreq.Data.Id]
+	msgcode := messageCode(inreq.Data.Id)
 	if msgcode != eth.GetBlockHeadersMsg &&
 		msgcode != eth.BlockHeadersMsg &&
 		msgcode != eth.BlockBodiesMsg &&
@@ -762,7 +817,15 @@ func (ss *GrpcServer) SendMessageById(_ context.Context, inreq *proto_sentry.Sen
 		return reply, nil
 	}
 
-	ss.writePeer("sendMessageById", peerInfo, msgcode, inreq.Data.Data, 0)
+	forPeer, err := announcementForPeer(inreq.Data.Id, inreq.Data.Data)
+	if err != nil {
+		return reply, err
+	}
+	data, ok := forPeer(peerInfo.protocol)
+	if !ok {
+		return reply, nil
+	}
+	ss.writePeer("sendMessageById", peerInfo, msgcode, data, 0)
 	reply.Peers = []*proto_types.H512{inreq.PeerId}
 	return reply, nil
 }
@@ -770,7 +833,7 @@ func (ss *GrpcServer) SendMessageById(_ context.Context, inreq *proto_sentry.Sen
 func (ss *GrpcServer) SendMessageToRandomPeers(ctx context.Context, req *proto_sentry.SendMessageToRandomPeersRequest) (*proto_sentry.SentPeers, error) {
 	reply := &proto_sentry.SentPeers{}
 
-	msgcode := eth.FromProto[ss.Protocol.Version][req.Data.Id]
+	msgcode := messageCode(req.Data.Id)
 	if msgcode != eth.NewBlockMsg &&
 		msgcode != eth.NewBlockHashesMsg &&
 		msgcode != eth.NewPooledTransactionHashesMsg &&
@@ -787,12 +850,21 @@ func (ss *GrpcServer) SendMessageToRandomPeers(ctx context.Context, req *proto_s
 		amount = req.MaxPeers
 	}
 
+	forPeer, err := announcementForPeer(req.Data.Id, req.Data.Data)
+	if err != nil {
+		return reply, err
+	}
+
 	// Send the block to a subset of our peers
 	sendToAmount := int(math.Sqrt(float64(amount)))
 	i := 0
 	var lastErr error
 	ss.rangePeers(func(peerInfo *PeerInfo) bool {
-		ss.writePeer("sendMessageToRandomPeers", peerInfo, msgcode, req.Data.Data, 0)
+		data, ok := forPeer(peerInfo.protocol)
+		if !ok {
+			return true
+		}
+		ss.writePeer("sendMessageToRandomPeers", peerInfo, msgcode, data, 0)
 		reply.Peers = append(reply.Peers, gointerfaces.ConvertHashToH512(peerInfo.ID()))
 		i++
 		return i < sendToAmount
@@ -803,16 +875,25 @@ func (ss *GrpcServer) SendMessageToRandomPeers(ctx context.Context, req *proto_s
 func (ss *GrpcServer) SendMessageToAll(ctx context.Context, req *proto_sentry.OutboundMessageData) (*proto_sentry.SentPeers, error) {
 	reply := &proto_sentry.SentPeers{}
 
-	msgcode := eth.FromProto[ss.Protocol.Version][req.Id]
+	msgcode := messageCode(req.Id)
 	if msgcode != eth.NewBlockMsg &&
 		msgcode != eth.NewPooledTransactionHashesMsg && // to broadcast new local transactions
 		msgcode != eth.NewBlockHashesMsg {
 		return reply, fmt.Errorf("sendMessageToAll not implemented for message Id: %s", req.Id)
 	}
 
+	forPeer, err := announcementForPeer(req.Id, req.Data)
+	if err != nil {
+		return reply, err
+	}
+
 	var lastErr error
 	ss.rangePeers(func(peerInfo *PeerInfo) bool {
-		ss.writePeer("SendMessageToAll", peerInfo, msgcode, req.Data, 0)
+		data, ok := forPeer(peerInfo.protocol)
+		if !ok {
+			return true
+		}
+		ss.writePeer("SendMessageToAll", peerInfo, msgcode, data, 0)
 		reply.Peers = append(reply.Peers, gointerfaces.ConvertHashToH512(peerInfo.ID()))
 		return true
 	})
@@ -821,8 +902,9 @@ func (ss *GrpcServer) SendMessageToAll(ctx context.Context, req *proto_sentry.Ou
 
 func (ss *GrpcServer) HandShake(context.Context, *emptypb.Empty) (*proto_sentry.HandShakeReply, error) {
 	reply := &proto_sentry.HandShakeReply{}
+	// the core uses eth/66 message ids with any negotiated version
 	switch ss.Protocol.Version {
-	case eth.ETH66:
+	case eth.ETH66, eth.ETH67, eth.ETH68:
 		reply.Protocol = proto_sentry.Protocol_ETH66
 	}
 	return reply, nil
@@ -849,7 +931,11 @@ func (ss *GrpcServer) SetStatus(ctx context.Context, statusData *proto_sentry.St
 			}
 		}
 
-		srv, err := makeP2PServer(*ss.p2p, genesisHash, ss.Protocol)
+		protocols := append(append([]p2p.Protocol{}, ss.olderProtocols...), ss.Protocol)
+		if ss.snapProtocol != nil {
+			protocols = append(protocols, *ss.snapProtocol)
+		}
+		srv, err := makeP2PServer(*ss.p2p, genesisHash, protocols)
 		if err != nil {
 			return reply, err
 		}
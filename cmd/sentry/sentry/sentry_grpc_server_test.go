@@ -11,12 +11,15 @@ import (
 	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/forkid"
 	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/protocols/eth"
 	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/p2p/enode"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +54,8 @@ func testSentryServer(db kv.Getter, genesis *core.Genesis, genesisHash common.Ha
 // Tests that peers are correctly accepted (or rejected) based on the advertised
 // fork IDs in the protocol handshake.
 func TestForkIDSplit66(t *testing.T) { testForkIDSplit(t, eth.ETH66) }
+func TestForkIDSplit67(t *testing.T) { testForkIDSplit(t, eth.ETH67) }
+func TestForkIDSplit68(t *testing.T) { testForkIDSplit(t, eth.ETH68) }
 
 func testForkIDSplit(t *testing.T, protocol uint) {
 	var (
@@ -177,3 +182,36 @@ func TestSentryServerImpl_SetStatusInitPanic(t *testing.T) {
 		t.Fatalf("error expected")
 	}
 }
+
+// Tests that announcements of the txpool reach eth/68 peers with types and sizes, and older peers as hashes.
+func TestSendMessageToAllAnnouncement68(t *testing.T) {
+	ss := &GrpcServer{ctx: context.Background()}
+	peers := map[uint]*p2p.MsgPipeRW{}
+	for i, protocol := range []uint{eth.ETH66, eth.ETH68} {
+		local, remote := p2p.MsgPipe()
+		defer local.Close()
+		defer remote.Close()
+		pubkey := [64]byte{byte(i + 1)}
+		peerInfo := NewPeerInfo(p2p.NewPeer(enode.ID{byte(i + 1)}, pubkey, "peer", nil), local, protocol)
+		ss.GoodPeers.Store(peerInfo.ID(), peerInfo)
+		peers[protocol] = remote
+	}
+
+	hashes := []common.Hash{{1}, {2}}
+	txTypes := []byte{types.LegacyTxType, types.DynamicFeeTxType}
+	sizes := []uint32{100, 200}
+	data := types2.EncodeAnnouncements(txTypes, sizes, append(hashes[0].Bytes(), hashes[1].Bytes()...), nil)
+	reply, err := ss.SendMessageToAll(context.Background(), &proto_sentry.OutboundMessageData{
+		Id:   proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68,
+		Data: data,
+	})
+	require.NoError(t, err)
+	require.Len(t, reply.Peers, 2)
+
+	require.NoError(t, p2p.ExpectMsg(peers[eth.ETH68], eth.NewPooledTransactionHashesMsg, &eth.NewPooledTransactionHashesPacket68{
+		Types:  txTypes,
+		Sizes:  sizes,
+		Hashes: hashes,
+	}))
+	require.NoError(t, p2p.ExpectMsg(peers[eth.ETH66], eth.NewPooledTransactionHashesMsg, eth.NewPooledTransactionHashesPacket(hashes)))
+}
@@ -357,12 +357,12 @@ type MultyClient struct {
 	networkId   uint64
 	db          kv.RwDB
 	Engine      consensus.Engine
-	blockReader interfaces.HeaderAndCanonicalReader
+	blockReader interfaces.FullBlockReader
 }
 
 func NewMultyClient(db kv.RwDB, nodeName string, chainConfig *params.ChainConfig,
 	genesisHash common.Hash, engine consensus.Engine, networkID uint64, sentries []direct.SentryClient,
-	window int, blockReader interfaces.HeaderAndCanonicalReader) (*MultyClient, error) {
+	window int, blockReader interfaces.FullBlockReader) (*MultyClient, error) {
 	hd := headerdownload.NewHeaderDownload(
 		512,       /* anchorLimit */
 		1024*1024, /* linkLimit */
@@ -590,8 +590,8 @@ func (cs *MultyClient) blockBodies66(inreq *proto_sentry.InboundMessage, sentry
 	if err := rlp.DecodeBytes(inreq.Data, &request); err != nil {
 		return fmt.Errorf("decode BlockBodiesPacket66: %w", err)
 	}
-	txs, uncles := request.BlockRawBodiesPacket.Unpack()
-	cs.Bd.DeliverBodies(txs, uncles, uint64(len(inreq.Data)), ConvertH512ToPeerID(inreq.PeerId))
+	txs, uncles, withdrawals := request.BlockRawBodiesPacket.Unpack()
+	cs.Bd.DeliverBodies(txs, uncles, withdrawals, uint64(len(inreq.Data)), ConvertH512ToPeerID(inreq.PeerId))
 	return nil
 }
 
@@ -687,7 +687,7 @@ func (cs *MultyClient) getReceipts66(ctx context.Context, inreq *proto_sentry.In
 		return err
 	}
 	defer tx.Rollback()
-	receipts, err := eth.AnswerGetReceiptsQuery(tx, query.GetReceiptsPacket)
+	receipts, err := eth.AnswerGetReceiptsQuery(ctx, tx, query.GetReceiptsPacket, cs.blockReader)
 	if err != nil {
 		return err
 	}
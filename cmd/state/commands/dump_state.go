@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dumpOutput         string
+	dumpFormat         string
+	dumpExcludeCode    bool
+	dumpExcludeStorage bool
+	dumpLatest         bool
+)
+
+func init() {
+	withBlock(dumpStateCmd)
+	withDataDir(dumpStateCmd)
+	dumpStateCmd.Flags().BoolVar(&dumpLatest, "latest", false, "dump the state after the last executed block, --block is ignored")
+	dumpStateCmd.Flags().StringVar(&dumpOutput, "output", "", "path of the dump file, stdout if omitted")
+	dumpStateCmd.Flags().StringVar(&dumpFormat, "format", "json", "json - one JSON object per account and line, rlp - stream of RLP encoded accounts")
+	dumpStateCmd.Flags().BoolVar(&dumpExcludeCode, "nocode", false, "do not dump contract code")
+	dumpStateCmd.Flags().BoolVar(&dumpExcludeStorage, "nostorage", false, "do not dump contract storage")
+	rootCmd.AddCommand(dumpStateCmd)
+}
+
+var dumpStateCmd = &cobra.Command{
+	Use:   "dumpState",
+	Short: "Dumps accounts, code and storage after the given block, rewinding through the changesets when it is not the last executed block",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return DumpState(cmd.Context(), log.New(), chaindata, block, dumpLatest, dumpOutput, dumpFormat, dumpExcludeCode, dumpExcludeStorage)
+	},
+}
+
+// DumpState writes the state after blockNum to the output file in the given format
+func DumpState(ctx context.Context, logger log.Logger, chaindata string, blockNum uint64, latest bool, output, format string, excludeCode, excludeStorage bool) error {
+	if format != "json" && format != "rlp" {
+		return fmt.Errorf("unknown dump format %q, expected json or rlp", format)
+	}
+	db, err := kv2.NewMDBX(logger).Path(chaindata).Readonly().Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if latest {
+		if blockNum, err = stages.GetStageProgress(tx, stages.Execution); err != nil {
+			return err
+		}
+	}
+	if err = state.CheckDumpBlock(tx, blockNum); err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if output != "" {
+		if out, err = os.Create(output); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	w := bufio.NewWriter(out)
+	dumper := state.NewDumper(tx, blockNum)
+	if format == "rlp" {
+		err = dumper.RLPDump(excludeCode, excludeStorage, w)
+	} else {
+		err = dumper.IterativeDump(excludeCode, excludeStorage, json.NewEncoder(w))
+	}
+	if err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if output != "" {
+		if err = out.Sync(); err != nil {
+			return err
+		}
+	}
+	logger.Info("State dumped", "block", blockNum, "format", format)
+	return nil
+}
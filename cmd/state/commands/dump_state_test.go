@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/log/v3"
+)
+
+func TestDumpState(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	dir := t.TempDir()
+	chaindata := filepath.Join(dir, "chaindata")
+	addr := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	// block 1 gives the account a balance of 1, block 2 of 2
+	db := kv2.NewMDBX(logger).Path(chaindata).MustOpen()
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		original := new(accounts.Account)
+		for blockNum := uint64(1); blockNum <= 2; blockNum++ {
+			account := accounts.NewAccount()
+			account.Initialised = true
+			account.Balance.SetUint64(blockNum)
+			w := state.NewPlainStateWriter(tx, tx, blockNum)
+			if err := w.UpdateAccountData(addr, original, &account); err != nil {
+				return err
+			}
+			if err := w.WriteChangeSets(); err != nil {
+				return err
+			}
+			if err := w.WriteHistory(); err != nil {
+				return err
+			}
+			original = &account
+		}
+		return stages.SaveStageProgress(tx, stages.Execution, 2)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	for _, tt := range []struct {
+		block   uint64
+		latest  bool
+		balance string
+	}{
+		{block: 1, balance: "1"},
+		{block: 2, balance: "2"},
+		{block: 1, latest: true, balance: "2"},
+	} {
+		output := filepath.Join(dir, "dump.json")
+		if err := DumpState(ctx, logger, chaindata, tt.block, tt.latest, output, "json", false, false); err != nil {
+			t.Fatal(err)
+		}
+		// the first line is the root, then one account per line
+		var accs []state.DumpAccount
+		f, err := os.Open(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var acc state.DumpAccount
+			if err = json.Unmarshal(scanner.Bytes(), &acc); err != nil {
+				t.Fatal(err)
+			}
+			if acc.Address != nil {
+				accs = append(accs, acc)
+			}
+		}
+		f.Close()
+		if len(accs) != 1 || *accs[0].Address != addr || accs[0].Balance != tt.balance {
+			t.Errorf("wrong json dump of block %d (latest %t): %+v", tt.block, tt.latest, accs)
+		}
+
+		output = filepath.Join(dir, "dump.rlp")
+		if err = DumpState(ctx, logger, chaindata, tt.block, tt.latest, output, "rlp", false, false); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var acc state.RLPDumpAccount
+		stream := rlp.NewStream(bytes.NewReader(data), 0)
+		if err = stream.Decode(&acc); err != nil {
+			t.Fatal(err)
+		}
+		if acc.Address != addr || acc.Balance.String() != tt.balance {
+			t.Errorf("wrong rlp dump of block %d (latest %t): %+v", tt.block, tt.latest, acc)
+		}
+		if err = stream.Decode(&acc); err == nil {
+			t.Errorf("more than one account in the rlp dump of block %d", tt.block)
+		}
+	}
+
+	if err := DumpState(ctx, logger, chaindata, 3, false, "", "json", false, false); err == nil {
+		t.Errorf("expected error for a block which is not executed")
+	}
+	if err := DumpState(ctx, logger, chaindata, 2, false, "", "xml", false, false); err == nil {
+		t.Errorf("expected error for an unknown format")
+	}
+}
@@ -384,6 +384,14 @@ var (
 		Name:  "rpc.accessList",
 		Usage: "Specify granular (method-by-method) API allowlist",
 	}
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable the GraphQL endpoint (served at /graphql by the HTTP server)",
+	}
+	RpcRateLimitsFlag = cli.StringFlag{
+		Name:  "rpc.rateLimits",
+		Usage: "Specify per-client rate limits and costs of methods",
+	}
 
 	RpcGasCapFlag = cli.UintFlag{
 		Name:  "rpc.gascap",
@@ -415,6 +423,14 @@ var (
 		Usage: "Sets a limit on traces that can be returned in trace_filter",
 		Value: 200,
 	}
+	RpcLogsMaxRangeFlag = cli.Uint64Flag{
+		Name:  "rpc.logs.maxrange",
+		Usage: "Sets a limit on amount of blocks eth_getLogs may query, 0 - no limit",
+	}
+	RpcLogsMaxResultsFlag = cli.Uint64Flag{
+		Name:  "rpc.logs.maxresults",
+		Usage: "Sets a limit on amount of logs eth_getLogs may return and on the page size of erigon_getLogs, 0 - no limit",
+	}
 
 	HTTPPathPrefixFlag = cli.StringFlag{
 		Name:  "http.rpcprefix",
@@ -657,6 +673,11 @@ var (
 		Value: 3,
 		Usage: "amount of files to download in parallel. If network has enough seeders 1-3 slot enough, if network has lack of seeders increase to 5-7 (too big value will slow down everything).",
 	}
+	TorrentWebSeedsFlag = cli.StringFlag{
+		Name:  "torrent.webseeds",
+		Value: "",
+		Usage: "comma separated list of snapshots mirrors, used in addition to BitTorrent peers. Example: https://example.com/snapshots/,file:///mnt/snapshots,s3://bucket/snapshots?endpoint=minio:9000",
+	}
 	TorrentPortFlag = cli.IntFlag{
 		Name:  "torrent.port",
 		Value: 42069,
@@ -694,6 +715,12 @@ var (
 		Name:  "bor.withoutheimdall",
 		Usage: "Run without Heimdall service (for testing purpose)",
 	}
+
+	// HeimdallMockFlag in-process mock heimdall (for dev chains and testing purpose)
+	HeimdallMockFlag = cli.StringFlag{
+		Name:  "bor.heimdallmock",
+		Usage: "Path to the JSON file with spans and state-sync events served by an in-process mock instead of Heimdall service",
+	}
 )
 
 var MetricFlags = []cli.Flag{MetricsEnabledFlag, MetricsEnabledExpensiveFlag, MetricsHTTPFlag, MetricsPortFlag}
@@ -829,8 +856,8 @@ func NewP2PConfig(
 ) (*p2p.Config, error) {
 	var enodeDBPath string
 	switch protocol {
-	case eth.ETH66:
-		enodeDBPath = filepath.Join(datadir, "nodes", "eth66")
+	case eth.ETH66, eth.ETH67, eth.ETH68:
+		enodeDBPath = filepath.Join(datadir, "nodes", eth.ProtocolToString[protocol])
 	default:
 		return nil, fmt.Errorf("unknown protocol: %v", protocol)
 	}
@@ -1280,6 +1307,7 @@ func setParlia(ctx *cli.Context, cfg *params.ParliaConfig, datadir string) {
 func setBorConfig(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.HeimdallURL = ctx.GlobalString(HeimdallURLFlag.Name)
 	cfg.WithoutHeimdall = ctx.GlobalBool(WithoutHeimdallFlag.Name)
+	cfg.HeimdallMock = ctx.GlobalString(HeimdallMockFlag.Name)
 }
 
 func setMiner(ctx *cli.Context, cfg *params.MiningConfig) {
@@ -1397,6 +1425,7 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *node.Config, cfg *ethconfig.Conf
 			ctx.GlobalInt(TorrentPortFlag.Name),
 			ctx.GlobalInt(TorrentConnsPerFileFlag.Name),
 			ctx.GlobalInt(TorrentDownloadSlotsFlag.Name),
+			SplitAndTrim(ctx.GlobalString(TorrentWebSeedsFlag.Name)),
 		)
 		if err != nil {
 			panic(err)
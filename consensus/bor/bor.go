@@ -695,6 +695,15 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header, s
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
 // rewards given.
 func (c *Bor) Finalize(config *params.ChainConfig, header *types.Header, state *state.IntraBlockState, txs types.Transactions, uncles []*types.Header, r types.Receipts, e consensus.EpochReader, chain consensus.ChainHeaderReader, syscall consensus.SystemCall) (types.Transactions, types.Receipts, error) {
+	if _, err := c.finalize(header, state, chain, syscall); err != nil {
+		return nil, types.Receipts{}, err
+	}
+	return nil, types.Receipts{}, nil
+}
+
+// finalize - finalizes the block as Finalize does, returns the state-sync events committed by the block
+func (c *Bor) finalize(header *types.Header, state *state.IntraBlockState, chain consensus.ChainHeaderReader, syscall consensus.SystemCall) ([]*EventRecordWithTime, error) {
+	var committed []*EventRecordWithTime
 	var err error
 	headerNumber := header.Number.Uint64()
 	if headerNumber%c.config.Sprint == 0 {
@@ -702,34 +711,53 @@ func (c *Bor) Finalize(config *params.ChainConfig, header *types.Header, state *
 		// check and commit span
 		if err := c.checkAndCommitSpan(state, header, cx, syscall); err != nil {
 			log.Error("Error while committing span", "err", err)
-			return nil, types.Receipts{}, err
+			return nil, err
 		}
 
 		if !c.WithoutHeimdall {
 			// commit states
-			_, err = c.CommitStates(state, header, cx, syscall)
+			committed, err = c.CommitStates(state, header, cx, syscall)
 			if err != nil {
 				log.Error("Error while committing states", "err", err)
-				return nil, types.Receipts{}, err
+				return nil, err
 			}
 		}
 	}
 
 	if err = c.changeContractCodeIfNeeded(headerNumber, state); err != nil {
 		log.Error("Error changing contract code", "err", err)
-		return nil, types.Receipts{}, err
+		return nil, err
 	}
 
 	// No block rewards in PoA, so the state remains as is and uncles are dropped
 	// header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number.Uint64()))
 	header.UncleHash = types.CalcUncleHash(nil)
+	return committed, nil
+}
 
-	// Set state sync data to blockchain
-	// bc := chain.(*core.BlockChain)
-	// bc.SetStateSync(stateSyncData)
+// StateSyncRecorder - Bor engine recording the state-sync events committed by the blocks it finalizes,
+// so that the caller executing a block gets them along with its results (see WriteStateSyncEvents).
+// Not safe for concurrent use, make one per executed block.
+type StateSyncRecorder struct {
+	*Bor
+	committed []*EventRecordWithTime
+}
+
+func NewStateSyncRecorder(c *Bor) *StateSyncRecorder { return &StateSyncRecorder{Bor: c} }
+
+// Finalize implements consensus.Engine, recording the state-sync events committed by the block
+func (r *StateSyncRecorder) Finalize(config *params.ChainConfig, header *types.Header, state *state.IntraBlockState, txs types.Transactions, uncles []*types.Header, receipts types.Receipts, e consensus.EpochReader, chain consensus.ChainHeaderReader, syscall consensus.SystemCall) (types.Transactions, types.Receipts, error) {
+	committed, err := r.finalize(header, state, chain, syscall)
+	if err != nil {
+		return nil, types.Receipts{}, err
+	}
+	r.committed = append(r.committed, committed...)
 	return nil, types.Receipts{}, nil
 }
 
+// Committed returns the state-sync events committed by the blocks finalized by the recorder
+func (r *StateSyncRecorder) Committed() []*EventRecordWithTime { return r.committed }
+
 func decodeGenesisAlloc(i interface{}) (core.GenesisAlloc, error) {
 	var alloc core.GenesisAlloc
 	b, err := json.Marshal(i)
@@ -1155,14 +1183,13 @@ func (c *Bor) fetchAndCommitSpan(
 	return err
 }
 
-// CommitStates commit states
+// CommitStates commit states, returns the committed state-sync events
 func (c *Bor) CommitStates(
 	state *state.IntraBlockState,
 	header *types.Header,
 	chain chainContext,
 	syscall consensus.SystemCall,
-) ([]*types.StateSyncData, error) {
-	stateSyncs := make([]*types.StateSyncData, 0)
+) ([]*EventRecordWithTime, error) {
 	number := header.Number.Uint64()
 	_lastStateID, err := c.GenesisContractsClient.LastStateId(header, state, chain, c, syscall)
 	if err != nil {
@@ -1187,6 +1214,7 @@ func (c *Bor) CommitStates(
 	}
 
 	chainID := c.chainConfig.ChainID.String()
+	var committed []*EventRecordWithTime
 	for _, eventRecord := range eventRecords {
 		if eventRecord.ID <= lastStateID {
 			continue
@@ -1196,20 +1224,13 @@ func (c *Bor) CommitStates(
 			break
 		}
 
-		stateData := types.StateSyncData{
-			ID:       eventRecord.ID,
-			Contract: eventRecord.Contract,
-			Data:     hex.EncodeToString(eventRecord.Data),
-			TxHash:   eventRecord.TxHash,
-		}
-		stateSyncs = append(stateSyncs, &stateData)
-
 		if err := c.GenesisContractsClient.CommitState(eventRecord, state, header, chain, c, syscall); err != nil {
 			return nil, err
 		}
+		committed = append(committed, eventRecord)
 		lastStateID++
 	}
-	return stateSyncs, nil
+	return committed, nil
 }
 
 func validateEventRecord(eventRecord *EventRecordWithTime, number uint64, to time.Time, lastStateID uint64, chainID string) error {
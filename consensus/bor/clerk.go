@@ -1,10 +1,15 @@
 package bor
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 )
 
@@ -47,3 +52,116 @@ func (e *EventRecordWithTime) BuildEventRecord() *EventRecord {
 		ChainID:  e.ChainID,
 	}
 }
+
+// StateSyncEvent is an event record committed to the chain by the state-sync of a block
+type StateSyncEvent struct {
+	EventRecordWithTime
+	BlockNumber uint64 `json:"block_number" yaml:"block_number"`
+}
+
+var stateSyncEventPrefix = []byte("event-")
+
+func stateSyncEventKey(id uint64) []byte {
+	key := make([]byte, len(stateSyncEventPrefix)+8)
+	copy(key, stateSyncEventPrefix)
+	binary.BigEndian.PutUint64(key[len(stateSyncEventPrefix):], id)
+	return key
+}
+
+// WriteStateSyncEvents inserts the events committed by the block into the database,
+// the events of a block re-executed after a reorg replace the previous ones
+func WriteStateSyncEvents(tx kv.Putter, blockNumber uint64, events []*EventRecordWithTime) error {
+	for _, event := range events {
+		blob, err := json.Marshal(&StateSyncEvent{EventRecordWithTime: *event, BlockNumber: blockNumber})
+		if err != nil {
+			return err
+		}
+		if err := tx.Put(kv.BorSeparate, stateSyncEventKey(event.ID), blob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnwindStateSyncEvents removes the events committed by the given block number or newer.
+// Event ids grow with the block number, so the events are removed from the last one backwards.
+func UnwindStateSyncEvents(tx kv.RwTx, blockNumber uint64) error {
+	c, err := tx.RwCursor(kv.BorSeparate)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// position at the first key after the events and step back to the last event
+	next, _ := dbutils.NextSubtree(stateSyncEventPrefix)
+	k, _, err := c.Seek(next)
+	if err != nil {
+		return err
+	}
+	var v []byte
+	if k == nil {
+		k, v, err = c.Last()
+	} else {
+		k, v, err = c.Prev()
+	}
+	for ; k != nil; k, v, err = c.Prev() {
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(k, stateSyncEventPrefix) {
+			break
+		}
+		event := new(StateSyncEvent)
+		if err := json.Unmarshal(v, event); err != nil {
+			return err
+		}
+		if event.BlockNumber < blockNumber {
+			break
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// ReadStateSyncEvent returns the committed event with the given id, nil if the event is unknown
+func ReadStateSyncEvent(tx kv.Getter, id uint64) (*StateSyncEvent, error) {
+	blob, err := tx.GetOne(kv.BorSeparate, stateSyncEventKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		return nil, nil
+	}
+	event := new(StateSyncEvent)
+	if err := json.Unmarshal(blob, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ReadStateSyncEvents returns the committed events with ids from the range [from, to]
+func ReadStateSyncEvents(tx kv.Tx, from, to uint64) ([]*StateSyncEvent, error) {
+	c, err := tx.Cursor(kv.BorSeparate)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	events := make([]*StateSyncEvent, 0)
+	for k, v, err := c.Seek(stateSyncEventKey(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasPrefix(k, stateSyncEventPrefix) || binary.BigEndian.Uint64(k[len(stateSyncEventPrefix):]) > to {
+			break
+		}
+		event := new(StateSyncEvent)
+		if err := json.Unmarshal(v, event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
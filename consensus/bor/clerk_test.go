@@ -0,0 +1,71 @@
+package bor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSyncEvents(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	var events []*EventRecordWithTime
+	for i := uint64(1); i <= 5; i++ {
+		events = append(events, &EventRecordWithTime{
+			EventRecord: EventRecord{ID: i, Contract: common.HexToAddress("0x1001"), Data: []byte{byte(i)}, ChainID: "137"},
+			Time:        time.Unix(1_600_000_000+int64(i), 0).UTC(),
+		})
+	}
+	require.Nil(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		require.Nil(t, tx.Put(kv.BorSeparate, []byte("bor-snapshot"), []byte{1})) // not an event, sorted before
+		require.Nil(t, tx.Put(kv.BorSeparate, []byte("fake"), []byte{1}))         // not an event, sorted after
+		require.Nil(t, WriteStateSyncEvents(tx, 16, events[:3]))
+		return WriteStateSyncEvents(tx, 32, events[3:])
+	}))
+
+	require.Nil(t, db.View(context.Background(), func(tx kv.Tx) error {
+		event, err := ReadStateSyncEvent(tx, 4)
+		require.Nil(t, err)
+		require.NotNil(t, event)
+		assert.Equal(t, uint64(32), event.BlockNumber)
+		assert.Equal(t, events[3].Data, event.Data)
+		assert.True(t, events[3].Time.Equal(event.Time))
+
+		event, err = ReadStateSyncEvent(tx, 6)
+		require.Nil(t, err)
+		assert.Nil(t, event)
+
+		found, err := ReadStateSyncEvents(tx, 2, 4)
+		require.Nil(t, err)
+		require.Equal(t, 3, len(found))
+		assert.Equal(t, uint64(2), found[0].ID)
+		assert.Equal(t, uint64(16), found[0].BlockNumber)
+		assert.Equal(t, uint64(4), found[2].ID)
+
+		found, err = ReadStateSyncEvents(tx, 5, 100)
+		require.Nil(t, err)
+		assert.Equal(t, 1, len(found))
+		return nil
+	}))
+
+	// unwind of the block 32 removes its events only
+	require.Nil(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		return UnwindStateSyncEvents(tx, 17)
+	}))
+	require.Nil(t, db.View(context.Background(), func(tx kv.Tx) error {
+		found, err := ReadStateSyncEvents(tx, 1, 100)
+		require.Nil(t, err)
+		require.Equal(t, 3, len(found))
+		assert.Equal(t, uint64(3), found[2].ID)
+
+		fake, err := tx.GetOne(kv.BorSeparate, []byte("fake"))
+		require.Nil(t, err)
+		assert.NotNil(t, fake)
+		return nil
+	}))
+}
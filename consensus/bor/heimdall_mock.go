@@ -0,0 +1,171 @@
+package bor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MockHeimdallConfig is the content of the file the mock Heimdall is loaded from
+type MockHeimdallConfig struct {
+	// Spans served by the mock, sorted by ID without gaps. Spans after the last one
+	// repeat its validator set and length, so that a dev chain can run indefinitely.
+	Spans []*HeimdallSpan `json:"spans"`
+	// Events are state-sync events replayed to the chain once their record time is reached
+	Events []*EventRecordWithTime `json:"events"`
+}
+
+// MockHeimdallClient is an in-process stand-in for Heimdall, serving spans and state-sync
+// events from a config, for dev chains and tests running without the Heimdall service.
+// It also implements http.Handler, so it can be used by a HeimdallClient over HTTP.
+type MockHeimdallClient struct {
+	lock   sync.RWMutex
+	spans  []*HeimdallSpan
+	events []*EventRecordWithTime // sorted by ID
+}
+
+func NewMockHeimdallClient(config *MockHeimdallConfig) (*MockHeimdallClient, error) {
+	if len(config.Spans) == 0 {
+		return nil, fmt.Errorf("mock heimdall: at least one span is required")
+	}
+	for i, span := range config.Spans {
+		if span.EndBlock < span.StartBlock {
+			return nil, &InvalidStartEndBlockError{Start: span.StartBlock, End: span.EndBlock}
+		}
+		if i > 0 && span.ID != config.Spans[i-1].ID+1 {
+			return nil, fmt.Errorf("mock heimdall: span %d follows span %d", span.ID, config.Spans[i-1].ID)
+		}
+	}
+	h := &MockHeimdallClient{spans: config.Spans}
+	for _, event := range config.Events {
+		h.AddStateSyncEvent(event)
+	}
+	return h, nil
+}
+
+// NewMockHeimdallClientFromFile loads the mock from a JSON file with MockHeimdallConfig
+func NewMockHeimdallClientFromFile(path string) (*MockHeimdallClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config MockHeimdallConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("mock heimdall config %s: %w", path, err)
+	}
+	return NewMockHeimdallClient(&config)
+}
+
+// AddStateSyncEvent schedules one more state-sync event, it is committed by the first sprint
+// which starts after the record time of the event
+func (h *MockHeimdallClient) AddStateSyncEvent(event *EventRecordWithTime) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.events = append(h.events, event)
+	sort.SliceStable(h.events, func(i, j int) bool {
+		return h.events[i].ID < h.events[j].ID
+	})
+}
+
+// Span returns the span with the given id
+func (h *MockHeimdallClient) Span(spanID uint64) (*HeimdallSpan, error) {
+	first, last := h.spans[0], h.spans[len(h.spans)-1]
+	if spanID < first.ID {
+		return nil, fmt.Errorf("mock heimdall: span %d is not configured", spanID)
+	}
+	if spanID <= last.ID {
+		return h.spans[spanID-first.ID], nil
+	}
+	span := *last
+	length := last.EndBlock - last.StartBlock + 1
+	span.ID = spanID
+	span.StartBlock = last.EndBlock + 1 + (spanID-last.ID-1)*length
+	span.EndBlock = span.StartBlock + length - 1
+	return &span, nil
+}
+
+// StateSyncEvents returns up to limit events starting from fromID with the record time before to
+func (h *MockHeimdallClient) StateSyncEvents(fromID uint64, to int64, limit int) []*EventRecordWithTime {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	events := make([]*EventRecordWithTime, 0)
+	for _, event := range h.events {
+		if limit > 0 && len(events) == limit {
+			break
+		}
+		if event.ID >= fromID && event.Time.Unix() < to {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (h *MockHeimdallClient) FetchStateSyncEvents(fromID uint64, to int64) ([]*EventRecordWithTime, error) {
+	return h.StateSyncEvents(fromID, to, 0), nil
+}
+
+// Fetch answers the Heimdall REST requests used by bor: bor/span/<id> and clerk/event-record/list
+func (h *MockHeimdallClient) Fetch(rawPath string, rawQuery string) (*ResponseWithHeight, error) {
+	var result interface{}
+	path := strings.TrimPrefix(rawPath, "/")
+	switch {
+	case strings.HasPrefix(path, "bor/span/"):
+		spanID, err := strconv.ParseUint(strings.TrimPrefix(path, "bor/span/"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mock heimdall: invalid span id: %w", err)
+		}
+		if result, err = h.Span(spanID); err != nil {
+			return nil, err
+		}
+	case path == "clerk/event-record/list":
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, err
+		}
+		fromID, err := strconv.ParseUint(query.Get("from-id"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mock heimdall: invalid from-id: %w", err)
+		}
+		to, err := strconv.ParseInt(query.Get("to-time"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("mock heimdall: invalid to-time: %w", err)
+		}
+		limit := stateFetchLimit
+		if query.Has("limit") {
+			if limit, err = strconv.Atoi(query.Get("limit")); err != nil {
+				return nil, fmt.Errorf("mock heimdall: invalid limit: %w", err)
+			}
+		}
+		result = h.StateSyncEvents(fromID, to, limit)
+	default:
+		return nil, fmt.Errorf("mock heimdall: unsupported request %s", rawPath)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseWithHeight{Height: "0", Result: data}, nil
+}
+
+// FetchWithRetry is the same as Fetch, retrying doesn't change the answer of the mock
+func (h *MockHeimdallClient) FetchWithRetry(rawPath string, rawQuery string) (*ResponseWithHeight, error) {
+	return h.Fetch(rawPath, rawQuery)
+}
+
+func (h *MockHeimdallClient) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	response, err := h.Fetch(r.URL.Path, r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
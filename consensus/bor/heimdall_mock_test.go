@@ -0,0 +1,109 @@
+package bor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockHeimdallConfig(events int) *MockHeimdallConfig {
+	validators := []*Validator{NewValidator(common.HexToAddress("0x1"), 10), NewValidator(common.HexToAddress("0x2"), 20)}
+	config := &MockHeimdallConfig{
+		Spans: []*HeimdallSpan{
+			{
+				Span:              Span{ID: 1, StartBlock: 256, EndBlock: 6655},
+				ValidatorSet:      *NewValidatorSet(validators),
+				SelectedProducers: []Validator{*validators[0], *validators[1]},
+				ChainID:           "1337",
+			},
+		},
+	}
+	start := time.Unix(1_600_000_000, 0).UTC()
+	for i := 1; i <= events; i++ {
+		config.Events = append(config.Events, &EventRecordWithTime{
+			EventRecord: EventRecord{ID: uint64(i), Contract: common.HexToAddress("0x1001"), Data: []byte{byte(i)}, ChainID: "1337"},
+			Time:        start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return config
+}
+
+func TestMockHeimdallSpans(t *testing.T) {
+	h, err := NewMockHeimdallClient(mockHeimdallConfig(0))
+	require.Nil(t, err)
+
+	span, err := h.Span(1)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(256), span.StartBlock)
+
+	// spans after the configured ones repeat the last one
+	span, err = h.Span(3)
+	require.Nil(t, err)
+	assert.Equal(t, Span{ID: 3, StartBlock: 13056, EndBlock: 19455}, span.Span)
+	assert.Equal(t, 2, len(span.ValidatorSet.Validators))
+
+	_, err = h.Span(0)
+	require.NotNil(t, err)
+
+	response, err := h.FetchWithRetry("bor/span/2", "")
+	require.Nil(t, err)
+	var heimdallSpan HeimdallSpan
+	require.Nil(t, json.Unmarshal(response.Result, &heimdallSpan))
+	assert.Equal(t, uint64(6656), heimdallSpan.StartBlock)
+	assert.Equal(t, "1337", heimdallSpan.ChainID)
+}
+
+func TestMockHeimdallStateSyncEvents(t *testing.T) {
+	config := mockHeimdallConfig(10)
+	h, err := NewMockHeimdallClient(config)
+	require.Nil(t, err)
+
+	// events are served once their record time is reached
+	events, err := h.FetchStateSyncEvents(3, config.Events[6].Time.Unix())
+	require.Nil(t, err)
+	require.Equal(t, 4, len(events))
+	assert.Equal(t, uint64(3), events[0].ID)
+	assert.Equal(t, uint64(6), events[3].ID)
+
+	h.AddStateSyncEvent(&EventRecordWithTime{EventRecord: EventRecord{ID: 11, ChainID: "1337"}, Time: config.Events[9].Time.Add(time.Minute)})
+	events, err = h.FetchStateSyncEvents(10, time.Now().Unix())
+	require.Nil(t, err)
+	require.Equal(t, 2, len(events))
+	assert.Equal(t, uint64(11), events[1].ID)
+}
+
+func TestMockHeimdallOverHTTP(t *testing.T) {
+	config := mockHeimdallConfig(2*stateFetchLimit + 5)
+	path := filepath.Join(t.TempDir(), "heimdall.json")
+	data, err := json.Marshal(config)
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(path, data, 0600))
+
+	h, err := NewMockHeimdallClientFromFile(path)
+	require.Nil(t, err)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client, err := NewHeimdallClient(server.URL)
+	require.Nil(t, err)
+	events, err := client.FetchStateSyncEvents(1, time.Now().Unix())
+	require.Nil(t, err)
+	require.Equal(t, len(config.Events), len(events))
+	assert.Equal(t, config.Events[0].Data, events[0].Data)
+	assert.True(t, config.Events[0].Time.Equal(events[0].Time))
+
+	response, err := client.Fetch(fmt.Sprintf("bor/span/%d", 1), "")
+	require.Nil(t, err)
+	var span HeimdallSpan
+	require.Nil(t, json.Unmarshal(response.Result, &span))
+	assert.Equal(t, config.Spans[0].Span, span.Span)
+	assert.Equal(t, config.Spans[0].ValidatorSet.Validators[1].Address, span.ValidatorSet.Validators[1].Address)
+}
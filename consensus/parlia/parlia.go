@@ -217,7 +217,7 @@ type Parlia struct {
 	chainConfig *params.ChainConfig  // Chain config
 	config      *params.ParliaConfig // Consensus engine configuration parameters for parlia consensus
 	genesisHash common.Hash
-	db          kv.RwDB // Database to store and retrieve snapshot checkpoints
+	DB          kv.RwDB // Database to store and retrieve snapshot checkpoints
 
 	recentSnaps *lru.ARCCache // Snapshots for recent block to speed up
 	signatures  *lru.ARCCache // Signatures of recent blocks to speed up mining
@@ -272,7 +272,7 @@ func New(
 	c := &Parlia{
 		chainConfig:     chainConfig,
 		config:          parliaConfig,
-		db:              db,
+		DB:              db,
 		recentSnaps:     recentSnaps,
 		signatures:      signatures,
 		validatorSetABI: vABI,
@@ -501,7 +501,7 @@ func (p *Parlia) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 
 		// If an on-disk checkpoint snapshot can be found, use that
 		if number%checkpointInterval == 0 {
-			if s, err := loadSnapshot(p.config, p.signatures, p.db, number, hash); err == nil {
+			if s, err := loadSnapshot(p.config, p.signatures, p.DB, number, hash); err == nil {
 				//log.Trace("Loaded snapshot from disk", "number", number, "hash", hash)
 				snap = s
 				if !verify || snap != nil {
@@ -522,6 +522,10 @@ func (p *Parlia) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 					}
 					// new snapshot
 					snap = newSnapshot(p.config, p.signatures, number, hash, validators)
+					if err := snap.store(p.DB); err != nil {
+						return nil, err
+					}
+					log.Info("[parlia] Stored checkpoint snapshot to disk", "number", number, "hash", hash)
 					break
 				}
 			}
@@ -564,7 +568,7 @@ func (p *Parlia) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 
 	// If we've generated a new checkpoint snapshot, save to disk
 	if snap.Number%checkpointInterval == 0 && len(headers) > 0 {
-		if err = snap.store(p.db); err != nil {
+		if err = snap.store(p.DB); err != nil {
 			return nil, err
 		}
 		//log.Trace("Stored snapshot to disk", "number", snap.Number, "hash", snap.Hash)
@@ -975,6 +979,7 @@ func (p *Parlia) AllowLightProcess(chain consensus.ChainReader, currentHeader *t
 
 // Close terminates any background threads maintained by the consensus engine.
 func (p *Parlia) Close() error {
+	p.DB.Close()
 	return nil
 }
 
@@ -0,0 +1,55 @@
+package parlia
+
+import (
+	"bytes"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/systemcontracts"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+var (
+	depositMethodID = crypto.Keccak256([]byte("deposit(address)"))[:4]
+	slashMethodID   = crypto.Keccak256([]byte("slash(address)"))[:4]
+)
+
+// Rewards is the distribution of the fees incoming to a block and the slashing done by the block,
+// see distributeIncoming and slash.
+type Rewards struct {
+	Validator       common.Address  // Validator which sealed the block
+	ValidatorReward *uint256.Int    // Deposited to the validator contract for the validator
+	SystemReward    *uint256.Int    // Sent to the system reward contract
+	Slashed         *common.Address // Validator slashed for missing its turn, nil if none
+}
+
+// BlockRewards derives the rewards of a block from its system transactions, senders are the senders of txs.
+func BlockRewards(header *types.Header, txs types.Transactions, senders []common.Address) *Rewards {
+	rewards := &Rewards{
+		Validator:       header.Coinbase,
+		ValidatorReward: new(uint256.Int),
+		SystemReward:    new(uint256.Int),
+	}
+	for i, tx := range txs {
+		to := tx.GetTo()
+		if to == nil || i >= len(senders) || senders[i] != header.Coinbase || !isToSystemContract(*to) || !tx.GetPrice().IsZero() {
+			continue
+		}
+		data := tx.GetData()
+		switch *to {
+		case systemcontracts.SystemRewardContract:
+			rewards.SystemReward.Add(rewards.SystemReward, tx.GetValue())
+		case systemcontracts.ValidatorContract:
+			if len(data) >= 4+32 && bytes.Equal(data[:4], depositMethodID) {
+				rewards.ValidatorReward.Add(rewards.ValidatorReward, tx.GetValue())
+			}
+		case systemcontracts.SlashContract:
+			if len(data) >= 4+32 && bytes.Equal(data[:4], slashMethodID) {
+				slashed := common.BytesToAddress(data[4 : 4+32])
+				rewards.Slashed = &slashed
+			}
+		}
+	}
+	return rewards
+}
@@ -0,0 +1,43 @@
+package parlia
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/systemcontracts"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRewards(t *testing.T) {
+	coinbase, user, slashed := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	header := &types.Header{Number: big.NewInt(10), Coinbase: coinbase}
+
+	deposit := append(append([]byte{}, depositMethodID...), common.LeftPadBytes(coinbase.Bytes(), 32)...)
+	slash := append(append([]byte{}, slashMethodID...), common.LeftPadBytes(slashed.Bytes(), 32)...)
+	txs := types.Transactions{
+		// regular transactions are not rewards, even to the system contracts
+		types.NewTransaction(0, systemcontracts.SystemRewardContract, uint256.NewInt(5), 21000, uint256.NewInt(1), nil),
+		types.NewTransaction(0, systemcontracts.ValidatorContract, uint256.NewInt(7), 21000, uint256.NewInt(0), deposit),
+		// system transactions of the validator
+		types.NewTransaction(1, systemcontracts.SlashContract, uint256.NewInt(0), 21000, uint256.NewInt(0), slash),
+		types.NewTransaction(2, systemcontracts.SystemRewardContract, uint256.NewInt(100), 21000, uint256.NewInt(0), nil),
+		types.NewTransaction(3, systemcontracts.ValidatorContract, uint256.NewInt(900), 21000, uint256.NewInt(0), deposit),
+	}
+	senders := []common.Address{user, user, coinbase, coinbase, coinbase}
+
+	rewards := BlockRewards(header, txs, senders)
+	assert.Equal(t, coinbase, rewards.Validator)
+	assert.Equal(t, uint64(900), rewards.ValidatorReward.Uint64())
+	assert.Equal(t, uint64(100), rewards.SystemReward.Uint64())
+	require.NotNil(t, rewards.Slashed)
+	assert.Equal(t, slashed, *rewards.Slashed)
+
+	rewards = BlockRewards(header, txs[:2], senders[:2])
+	assert.True(t, rewards.ValidatorReward.IsZero())
+	assert.True(t, rewards.SystemReward.IsZero())
+	assert.Nil(t, rewards.Slashed)
+}
@@ -94,6 +94,9 @@ func SnapshotFullKey(number uint64, hash common.Hash) []byte {
 	return append(EncodeBlockNumber(number), hash.Bytes()...)
 }
 
+// errMissingSnapshot is returned if there is no snapshot for the block in the database.
+var errMissingSnapshot = errors.New("snapshot not found")
+
 // loadSnapshot loads an existing snapshot from the database.
 func loadSnapshot(config *params.ParliaConfig, sigCache *lru.ARCCache, db kv.RwDB, num uint64, hash common.Hash) (*Snapshot, error) {
 	tx, err := db.BeginRo(context.Background())
@@ -101,10 +104,25 @@ func loadSnapshot(config *params.ParliaConfig, sigCache *lru.ARCCache, db kv.RwD
 		return nil, err
 	}
 	defer tx.Rollback()
+	snap, err := readSnapshot(config, sigCache, tx, num, hash)
+	if err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		return nil, errMissingSnapshot
+	}
+	return snap, nil
+}
+
+// readSnapshot reads an existing snapshot from the database, nil if there is none.
+func readSnapshot(config *params.ParliaConfig, sigCache *lru.ARCCache, tx kv.Getter, num uint64, hash common.Hash) (*Snapshot, error) {
 	blob, err := tx.GetOne(kv.ParliaSnapshot, SnapshotFullKey(num, hash))
 	if err != nil {
 		return nil, err
 	}
+	if len(blob) == 0 {
+		return nil, nil
+	}
 	snap := new(Snapshot)
 	if err := json.Unmarshal(blob, snap); err != nil {
 		return nil, err
@@ -114,6 +132,57 @@ func loadSnapshot(config *params.ParliaConfig, sigCache *lru.ARCCache, db kv.RwD
 	return snap, nil
 }
 
+// ReadSnapshot retrieves the snapshot at a given block without an engine: the nearest snapshot stored
+// in the database is brought up to the block with headers of the chain. Nothing is written, so it works
+// with a read-only database (e.g. in rpcdaemon). To not walk back to the genesis when no snapshot is stored,
+// the validators of an epoch block further than checkpointInterval are trusted, like the engine trusts
+// the headers included into the block snapshots.
+func ReadSnapshot(chainConfig *params.ChainConfig, tx kv.Getter, chain consensus.ChainHeaderReader, number uint64, hash common.Hash) (*Snapshot, error) {
+	config := *chainConfig.Parlia
+	if config.Epoch == 0 {
+		config.Epoch = defaultEpochLength
+	}
+	sigCache, err := lru.NewARC(inMemorySignatures)
+	if err != nil {
+		return nil, err
+	}
+
+	var headers []*types.Header
+	var snap *Snapshot
+	for snap == nil {
+		if number%checkpointInterval == 0 || number%config.Epoch == 0 {
+			if snap, err = readSnapshot(&config, sigCache, tx, number, hash); err != nil {
+				return nil, err
+			}
+			if snap != nil {
+				break
+			}
+		}
+		header := chain.GetHeader(hash, number)
+		if header == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		if number == 0 || (number%config.Epoch == 0 && len(headers) > checkpointInterval) {
+			if len(header.Extra) < extraVanity+extraSeal {
+				return nil, errMissingSignature
+			}
+			validators, err := ParseValidators(header.Extra[extraVanity : len(header.Extra)-extraSeal])
+			if err != nil {
+				return nil, err
+			}
+			snap = newSnapshot(&config, sigCache, number, hash, validators)
+			break
+		}
+		headers = append(headers, header)
+		number, hash = number-1, header.ParentHash
+	}
+
+	for i := 0; i < len(headers)/2; i++ {
+		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
+	}
+	return snap.apply(headers, chain, nil, chainConfig.ChainID)
+}
+
 // store inserts the snapshot into the database.
 func (s *Snapshot) store(db kv.RwDB) error {
 	blob, err := json.Marshal(s)
@@ -256,6 +325,11 @@ func (s *Snapshot) validators() []common.Address {
 	return validators
 }
 
+// SortedValidators retrieves the list of validators in ascending order, the order of their turns.
+func (s *Snapshot) SortedValidators() []common.Address {
+	return s.validators()
+}
+
 // inturn returns if a validator at a given block height is in-turn or not.
 func (s *Snapshot) inturn(validator common.Address) bool {
 	validators := s.validators()
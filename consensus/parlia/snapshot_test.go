@@ -2,13 +2,20 @@ package parlia
 
 import (
 	"bytes"
+	"encoding/json"
+	"math/big"
 	"math/rand"
 	"sort"
 	"testing"
 
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
 )
 
 func TestValidatorSetSort(t *testing.T) {
@@ -23,6 +30,56 @@ func TestValidatorSetSort(t *testing.T) {
 	}
 }
 
+// testHeaderReader is consensus.ChainHeaderReader over a set of headers
+type testHeaderReader struct {
+	config  *params.ChainConfig
+	headers map[common.Hash]*types.Header
+}
+
+func (r *testHeaderReader) Config() *params.ChainConfig                   { return r.config }
+func (r *testHeaderReader) CurrentHeader() *types.Header                  { return nil }
+func (r *testHeaderReader) GetHeaderByNumber(number uint64) *types.Header { return nil }
+func (r *testHeaderReader) GetHeaderByHash(hash common.Hash) *types.Header {
+	return r.headers[hash]
+}
+func (r *testHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return r.headers[hash]
+}
+func (r *testHeaderReader) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+func TestReadSnapshot(t *testing.T) {
+	config := &params.ChainConfig{ChainID: big.NewInt(56), Parlia: &params.ParliaConfig{Period: 3, Epoch: 200}}
+	validators := []common.Address{common.HexToAddress("0x2"), common.HexToAddress("0x1")}
+	extra := make([]byte, extraVanity)
+	for _, v := range validators {
+		extra = append(extra, v.Bytes()...)
+	}
+	extra = append(extra, make([]byte, extraSeal)...)
+	genesis := &types.Header{Number: big.NewInt(0), Extra: extra}
+	chain := &testHeaderReader{config: config, headers: map[common.Hash]*types.Header{genesis.Hash(): genesis}}
+	_, tx := memdb.NewTestTx(t)
+
+	// validators of the genesis are taken from its header
+	snap, err := ReadSnapshot(config, tx, chain, 0, genesis.Hash())
+	require.Nil(t, err)
+	assert.Equal(t, genesis.Hash(), snap.Hash)
+	assert.Equal(t, []common.Address{validators[1], validators[0]}, snap.SortedValidators())
+
+	// a snapshot persisted at an epoch doesn't need the headers
+	stored := newSnapshot(config.Parlia, nil, 400, common.HexToHash("0x400"), validators[:1])
+	blob, err := json.Marshal(stored)
+	require.Nil(t, err)
+	require.Nil(t, tx.Put(kv.ParliaSnapshot, SnapshotFullKey(stored.Number, stored.Hash), blob))
+	snap, err = ReadSnapshot(config, tx, chain, 400, stored.Hash)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(400), snap.Number)
+	assert.Equal(t, validators[:1], snap.SortedValidators())
+
+	// neither persisted nor known
+	_, err = ReadSnapshot(config, tx, chain, 401, common.HexToHash("0x401"))
+	require.NotNil(t, err)
+}
+
 func randomAddress() common.Address {
 	addrBytes := make([]byte, 20)
 	rand.Read(addrBytes)
@@ -186,6 +186,14 @@ func (s *Serenity) verifyHeader(chain consensus.ChainHeaderReader, header, paren
 		return errInvalidUncleHash
 	}
 
+	shanghai := chain.Config().IsShanghai(header.Time)
+	if shanghai && header.WithdrawalsHash == nil {
+		return fmt.Errorf("missing withdrawalsHash")
+	}
+	if !shanghai && header.WithdrawalsHash != nil {
+		return fmt.Errorf("withdrawalsHash before Shanghai")
+	}
+
 	return misc.VerifyEip1559Header(chain.Config(), parent, header)
 }
 
@@ -1,6 +1,9 @@
 package core
 
-import "github.com/ledgerwatch/erigon/common"
+import (
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+)
 
 // See https://github.com/ethereum/execution-apis/blob/main/src/engine/specification.md#payloadattributesv1
 type BlockProposerParametersPOS struct {
@@ -8,4 +11,5 @@ type BlockProposerParametersPOS struct {
 	Timestamp             uint64
 	PrevRandao            common.Hash
 	SuggestedFeeRecipient common.Address
+	Withdrawals           []*types.Withdrawal // nil before Shanghai
 }
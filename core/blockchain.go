@@ -27,6 +27,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/systemcontracts"
 
 	metrics2 "github.com/VictoriaMetrics/metrics"
+	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon/common"
@@ -301,7 +302,7 @@ func ExecuteBlockEphemerally(
 	}
 	if !vmConfig.ReadOnly {
 		txs := block.Transactions()
-		if _, err := FinalizeBlockExecution(engine, stateReader, block.Header(), txs, block.Uncles(), stateWriter, chainConfig, ibs, receipts, epochReader, chainReader, false); err != nil {
+		if _, err := FinalizeBlockExecution(engine, stateReader, block.Header(), txs, block.Uncles(), block.Withdrawals(), stateWriter, chainConfig, ibs, receipts, epochReader, chainReader, false); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -418,9 +419,22 @@ func CallContractTx(contract common.Address, data []byte, ibs *state.IntraBlockS
 }
 
 func FinalizeBlockExecution(engine consensus.Engine, stateReader state.StateReader, header *types.Header,
-	txs types.Transactions, uncles []*types.Header, stateWriter state.WriterWithChangeSets, cc *params.ChainConfig, ibs *state.IntraBlockState,
+	txs types.Transactions, uncles []*types.Header, withdrawals []*types.Withdrawal, stateWriter state.WriterWithChangeSets, cc *params.ChainConfig, ibs *state.IntraBlockState,
 	receipts types.Receipts, e consensus.EpochReader, headerReader consensus.ChainHeaderReader, isMining bool,
 ) (newBlock *types.Block, err error) {
+	// EIP-4895: withdrawals are processed after transactions, amounts are in Gwei
+	if cc.IsShanghai(header.Time) {
+		if header.WithdrawalsHash == nil {
+			return nil, fmt.Errorf("no withdrawals hash in block %d after Shanghai", header.Number.Uint64())
+		}
+		for _, w := range withdrawals {
+			amount := new(uint256.Int).Mul(uint256.NewInt(w.Amount), uint256.NewInt(params.GWei))
+			ibs.AddBalance(w.Address, amount)
+		}
+	} else if withdrawals != nil || header.WithdrawalsHash != nil {
+		return nil, fmt.Errorf("withdrawals in block %d before Shanghai", header.Number.Uint64())
+	}
+
 	syscall := func(contract common.Address, data []byte) ([]byte, error) {
 		return SysCallContract(contract, data, *cc, ibs, header, engine)
 	}
@@ -0,0 +1,50 @@
+package core_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalizeBlockExecutionWithdrawals(t *testing.T) {
+	config := *params.TestChainConfig
+	config.ShanghaiTime = big.NewInt(100)
+	recipient := common.HexToAddress("0x1234")
+	withdrawals := []*types.Withdrawal{{Index: 0, Validator: 1, Address: recipient, Amount: 3}}
+	withdrawalsHash := types.DeriveSha(types.Withdrawals(withdrawals))
+
+	finalize := func(header *types.Header, withdrawals []*types.Withdrawal) (*state.IntraBlockState, error) {
+		_, tx := memdb.NewTestTx(t)
+		ibs := state.New(state.NewPlainStateReader(tx))
+		_, err := core.FinalizeBlockExecution(ethash.NewFaker(), state.NewPlainStateReader(tx), header, nil, nil, withdrawals,
+			state.NewPlainStateWriter(tx, tx, header.Number.Uint64()), &config, ibs, nil, nil, nil, false)
+		return ibs, err
+	}
+
+	// withdrawals are credited in Gwei after Shanghai
+	ibs, err := finalize(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: 100, WithdrawalsHash: &withdrawalsHash}, withdrawals)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3*params.GWei), ibs.GetBalance(recipient).Uint64())
+
+	// after Shanghai the header must commit to the withdrawals, even to none
+	_, err = finalize(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: 100}, withdrawals)
+	require.Error(t, err)
+	_, err = finalize(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: 100}, nil)
+	require.Error(t, err)
+
+	// before Shanghai neither withdrawals nor their root are allowed
+	_, err = finalize(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: 99}, withdrawals)
+	require.Error(t, err)
+	_, err = finalize(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: 99, WithdrawalsHash: &withdrawalsHash}, nil)
+	require.Error(t, err)
+	_, err = finalize(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Time: 99}, nil)
+	require.NoError(t, err)
+}
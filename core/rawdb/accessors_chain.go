@@ -125,6 +125,51 @@ func WriteHeadHeaderHash(db kv.Putter, hash common.Hash) error {
 	return nil
 }
 
+const (
+	forkchoiceSafeKey      = "safeBlockHash"
+	forkchoiceFinalizedKey = "finalizedBlockHash"
+)
+
+// ReadForkchoiceSafe retrieves the safe block hash of the latest Engine API fork choice, zero hash before the merge.
+func ReadForkchoiceSafe(db kv.Getter) common.Hash {
+	data, err := db.GetOne(kv.LastForkchoice, []byte(forkchoiceSafeKey))
+	if err != nil {
+		log.Error("ReadForkchoiceSafe failed", "err", err)
+	}
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteForkchoiceSafe stores the safe block hash of the latest Engine API fork choice.
+func WriteForkchoiceSafe(db kv.Putter, hash common.Hash) error {
+	if err := db.Put(kv.LastForkchoice, []byte(forkchoiceSafeKey), hash.Bytes()); err != nil {
+		return fmt.Errorf("failed to store safe block hash: %w", err)
+	}
+	return nil
+}
+
+// ReadForkchoiceFinalized retrieves the finalized block hash of the latest Engine API fork choice, zero hash before the merge.
+func ReadForkchoiceFinalized(db kv.Getter) common.Hash {
+	data, err := db.GetOne(kv.LastForkchoice, []byte(forkchoiceFinalizedKey))
+	if err != nil {
+		log.Error("ReadForkchoiceFinalized failed", "err", err)
+	}
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteForkchoiceFinalized stores the finalized block hash of the latest Engine API fork choice.
+func WriteForkchoiceFinalized(db kv.Putter, hash common.Hash) error {
+	if err := db.Put(kv.LastForkchoice, []byte(forkchoiceFinalizedKey), hash.Bytes()); err != nil {
+		return fmt.Errorf("failed to store finalized block hash: %w", err)
+	}
+	return nil
+}
+
 // ReadHeadBlockHash retrieves the hash of the current canonical head block.
 func ReadHeadBlockHash(db kv.Getter) common.Hash {
 	data, err := db.GetOne(kv.HeadBlockKey, []byte(kv.HeadBlockKey))
@@ -537,6 +582,7 @@ func ReadBody(db kv.Getter, hash common.Hash, number uint64) (*types.Body, uint6
 	}
 	body := new(types.Body)
 	body.Uncles = bodyForStorage.Uncles
+	body.Withdrawals = bodyForStorage.Withdrawals
 
 	if bodyForStorage.TxAmount < 2 {
 		panic(fmt.Sprintf("block body hash too few txs amount: %d, %d", number, bodyForStorage.TxAmount))
@@ -573,9 +619,10 @@ func WriteRawBody(db kv.RwTx, hash common.Hash, number uint64, body *types.RawBo
 		return err
 	}
 	data := types.BodyForStorage{
-		BaseTxId: baseTxId,
-		TxAmount: uint32(len(body.Transactions)) + 2,
-		Uncles:   body.Uncles,
+		BaseTxId:    baseTxId,
+		TxAmount:    uint32(len(body.Transactions)) + 2,
+		Uncles:      body.Uncles,
+		Withdrawals: body.Withdrawals,
 	}
 	if err = WriteBodyForStorage(db, hash, number, &data); err != nil {
 		return fmt.Errorf("WriteBodyForStorage: %w", err)
@@ -594,9 +641,10 @@ func WriteBody(db kv.RwTx, hash common.Hash, number uint64, body *types.Body) er
 		return err
 	}
 	data := types.BodyForStorage{
-		BaseTxId: baseTxId,
-		TxAmount: uint32(len(body.Transactions)) + 2,
-		Uncles:   body.Uncles,
+		BaseTxId:    baseTxId,
+		TxAmount:    uint32(len(body.Transactions)) + 2,
+		Uncles:      body.Uncles,
+		Withdrawals: body.Withdrawals,
 	}
 	if err := WriteBodyForStorage(db, hash, number, &data); err != nil {
 		return fmt.Errorf("failed to write body: %w", err)
@@ -901,6 +949,41 @@ func ReadReceiptsByHash(db kv.Tx, hash common.Hash) (types.Receipts, error) {
 	return receipts, nil
 }
 
+// ReadReceiptsByHashWithReader - like ReadReceiptsByHash, but reads the block and its raw receipts with blockReader,
+// so receipts of blocks retired to snapshots are found after they are pruned from db
+func ReadReceiptsByHashWithReader(ctx context.Context, db kv.Tx, blockReader interfaces.BlockAndTxnReader, hash common.Hash) (types.Receipts, error) {
+	number := ReadHeaderNumber(db, hash)
+	if number == nil {
+		return nil, nil
+	}
+	canonicalHash, err := ReadCanonicalHash(db, *number)
+	if err != nil {
+		return nil, err
+	}
+	if canonicalHash != hash {
+		return nil, nil
+	}
+	b, s, err := blockReader.BlockWithSenders(ctx, db, hash, *number)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, nil
+	}
+	receipts, err := blockReader.RawReceipts(ctx, db, *number)
+	if err != nil {
+		return nil, err
+	}
+	if receipts == nil {
+		return nil, nil
+	}
+	b.SendersToTxs(s)
+	if err := receipts.DeriveFields(b.Hash(), b.NumberU64(), b.Transactions(), s); err != nil {
+		return nil, fmt.Errorf("failed to derive block receipts fields: %w, hash=%x, number=%d", err, hash, *number)
+	}
+	return receipts, nil
+}
+
 // WriteReceipts stores all the transaction receipts belonging to a block.
 func WriteReceipts(tx kv.Putter, number uint64, receipts types.Receipts) error {
 	buf := bytes.NewBuffer(make([]byte, 0, 1024))
@@ -1013,7 +1096,7 @@ func ReadBlock(tx kv.Getter, hash common.Hash, number uint64) *types.Block {
 	if body == nil {
 		return nil
 	}
-	return types.NewBlockFromStorage(hash, header, body.Transactions, body.Uncles)
+	return types.NewBlockFromStorage(hash, header, body.Transactions, body.Uncles, body.Withdrawals)
 }
 
 func NonCanonicalBlockWithSenders(tx kv.Getter, hash common.Hash, number uint64) (*types.Block, []common.Address, error) {
@@ -1025,7 +1108,7 @@ func NonCanonicalBlockWithSenders(tx kv.Getter, hash common.Hash, number uint64)
 	if body == nil {
 		return nil, nil, fmt.Errorf("body not found for block %d, %x", number, hash)
 	}
-	block := types.NewBlockFromStorage(hash, header, body.Transactions, body.Uncles)
+	block := types.NewBlockFromStorage(hash, header, body.Transactions, body.Uncles, body.Withdrawals)
 	senders, err := ReadSenders(tx, hash, number)
 	if err != nil {
 		return nil, nil, err
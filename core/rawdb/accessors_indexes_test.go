@@ -96,3 +96,55 @@ func TestLookupStorage(t *testing.T) {
 		})
 	}
 }
+
+// Tests that the bor (state-sync) transaction of a block can be looked up by its derived hash.
+func TestBorTxLookup(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	block := types.NewBlock(&types.Header{Number: big.NewInt(314)}, nil, nil, nil)
+	borTxHash := types.ComputeBorTxHash(block.NumberU64(), block.Hash())
+	if txn, _, _, _, err := ReadBorTransaction(tx, borTxHash); err != nil || txn != nil {
+		t.Fatalf("non existent bor transaction returned: %v, %v", txn, err)
+	}
+
+	if err := WriteCanonicalHash(tx, block.Hash(), block.NumberU64()); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBlock(tx, block); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBorReceipt(tx, block.Hash(), block.NumberU64(), &types.ReceiptForStorage{Status: types.ReceiptStatusSuccessful}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBorTxLookupEntry(tx, block.Hash(), block.NumberU64()); err != nil {
+		t.Fatal(err)
+	}
+	if txn, hash, number, _, err := ReadBorTransaction(tx, borTxHash); err != nil || txn == nil {
+		t.Fatalf("bor transaction not found: %v", err)
+	} else if hash != block.Hash() || number != block.NumberU64() {
+		t.Fatalf("positional metadata mismatch: have %x/%d, want %x/%d", hash, number, block.Hash(), block.NumberU64())
+	}
+	if receipt := ReadBorReceipt(tx, block.Hash(), block.NumberU64()); receipt == nil || receipt.TxHash != borTxHash {
+		t.Fatalf("bor receipt mismatch: %v", receipt)
+	}
+
+	// A reorg changes the canonical block before unwind, the stale lookup is not resolved to the new block
+	reorged := types.NewBlock(&types.Header{Number: big.NewInt(314), Extra: []byte("reorg")}, nil, nil, nil)
+	if err := WriteCanonicalHash(tx, reorged.Hash(), reorged.NumberU64()); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBlock(tx, reorged); err != nil {
+		t.Fatal(err)
+	}
+	if txn, _, _, _, err := ReadBorTransaction(tx, borTxHash); err != nil || txn != nil {
+		t.Fatalf("bor transaction of non-canonical block returned: %v, %v", txn, err)
+	}
+
+	// Unwind removes the lookup of the executed block together with the receipt
+	if err := TruncateBorReceipts(tx, block.NumberU64()); err != nil {
+		t.Fatal(err)
+	}
+	if number, err := ReadBorTxLookupEntry(tx, borTxHash); err != nil || number != nil {
+		t.Fatalf("lookup of unwound bor transaction remains: %v, %v", number, err)
+	}
+}
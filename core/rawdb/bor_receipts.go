@@ -2,6 +2,7 @@ package rawdb
 
 import (
 	"errors"
+	"math/big"
 
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common"
@@ -111,8 +112,10 @@ func DeleteBorReceipt(tx kv.RwTx, hash common.Hash, number uint64) {
 // ReadBorTransactionWithBlockHash retrieves a specific bor (fake) transaction by tx hash and block hash, along with
 // its added positional metadata.
 func ReadBorTransactionWithBlockHash(db kv.Tx, txHash common.Hash, blockHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
-	blockNumber := ReadHeaderNumber(db, txHash)
-
+	blockNumber, err := ReadBorTxLookupEntry(db, txHash)
+	if err != nil {
+		return nil, common.Hash{}, 0, 0, err
+	}
 	if blockNumber == nil {
 		return nil, common.Hash{}, 0, 0, nil
 	}
@@ -129,18 +132,24 @@ func ReadBorTransactionWithBlockHash(db kv.Tx, txHash common.Hash, blockHash com
 // ReadBorTransaction retrieves a specific bor (fake) transaction by hash, along with
 // its added positional metadata.
 func ReadBorTransaction(db kv.Tx, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
-	blockNumber := ReadHeaderNumber(db, hash)
-
+	blockNumber, err := ReadBorTxLookupEntry(db, hash)
+	if err != nil {
+		return nil, common.Hash{}, 0, 0, err
+	}
 	if blockNumber == nil {
-		return nil, common.Hash{}, 0, 0, errors.New("missing block number")
+		return nil, common.Hash{}, 0, 0, nil
 	}
 
 	blockHash, _ := ReadCanonicalHash(db, *blockNumber)
 	if blockHash == (common.Hash{}) {
 		return nil, common.Hash{}, 0, 0, errors.New("missing block hash")
 	}
+	if types.ComputeBorTxHash(*blockNumber, blockHash) != hash {
+		// the transaction is of a non-canonical block, which is not unwound yet
+		return nil, common.Hash{}, 0, 0, nil
+	}
 
-	bodyForStorage, err := ReadStorageBody(db, hash, *blockNumber)
+	bodyForStorage, err := ReadStorageBody(db, blockHash, *blockNumber)
 	if err != nil {
 		return nil, common.Hash{}, 0, 0, nil
 	}
@@ -149,9 +158,47 @@ func ReadBorTransaction(db kv.Tx, hash common.Hash) (*types.Transaction, common.
 	return &tx, blockHash, *blockNumber, uint64(bodyForStorage.TxAmount), nil
 }
 
-// TruncateBorReceipts removes all bor receipt for given block number or newer
+// ReadBorTxLookupEntry retrieves the block number of a bor (state-sync) transaction
+func ReadBorTxLookupEntry(db kv.Getter, borTxHash common.Hash) (*uint64, error) {
+	data, err := db.GetOne(kv.BorTxLookup, borTxHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	number := new(big.Int).SetBytes(data).Uint64()
+	return &number, nil
+}
+
+// WriteBorTxLookupEntry stores the block number of the bor (state-sync) transaction of a block,
+// enabling hash based lookups of the transaction and its receipt.
+// The transaction hash is also stored by the block number (8 bytes key, no collision with hashes),
+// so that unwind removes the lookup of the executed block even if the canonical hash was already
+// changed by a reorg.
+func WriteBorTxLookupEntry(db kv.Putter, hash common.Hash, number uint64) error {
+	borTxHash := types.ComputeBorTxHash(number, hash)
+	if err := db.Put(kv.BorTxLookup, borTxHash.Bytes(), new(big.Int).SetUint64(number).Bytes()); err != nil {
+		return err
+	}
+	return db.Put(kv.BorTxLookup, dbutils.EncodeBlockNumber(number), borTxHash.Bytes())
+}
+
+// TruncateBorReceipts removes all bor receipt for given block number or newer, together with their lookups
 func TruncateBorReceipts(db kv.RwTx, number uint64) error {
 	if err := db.ForEach(kv.BorReceipts, dbutils.EncodeBlockNumber(number), func(k, _ []byte) error {
+		borTxHash, err := db.GetOne(kv.BorTxLookup, k)
+		if err != nil {
+			return err
+		}
+		if len(borTxHash) > 0 {
+			if err := db.Delete(kv.BorTxLookup, borTxHash, nil); err != nil {
+				return err
+			}
+			if err := db.Delete(kv.BorTxLookup, k, nil); err != nil {
+				return err
+			}
+		}
 		return db.Delete(kv.BorReceipts, k, nil)
 	}); err != nil {
 		return err
@@ -20,6 +20,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
+	"sort"
 
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common"
@@ -27,9 +30,15 @@ import (
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/trie"
 )
 
+// dumpBatchSize is the number of accounts held in memory before they are passed to the collector
+const dumpBatchSize = 10_000
+
 type Dumper struct {
 	blockNumber uint64
 	db          kv.Tx
@@ -59,6 +68,29 @@ type iterativeDump struct {
 	*json.Encoder
 }
 
+// RLPDumpAccount is the RLP encoding of an account in the output of RLPDump.
+type RLPDumpAccount struct {
+	Address  common.Address
+	Nonce    uint64
+	Balance  *big.Int
+	CodeHash common.Hash
+	Code     []byte
+	Storage  []RLPDumpStorage // sorted by key
+}
+
+// RLPDumpStorage is a single storage slot of an RLPDumpAccount.
+type RLPDumpStorage struct {
+	Key   common.Hash
+	Value []byte
+}
+
+// rlpDump is a 'collector'-implementation which writes accounts as a stream of RLP lists.
+// The first error is kept and the remaining accounts are skipped.
+type rlpDump struct {
+	w   io.Writer
+	err error
+}
+
 // IteratorDump is an implementation for iterating over data.
 type IteratorDump struct {
 	Root     string                         `json:"root"`
@@ -121,6 +153,38 @@ func (d iterativeDump) OnRoot(root common.Hash) {
 	}{root})
 }
 
+// OnRoot implements DumpCollector interface
+func (d *rlpDump) OnRoot(common.Hash) {}
+
+// OnAccount implements DumpCollector interface
+func (d *rlpDump) OnAccount(addr common.Address, account DumpAccount) {
+	if d.err != nil {
+		return
+	}
+	balance, ok := new(big.Int).SetString(account.Balance, 10)
+	if !ok {
+		d.err = fmt.Errorf("invalid balance %q of %x", account.Balance, addr)
+		return
+	}
+	rlpAccount := RLPDumpAccount{
+		Address:  addr,
+		Nonce:    account.Nonce,
+		Balance:  balance,
+		CodeHash: common.BytesToHash(account.CodeHash),
+		Code:     account.Code,
+		Storage:  make([]RLPDumpStorage, 0, len(account.Storage)),
+	}
+	for k, v := range account.Storage {
+		rlpAccount.Storage = append(rlpAccount.Storage, RLPDumpStorage{Key: common.HexToHash(k), Value: common.FromHex(v)})
+	}
+	sort.Slice(rlpAccount.Storage, func(i, j int) bool {
+		return bytes.Compare(rlpAccount.Storage[i].Key[:], rlpAccount.Storage[j].Key[:]) < 0
+	})
+	if err := rlp.Encode(d.w, &rlpAccount); err != nil {
+		d.err = fmt.Errorf("writing %x: %w", addr, err)
+	}
+}
+
 func NewDumper(db kv.Tx, blockNumber uint64) *Dumper {
 	return &Dumper{
 		db:          db,
@@ -129,7 +193,54 @@ func NewDumper(db kv.Tx, blockNumber uint64) *Dumper {
 	}
 }
 
+// CheckDumpBlock returns an error when the state after the block cannot be dumped,
+// because the block is not executed yet or the changesets needed to rewind to it are pruned
+func CheckDumpBlock(tx kv.Tx, blockNumber uint64) error {
+	executed, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return err
+	}
+	if blockNumber > executed {
+		return fmt.Errorf("block %d is not executed yet, execution progress is %d", blockNumber, executed)
+	}
+	if blockNumber == executed {
+		return nil
+	}
+	pm, err := prune.Get(tx)
+	if err != nil {
+		return err
+	}
+	if pruneTo := pm.History.PruneTo(executed); blockNumber+1 < pruneTo {
+		return fmt.Errorf("history of block %d is pruned, the oldest available state is after block %d", blockNumber, pruneTo-1)
+	}
+	return nil
+}
+
+// DumpToCollector passes the accounts of the state after the block to the collector, starting with startAddress.
+// Returns the key to continue from when maxResults accounts were collected, maxResults <= 0 means no limit.
 func (d *Dumper) DumpToCollector(c DumpCollector, excludeCode, excludeStorage bool, startAddress common.Address, maxResults int) ([]byte, error) {
+	c.OnRoot(common.Hash{}) // We do not calculate the root
+
+	for {
+		batchSize := dumpBatchSize
+		if maxResults > 0 && maxResults < batchSize {
+			batchSize = maxResults
+		}
+		nextKey, err := d.dumpBatch(c, excludeCode, excludeStorage, startAddress, batchSize)
+		if err != nil || nextKey == nil {
+			return nextKey, err
+		}
+		if maxResults > 0 {
+			if maxResults -= batchSize; maxResults == 0 {
+				return nextKey, nil
+			}
+		}
+		startAddress = common.BytesToAddress(nextKey)
+	}
+}
+
+// dumpBatch collects at most maxResults accounts, the state is rewound through the changesets when the block is not the tip
+func (d *Dumper) dumpBatch(c DumpCollector, excludeCode, excludeStorage bool, startAddress common.Address, maxResults int) ([]byte, error) {
 	var nextKey []byte
 	var emptyCodeHash = crypto.Keccak256Hash(nil)
 	var emptyHash = common.Hash{}
@@ -137,13 +248,11 @@ func (d *Dumper) DumpToCollector(c DumpCollector, excludeCode, excludeStorage bo
 	var incarnationList []uint64
 	var addrList []common.Address
 
-	c.OnRoot(emptyHash) // We do not calculate the root
-
 	var acc accounts.Account
 	numberOfResults := 0
 
 	if err := WalkAsOfAccounts(d.db, startAddress, d.blockNumber+1, func(k, v []byte) (bool, error) {
-		if maxResults > 0 && numberOfResults >= maxResults {
+		if numberOfResults >= maxResults {
 			if nextKey == nil {
 				nextKey = make([]byte, len(k))
 			}
@@ -204,7 +313,7 @@ func (d *Dumper) DumpToCollector(c DumpCollector, excludeCode, excludeStorage bo
 				addr,
 				incarnation,
 				common.Hash{}, /* startLocation */
-				d.blockNumber,
+				d.blockNumber+1,
 				func(_, loc, vs []byte) (bool, error) {
 					account.Storage[common.BytesToHash(loc).String()] = common.Bytes2Hex(vs)
 					h, _ := common.HashData(loc)
@@ -242,9 +351,18 @@ func (d *Dumper) Dump(excludeCode, excludeStorage bool) []byte {
 }
 
 // IterativeDump dumps out accounts as json-objects, delimited by linebreaks on stdout
-func (d *Dumper) IterativeDump(excludeCode, excludeStorage bool, output *json.Encoder) {
-	//nolint:errcheck
-	d.DumpToCollector(iterativeDump{output}, excludeCode, excludeStorage, common.Address{}, 0)
+func (d *Dumper) IterativeDump(excludeCode, excludeStorage bool, output *json.Encoder) error {
+	_, err := d.DumpToCollector(iterativeDump{output}, excludeCode, excludeStorage, common.Address{}, 0)
+	return err
+}
+
+// RLPDump writes out accounts as a stream of RLPDumpAccount lists
+func (d *Dumper) RLPDump(excludeCode, excludeStorage bool, w io.Writer) error {
+	c := &rlpDump{w: w}
+	if _, err := d.DumpToCollector(c, excludeCode, excludeStorage, common.Address{}, 0); err != nil {
+		return err
+	}
+	return c.err
 }
 
 // IteratorDump dumps out a batch of accounts starts with the given start key
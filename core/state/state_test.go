@@ -19,6 +19,7 @@ package state
 import (
 	"bytes"
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/holiman/uint256"
@@ -30,6 +31,7 @@ import (
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
 )
 
 var toAddr = common.BytesToAddress
@@ -390,3 +392,76 @@ func TestDump(t *testing.T) {
 		t.Fatalf("dump mismatch:\ngot: %s\nwant: %s\n", got, want)
 	}
 }
+
+func TestDumpStorageAfterBlock(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	addr := toAddr([]byte{0x01})
+	key := common.HexToHash("0x01")
+
+	// block 1 sets the slot to 1, block 2 to 2
+	for blockNum := uint64(1); blockNum <= 2; blockNum++ {
+		state := New(NewPlainStateReader(tx))
+		obj := state.GetOrNewStateObject(addr)
+		obj.setIncarnation(1)
+		state.SetState(addr, &key, *uint256.NewInt(blockNum))
+
+		blockWriter := NewPlainStateWriter(tx, tx, blockNum)
+		if err := state.CommitBlock(params.Rules{}, blockWriter); err != nil {
+			t.Fatal(err)
+		}
+		if err := blockWriter.WriteChangeSets(); err != nil {
+			t.Fatal(err)
+		}
+		if err := blockWriter.WriteHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the storage is the one after the block, like the accounts, also when it is rewound through the changesets
+	for blockNum, want := range map[uint64]string{1: "01", 2: "02"} {
+		dump := NewDumper(tx, blockNum).RawDump(true, false)
+		account, ok := dump.Accounts[addr]
+		if !ok {
+			t.Fatalf("no account in the dump of block %d", blockNum)
+		}
+		if got := account.Storage[key.String()]; got != want {
+			t.Errorf("wrong storage in the dump of block %d: %q, expected %q", blockNum, got, want)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestRLPDump(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	addr := toAddr([]byte{0x01})
+	key := common.HexToHash("0x01")
+
+	state := New(NewPlainStateReader(tx))
+	obj := state.GetOrNewStateObject(addr)
+	obj.setIncarnation(1)
+	obj.AddBalance(uint256.NewInt(22))
+	state.SetState(addr, &key, *uint256.NewInt(7))
+	if err := state.CommitBlock(params.Rules{}, NewPlainStateWriter(tx, tx, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewDumper(tx, 1).RLPDump(false, false, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var account RLPDumpAccount
+	if err := rlp.DecodeBytes(buf.Bytes(), &account); err != nil {
+		t.Fatal(err)
+	}
+	if account.Address != addr || account.Balance.Uint64() != 22 || len(account.Storage) != 1 ||
+		account.Storage[0].Key != key || !bytes.Equal(account.Storage[0].Value, []byte{7}) {
+		t.Errorf("wrong account in the dump: %+v", account)
+	}
+
+	if err := NewDumper(tx, 1).RLPDump(false, false, failingWriter{}); err == nil {
+		t.Errorf("expected the write error")
+	}
+}
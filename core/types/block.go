@@ -99,6 +99,8 @@ type Header struct {
 	Eip1559     bool           // to avoid relying on BaseFee != nil for that
 	Seal        []rlp.RawValue // AuRa POA network field
 	WithSeal    bool           // to avoid relying on Seal != nil for that
+
+	WithdrawalsHash *common.Hash `json:"withdrawalsRoot"` // EIP-4895, nil before Shanghai
 }
 
 func (h Header) EncodingSize() int {
@@ -174,11 +176,18 @@ func (h Header) EncodingSize() int {
 		}
 		encodingSize += baseFeeLen
 	}
+	if h.WithdrawalsHash != nil {
+		encodingSize += 33
+	}
 
 	return encodingSize
 }
 
 func (h Header) EncodeRLP(w io.Writer) error {
+	// WithdrawalsHash follows BaseFee, DecodeRLP only reads it after a BaseFee
+	if h.WithdrawalsHash != nil && !h.Eip1559 {
+		return fmt.Errorf("header with WithdrawalsHash but without BaseFee")
+	}
 	// Precompute the size of the encoding
 	encodingSize := 33 /* ParentHash */ + 33 /* UncleHash */ + 21 /* Coinbase */ + 33 /* Root */ + 33 /* TxHash */ +
 		33 /* ReceiptHash */ + 259 /* Bloom */
@@ -256,6 +265,9 @@ func (h Header) EncodeRLP(w io.Writer) error {
 		}
 		encodingSize += baseFeeLen
 	}
+	if h.WithdrawalsHash != nil {
+		encodingSize += 33
+	}
 
 	var b [33]byte
 	// Prefix
@@ -424,6 +436,16 @@ func (h Header) EncodeRLP(w io.Writer) error {
 		}
 	}
 
+	if h.WithdrawalsHash != nil {
+		b[0] = 128 + 32
+		if _, err := w.Write(b[:1]); err != nil {
+			return err
+		}
+		if _, err := w.Write(h.WithdrawalsHash.Bytes()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -543,6 +565,21 @@ func (h *Header) DecodeRLP(s *rlp.Stream) error {
 		}
 		h.Eip1559 = true
 		h.BaseFee = new(big.Int).SetBytes(b)
+		if b, err = s.Bytes(); err != nil {
+			if errors.Is(err, rlp.EOL) {
+				h.WithdrawalsHash = nil
+				if err := s.ListEnd(); err != nil {
+					return fmt.Errorf("close header struct (no withdrawals hash): %w", err)
+				}
+				return nil
+			}
+			return fmt.Errorf("read WithdrawalsHash: %w", err)
+		}
+		if len(b) != 32 {
+			return fmt.Errorf("wrong size for WithdrawalsHash: %d", len(b))
+		}
+		h.WithdrawalsHash = new(common.Hash)
+		copy(h.WithdrawalsHash[:], b)
 	}
 	if err := s.ListEnd(); err != nil {
 		return fmt.Errorf("close header struct: %w", err)
@@ -623,10 +660,11 @@ func (h *Header) copySeal() []rlp.RawValue {
 }
 
 // Body is a simple (mutable, non-safe) data container for storing and moving
-// a block's data contents (transactions and uncles) together.
+// a block's data contents (transactions, uncles and withdrawals) together.
 type Body struct {
 	Transactions []Transaction
 	Uncles       []*Header
+	Withdrawals  []*Withdrawal // nil before Shanghai
 }
 
 // RawBody is semi-parsed variant of Body, where transactions are still unparsed RLP strings
@@ -635,12 +673,14 @@ type Body struct {
 type RawBody struct {
 	Transactions [][]byte
 	Uncles       []*Header
+	Withdrawals  []*Withdrawal
 }
 
 type BodyForStorage struct {
-	BaseTxId uint64
-	TxAmount uint32
-	Uncles   []*Header
+	BaseTxId    uint64
+	TxAmount    uint32
+	Uncles      []*Header
+	Withdrawals []*Withdrawal `rlp:"optional"`
 }
 
 // Block represents an entire block in the Ethereum blockchain.
@@ -648,6 +688,7 @@ type Block struct {
 	header       *Header
 	uncles       []*Header
 	transactions Transactions
+	withdrawals  []*Withdrawal
 
 	// caches
 	hash atomic.Value
@@ -740,6 +781,10 @@ func (rb RawBody) payloadSize() (payloadSize int, txsLen, unclesLen int) {
 		payloadSize += (bits.Len(uint(unclesLen)) + 7) / 8
 	}
 	payloadSize += unclesLen
+	// size of Withdrawals
+	if rb.Withdrawals != nil {
+		payloadSize += WithdrawalsEncodingSize(rb.Withdrawals)
+	}
 	return payloadSize, txsLen, unclesLen
 }
 
@@ -768,6 +813,12 @@ func (rb RawBody) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	// encode Withdrawals
+	if rb.Withdrawals != nil {
+		if err := EncodeWithdrawals(rb.Withdrawals, w, b[:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -809,6 +860,13 @@ func (rb *RawBody) DecodeRLP(s *rlp.Stream) error {
 	if err = s.ListEnd(); err != nil {
 		return err
 	}
+	// decode optional Withdrawals
+	if rb.Withdrawals, err = DecodeWithdrawals(s); err != nil {
+		if !errors.Is(err, rlp.EOL) {
+			return err
+		}
+		rb.Withdrawals = nil
+	}
 	return s.ListEnd()
 }
 
@@ -854,6 +912,10 @@ func (bb Body) payloadSize() (payloadSize int, txsLen, unclesLen int) {
 		payloadSize += (bits.Len(uint(unclesLen)) + 7) / 8
 	}
 	payloadSize += unclesLen
+	// size of Withdrawals
+	if bb.Withdrawals != nil {
+		payloadSize += WithdrawalsEncodingSize(bb.Withdrawals)
+	}
 	return payloadSize, txsLen, unclesLen
 }
 
@@ -893,6 +955,12 @@ func (bb Body) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	// encode Withdrawals
+	if bb.Withdrawals != nil {
+		if err := EncodeWithdrawals(bb.Withdrawals, w, b[:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -934,6 +1002,13 @@ func (bb *Body) DecodeRLP(s *rlp.Stream) error {
 	if err = s.ListEnd(); err != nil {
 		return err
 	}
+	// decode optional Withdrawals
+	if bb.Withdrawals, err = DecodeWithdrawals(s); err != nil {
+		if !errors.Is(err, rlp.EOL) {
+			return err
+		}
+		bb.Withdrawals = nil
+	}
 	return s.ListEnd()
 }
 
@@ -977,10 +1052,24 @@ func NewBlock(header *Header, txs []Transaction, uncles []*Header, receipts []*R
 	return b
 }
 
+// NewBlockWithWithdrawals creates a new block like NewBlock, additionally the
+// WithdrawalsHash in header is set to the value derived from the given withdrawals.
+// Withdrawals are nil for the blocks before Shanghai.
+func NewBlockWithWithdrawals(header *Header, txs []Transaction, uncles []*Header, receipts []*Receipt, withdrawals []*Withdrawal) *Block {
+	b := NewBlock(header, txs, uncles, receipts)
+	if withdrawals != nil {
+		withdrawalsHash := DeriveSha(Withdrawals(withdrawals))
+		b.header.WithdrawalsHash = &withdrawalsHash
+		b.withdrawals = make([]*Withdrawal, len(withdrawals))
+		copy(b.withdrawals, withdrawals)
+	}
+	return b
+}
+
 // NewBlockFromStorage like NewBlock but used to create Block object when read it from DB
 // in this case no reason to copy parts, or re-calculate headers fields - they are all stored in DB
-func NewBlockFromStorage(hash common.Hash, header *Header, txs []Transaction, uncles []*Header) *Block {
-	b := &Block{header: header, td: new(big.Int), transactions: txs, uncles: uncles}
+func NewBlockFromStorage(hash common.Hash, header *Header, txs []Transaction, uncles []*Header, withdrawals []*Withdrawal) *Block {
+	b := &Block{header: header, td: new(big.Int), transactions: txs, uncles: uncles, withdrawals: withdrawals}
 	b.hash.Store(hash)
 	return b
 }
@@ -1010,6 +1099,10 @@ func CopyHeader(h *Header) *Header {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
 	}
+	if h.WithdrawalsHash != nil {
+		withdrawalsHash := *h.WithdrawalsHash
+		cpy.WithdrawalsHash = &withdrawalsHash
+	}
 	cpy.Seal = h.copySeal()
 	return &cpy
 }
@@ -1059,6 +1152,13 @@ func (bb *Block) DecodeRLP(s *rlp.Stream) error {
 	if err = s.ListEnd(); err != nil {
 		return err
 	}
+	// decode optional Withdrawals
+	if bb.withdrawals, err = DecodeWithdrawals(s); err != nil {
+		if !errors.Is(err, rlp.EOL) {
+			return err
+		}
+		bb.withdrawals = nil
+	}
 	if err = s.ListEnd(); err != nil {
 		return err
 	}
@@ -1112,6 +1212,10 @@ func (bb Block) payloadSize() (payloadSize int, txsLen, unclesLen int) {
 		payloadSize += (bits.Len(uint(unclesLen)) + 7) / 8
 	}
 	payloadSize += unclesLen
+	// size of Withdrawals
+	if bb.withdrawals != nil {
+		payloadSize += WithdrawalsEncodingSize(bb.withdrawals)
+	}
 	return payloadSize, txsLen, unclesLen
 }
 
@@ -1165,6 +1269,12 @@ func (bb Block) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	// encode Withdrawals
+	if bb.withdrawals != nil {
+		if err := EncodeWithdrawals(bb.withdrawals, w, b[:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1180,6 +1290,7 @@ func (b *StorageBlock) DecodeRLP(s *rlp.Stream) error {
 
 func (b *Block) Uncles() []*Header          { return b.uncles }
 func (b *Block) Transactions() Transactions { return b.transactions }
+func (b *Block) Withdrawals() []*Withdrawal { return b.withdrawals }
 
 func (b *Block) Transaction(hash common.Hash) Transaction {
 	for _, transaction := range b.transactions {
@@ -1221,7 +1332,7 @@ func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
 func (b *Block) Body() *Body {
-	bd := &Body{Transactions: b.transactions, Uncles: b.uncles}
+	bd := &Body{Transactions: b.transactions, Uncles: b.uncles, Withdrawals: b.withdrawals}
 	bd.SendersFromTxs()
 	return bd
 }
@@ -1237,7 +1348,7 @@ func (b *Block) SendersToTxs(senders []common.Address) {
 // RawBody creates a RawBody based on the block. It is not very efficient, so
 // will probably be removed in favour of RawBlock. Also it panics
 func (b *Block) RawBody() *RawBody {
-	br := &RawBody{Transactions: make([][]byte, len(b.transactions)), Uncles: b.uncles}
+	br := &RawBody{Transactions: make([][]byte, len(b.transactions)), Uncles: b.uncles, Withdrawals: b.withdrawals}
 	for i, tx := range b.transactions {
 		var err error
 		br.Transactions[i], err = rlp.EncodeToBytes(tx)
@@ -1310,6 +1421,15 @@ func (b *Block) Copy() *Block {
 
 	td := big.NewInt(0).Set(b.td)
 
+	var withdrawals []*Withdrawal
+	if b.withdrawals != nil {
+		withdrawals = make([]*Withdrawal, 0, len(b.withdrawals))
+		for _, withdrawal := range b.withdrawals {
+			withdrawalCopy := *withdrawal
+			withdrawals = append(withdrawals, &withdrawalCopy)
+		}
+	}
+
 	if b.ReceivedFrom != nil {
 		panic("ReceivedFrom deep copy is not supported")
 	}
@@ -1318,6 +1438,7 @@ func (b *Block) Copy() *Block {
 		header:       CopyHeader(b.header),
 		uncles:       uncles,
 		transactions: transactions,
+		withdrawals:  withdrawals,
 		hash:         hashValue,
 		size:         sizeValue,
 		td:           td,
@@ -1335,15 +1456,17 @@ func (b *Block) WithSeal(header *Header) *Block {
 		header:       &cpy,
 		transactions: b.transactions,
 		uncles:       b.uncles,
+		withdrawals:  b.withdrawals,
 	}
 }
 
-// WithBody returns a new block with the given transaction and uncle contents.
+// WithBody returns a new block with the given transaction and uncle contents, withdrawals are kept.
 func (b *Block) WithBody(transactions []Transaction, uncles []*Header) *Block {
 	block := &Block{
 		header:       CopyHeader(b.header),
 		transactions: make([]Transaction, len(transactions)),
 		uncles:       make([]*Header, len(uncles)),
+		withdrawals:  b.withdrawals,
 	}
 	copy(block.transactions, transactions)
 	for i := range uncles {
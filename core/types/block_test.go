@@ -205,6 +205,78 @@ func TestEIP2718BlockEncoding(t *testing.T) {
 	}
 }
 
+func TestWithdrawalsEncoding(t *testing.T) {
+	header := &Header{
+		Difficulty: common.Big0,
+		Number:     big.NewInt(17034870),
+		GasLimit:   30000000,
+		Time:       1681338455,
+		BaseFee:    big.NewInt(7),
+		Eip1559:    true,
+	}
+	withdrawals := []*Withdrawal{
+		{Index: 0, Validator: 1, Address: common.HexToAddress("0x00000000219ab540356cbb839cbe05303d7705fa"), Amount: 32_000_000_000},
+		{Index: 1, Validator: 2, Address: common.HexToAddress("0x388c818ca8b9251b393131c08a736a67ccb19297"), Amount: 1},
+	}
+	block := NewBlockWithWithdrawals(header, nil, nil, nil, withdrawals)
+	if block.Header().WithdrawalsHash == nil || *block.Header().WithdrawalsHash != DeriveSha(Withdrawals(withdrawals)) {
+		t.Fatalf("unexpected withdrawals hash %v", block.Header().WithdrawalsHash)
+	}
+
+	enc, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	var decoded Block
+	if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if decoded.Hash() != block.Hash() {
+		t.Errorf("hash mismatch: got %x, want %x", decoded.Hash(), block.Hash())
+	}
+	if !reflect.DeepEqual(decoded.Withdrawals(), withdrawals) {
+		t.Errorf("withdrawals mismatch: got %v, want %v", decoded.Withdrawals(), withdrawals)
+	}
+	if decoded.Size() != common.StorageSize(len(enc)) {
+		t.Errorf("size mismatch: got %v, want %d", decoded.Size(), len(enc))
+	}
+
+	// Pre-Shanghai blocks must not grow a withdrawals field
+	legacy := NewBlock(header, nil, nil, nil)
+	if legacy.Header().WithdrawalsHash != nil || legacy.Withdrawals() != nil {
+		t.Fatal("unexpected withdrawals in a block without them")
+	}
+	enc, err = rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	var decodedLegacy Block
+	if err := rlp.DecodeBytes(enc, &decodedLegacy); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if decodedLegacy.Withdrawals() != nil || decodedLegacy.Hash() != legacy.Hash() {
+		t.Errorf("legacy block did not round-trip")
+	}
+
+	// The header decodes WithdrawalsHash right after BaseFee, so it cannot be encoded without one
+	withoutBaseFee := CopyHeader(block.Header())
+	withoutBaseFee.BaseFee, withoutBaseFee.Eip1559 = nil, false
+	if _, err = rlp.EncodeToBytes(withoutBaseFee); err == nil {
+		t.Errorf("expected error for a withdrawals hash without base fee")
+	}
+	enc, err = rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	var decodedHeader Header
+	if err := rlp.DecodeBytes(enc, &decodedHeader); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if decodedHeader.WithdrawalsHash == nil || *decodedHeader.WithdrawalsHash != *block.Header().WithdrawalsHash || decodedHeader.Hash() != block.Hash() {
+		t.Errorf("header did not round-trip: %+v", decodedHeader)
+	}
+}
+
 func TestUncleHash(t *testing.T) {
 	uncles := make([]*Header, 0)
 	h := CalcUncleHash(uncles)
@@ -30,6 +30,13 @@ func GetDerivedBorTxHash(receiptKey []byte) common.Hash {
 	return common.BytesToHash(crypto.Keccak256(receiptKey))
 }
 
+// ComputeBorTxHash get derived tx hash from block number and hash
+func ComputeBorTxHash(number uint64, hash common.Hash) common.Hash {
+	// hashing using prefix + number + hash
+	borPrefix := []byte("matic-bor-receipt-")
+	return GetDerivedBorTxHash(append(borPrefix, append(BorReceiptKey(number), hash.Bytes()...)...))
+}
+
 // NewBorTransaction create new bor transaction for bor receipt
 func NewBorTransaction() *LegacyTx {
 	return NewTransaction(0, common.Address{}, uint256.NewInt(0), 0, uint256.NewInt(0), make([]byte, 0))
@@ -39,9 +46,7 @@ func NewBorTransaction() *LegacyTx {
 // data and contextual infos like containing block and transactions.
 func DeriveFieldsForBorReceipt(receipt *Receipt, hash common.Hash, number uint64, receipts Receipts) error {
 	// get derived tx hash
-	borPrefix := []byte("matic-bor-receipt-")
-	// hashing using prefix + number + hash
-	txHash := GetDerivedBorTxHash((append(borPrefix, append(BorReceiptKey(number), hash.Bytes()...)...)))
+	txHash := ComputeBorTxHash(number, hash)
 	txIndex := uint(len(receipts))
 
 	// set tx hash and tx index
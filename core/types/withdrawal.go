@@ -0,0 +1,136 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+//go:generate gencodec -type Withdrawal -field-override withdrawalMarshaling -out gen_withdrawal_json.go
+
+// Withdrawal represents a validator withdrawal from the consensus layer (EIP-4895)
+type Withdrawal struct {
+	Index     uint64         `json:"index"`          // monotonically increasing identifier issued by consensus layer
+	Validator uint64         `json:"validatorIndex"` // index of validator associated with withdrawal
+	Address   common.Address `json:"address"`        // target address for withdrawn ether
+	Amount    uint64         `json:"amount"`         // value of withdrawal in Gwei
+}
+
+// field type overrides for gencodec
+type withdrawalMarshaling struct {
+	Index     hexutil.Uint64
+	Validator hexutil.Uint64
+	Amount    hexutil.Uint64
+}
+
+func (w *Withdrawal) payloadSize() int {
+	return rlp.IntSize(w.Index) + rlp.IntSize(w.Validator) + 21 /* Address */ + rlp.IntSize(w.Amount)
+}
+
+func (w *Withdrawal) EncodingSize() int {
+	return int(rlp.ListSize(uint64(w.payloadSize())))
+}
+
+func (w *Withdrawal) EncodeRLP(out io.Writer) error {
+	var b [33]byte
+	if err := EncodeStructSizePrefix(w.payloadSize(), out, b[:]); err != nil {
+		return err
+	}
+	enc := make([]byte, 0, w.payloadSize())
+	enc = rlp.AppendUint64(enc, w.Index)
+	enc = rlp.AppendUint64(enc, w.Validator)
+	enc = append(enc, 128+20)
+	enc = append(enc, w.Address[:]...)
+	enc = rlp.AppendUint64(enc, w.Amount)
+	_, err := out.Write(enc)
+	return err
+}
+
+func (w *Withdrawal) DecodeRLP(s *rlp.Stream) error {
+	_, err := s.List()
+	if err != nil {
+		return err
+	}
+	if w.Index, err = s.Uint(); err != nil {
+		return fmt.Errorf("read Index: %w", err)
+	}
+	if w.Validator, err = s.Uint(); err != nil {
+		return fmt.Errorf("read Validator: %w", err)
+	}
+	var b []byte
+	if b, err = s.Bytes(); err != nil {
+		return fmt.Errorf("read Address: %w", err)
+	}
+	if len(b) != 20 {
+		return fmt.Errorf("wrong size for Address: %d", len(b))
+	}
+	copy(w.Address[:], b)
+	if w.Amount, err = s.Uint(); err != nil {
+		return fmt.Errorf("read Amount: %w", err)
+	}
+	return s.ListEnd()
+}
+
+// Withdrawals implements DerivableList for withdrawals.
+type Withdrawals []*Withdrawal
+
+// Len returns the length of s.
+func (s Withdrawals) Len() int { return len(s) }
+
+// EncodeIndex encodes the i'th withdrawal to w.
+func (s Withdrawals) EncodeIndex(i int, w *bytes.Buffer) {
+	if err := s[i].EncodeRLP(w); err != nil {
+		panic(err)
+	}
+}
+
+// EncodeWithdrawals encodes withdrawals as an RLP list, b is a scratch buffer of at least 33 bytes
+func EncodeWithdrawals(withdrawals []*Withdrawal, w io.Writer, b []byte) error {
+	var withdrawalsLen int
+	for _, withdrawal := range withdrawals {
+		withdrawalsLen += withdrawal.EncodingSize()
+	}
+	if err := EncodeStructSizePrefix(withdrawalsLen, w, b); err != nil {
+		return err
+	}
+	for _, withdrawal := range withdrawals {
+		if err := withdrawal.EncodeRLP(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithdrawalsEncodingSize returns the size of the RLP list of withdrawals, including the list prefix
+func WithdrawalsEncodingSize(withdrawals []*Withdrawal) int {
+	var withdrawalsLen int
+	for _, withdrawal := range withdrawals {
+		withdrawalsLen += withdrawal.EncodingSize()
+	}
+	return int(rlp.ListSize(uint64(withdrawalsLen)))
+}
+
+// DecodeWithdrawals decodes an RLP list of withdrawals, an empty list gives a non-nil empty slice
+func DecodeWithdrawals(s *rlp.Stream) ([]*Withdrawal, error) {
+	if _, err := s.List(); err != nil {
+		return nil, err
+	}
+	withdrawals := []*Withdrawal{}
+	var err error
+	for err == nil {
+		var withdrawal Withdrawal
+		if err = withdrawal.DecodeRLP(s); err != nil {
+			break
+		}
+		withdrawals = append(withdrawals, &withdrawal)
+	}
+	if !errors.Is(err, rlp.EOL) {
+		return nil, err
+	}
+	return withdrawals, s.ListEnd()
+}
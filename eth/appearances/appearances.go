@@ -0,0 +1,205 @@
+// Package appearances keeps an index of transactions by every address appearing in them: sender,
+// recipient, addresses of emitted logs and address-like topics, and all addresses touched by internal
+// calls. The index is built by the AddressAppearances stage and serves erigon_getTransactionsByAddress.
+package appearances
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+)
+
+// AddressAppearances - transactions in which the address appeared
+// address -> blockNum_u64 + txIndex_u32 (DupSort)
+const AddressAppearances = "AddressAppearances"
+
+// TxAppearances - addresses appeared in the transaction, used to unwind and prune AddressAppearances
+// blockNum_u64 + txIndex_u32 -> concatenated addresses
+const TxAppearances = "TxAppearances"
+
+func init() {
+	// The tables are not (yet) known by erigon-lib, register them before any database is opened
+	kv.ChaindataTables = append(kv.ChaindataTables, AddressAppearances, TxAppearances)
+	kv.ChaindataTablesCfg[AddressAppearances] = kv.TableCfgItem{Flags: kv.DupSort}
+	kv.ChaindataTablesCfg[TxAppearances] = kv.TableCfgItem{}
+}
+
+// Appearance - position of the transaction in which an address appeared
+type Appearance struct {
+	BlockNum uint64
+	TxIndex  uint32
+}
+
+const appearanceLen = 8 + 4
+
+func (a Appearance) Encode() []byte {
+	v := make([]byte, appearanceLen)
+	binary.BigEndian.PutUint64(v, a.BlockNum)
+	binary.BigEndian.PutUint32(v[8:], a.TxIndex)
+	return v
+}
+
+func DecodeAppearance(v []byte) (Appearance, error) {
+	if len(v) != appearanceLen {
+		return Appearance{}, fmt.Errorf("wrong size of appearance: %x (size %d)", v, len(v))
+	}
+	return Appearance{BlockNum: binary.BigEndian.Uint64(v), TxIndex: binary.BigEndian.Uint32(v[8:])}, nil
+}
+
+// Less reports whether a is earlier in the chain than b
+func (a Appearance) Less(b Appearance) bool {
+	return a.BlockNum < b.BlockNum || (a.BlockNum == b.BlockNum && a.TxIndex < b.TxIndex)
+}
+
+// WriteTxAppearances indexes the transaction by the given addresses
+func WriteTxAppearances(tx kv.RwTx, blockNum uint64, txIndex int, addrs []common.Address) error {
+	pos := Appearance{BlockNum: blockNum, TxIndex: uint32(txIndex)}.Encode()
+	v := make([]byte, 0, len(addrs)*common.AddressLength)
+	for _, addr := range addrs {
+		if err := tx.Put(AddressAppearances, addr[:], pos); err != nil {
+			return err
+		}
+		v = append(v, addr[:]...)
+	}
+	return tx.Put(TxAppearances, pos, v)
+}
+
+// TruncateAppearances deletes appearances in all blocks starting from the given one
+func TruncateAppearances(tx kv.RwTx, fromBlock uint64) error {
+	return deleteAppearances(tx, fromBlock, math.MaxUint64)
+}
+
+// PruneAppearances deletes appearances in all blocks before the given one
+func PruneAppearances(tx kv.RwTx, toBlock uint64) error {
+	if toBlock == 0 {
+		return nil
+	}
+	return deleteAppearances(tx, 0, toBlock-1)
+}
+
+// deleteAppearances deletes appearances in blocks from fromBlock to toBlock (inclusive)
+func deleteAppearances(tx kv.RwTx, fromBlock, toBlock uint64) error {
+	c, err := tx.RwCursor(TxAppearances)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(dbutils.EncodeBlockNumber(fromBlock)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint64(k) > toBlock {
+			break
+		}
+		for i := 0; i+common.AddressLength <= len(v); i += common.AddressLength {
+			if err = tx.Delete(AddressAppearances, v[i:i+common.AddressLength], k); err != nil {
+				return err
+			}
+		}
+		if err = c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAppearances returns up to limit appearances of the address in blocks from fromBlock to toBlock (inclusive),
+// starting right after the given appearance (if any). Appearances are ordered from the oldest to the newest one,
+// or the other way around if reverse is set. The flag returned is set when there are more appearances to read.
+func ReadAppearances(tx kv.Tx, addr common.Address, fromBlock, toBlock uint64, after *Appearance, reverse bool, limit int) ([]Appearance, bool, error) {
+	c, err := tx.CursorDupSort(AddressAppearances)
+	if err != nil {
+		return nil, false, err
+	}
+	defer c.Close()
+
+	var v []byte
+	if reverse {
+		end := Appearance{BlockNum: toBlock, TxIndex: math.MaxUint32}
+		if after != nil && after.Less(end) {
+			end = *after
+		} else if toBlock < math.MaxUint64 {
+			end = Appearance{BlockNum: toBlock + 1}
+		}
+		if v, err = seekBefore(c, addr[:], end.Encode()); err != nil {
+			return nil, false, err
+		}
+	} else {
+		start := Appearance{BlockNum: fromBlock}
+		if after != nil && !after.Less(start) {
+			start = Appearance{BlockNum: after.BlockNum, TxIndex: after.TxIndex + 1}
+			if after.TxIndex == math.MaxUint32 {
+				start = Appearance{BlockNum: after.BlockNum + 1}
+			}
+		}
+		if v, err = c.SeekBothRange(addr[:], start.Encode()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var result []Appearance
+	for v != nil {
+		a, err := DecodeAppearance(v)
+		if err != nil {
+			return nil, false, err
+		}
+		if a.BlockNum < fromBlock || a.BlockNum > toBlock {
+			break
+		}
+		if len(result) == limit {
+			return result, true, nil
+		}
+		result = append(result, a)
+
+		if reverse {
+			var k []byte
+			if k, v, err = c.Prev(); err != nil {
+				return nil, false, err
+			}
+			if !bytes.Equal(k, addr[:]) {
+				break
+			}
+		} else if _, v, err = c.NextDup(); err != nil {
+			return nil, false, err
+		}
+	}
+	return result, false, nil
+}
+
+// seekBefore positions the cursor at the last value of the key which is less than end
+func seekBefore(c kv.CursorDupSort, key, end []byte) ([]byte, error) {
+	k, _, err := c.SeekExact(key)
+	if err != nil || k == nil {
+		return nil, err
+	}
+	v, err := c.SeekBothRange(key, end)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		// All values are less than end, position at the first value of the next key (if any) and step back
+		if _, _, err = c.SeekExact(key); err != nil {
+			return nil, err
+		}
+		if k, _, err = c.NextNoDup(); err != nil {
+			return nil, err
+		}
+		if k == nil {
+			_, v, err = c.Last()
+			return v, err
+		}
+	}
+	k, v, err = c.Prev()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(k, key) {
+		return nil, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,74 @@
+package appearances
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAppearances(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	addr1, addr2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+
+	require.NoError(t, WriteTxAppearances(tx, 1, 0, []common.Address{addr1, addr2}))
+	require.NoError(t, WriteTxAppearances(tx, 1, 2, []common.Address{addr1}))
+	require.NoError(t, WriteTxAppearances(tx, 3, 1, []common.Address{addr1}))
+	require.NoError(t, WriteTxAppearances(tx, 4, 0, []common.Address{addr2}))
+	require.NoError(t, WriteTxAppearances(tx, 5, 7, []common.Address{addr1, addr2}))
+
+	all := []Appearance{{1, 0}, {1, 2}, {3, 1}, {5, 7}}
+	found, more, err := ReadAppearances(tx, addr1, 0, math.MaxUint64, nil, false, 10)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, all, found)
+
+	// forward pages
+	found, more, err = ReadAppearances(tx, addr1, 0, math.MaxUint64, nil, false, 2)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Equal(t, all[:2], found)
+	found, more, err = ReadAppearances(tx, addr1, 0, math.MaxUint64, &found[1], false, 2)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, all[2:], found)
+
+	// backward pages
+	found, more, err = ReadAppearances(tx, addr1, 0, math.MaxUint64, nil, true, 3)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Equal(t, []Appearance{{5, 7}, {3, 1}, {1, 2}}, found)
+	found, more, err = ReadAppearances(tx, addr1, 0, math.MaxUint64, &found[2], true, 3)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, []Appearance{{1, 0}}, found)
+
+	// block range
+	found, _, err = ReadAppearances(tx, addr1, 2, 4, nil, false, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Appearance{{3, 1}}, found)
+	found, _, err = ReadAppearances(tx, addr1, 2, 4, nil, true, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Appearance{{3, 1}}, found)
+	found, _, err = ReadAppearances(tx, addr2, 0, 3, nil, true, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Appearance{{1, 0}}, found)
+
+	// unknown address
+	found, more, err = ReadAppearances(tx, common.HexToAddress("0x03"), 0, math.MaxUint64, nil, true, 10)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Empty(t, found)
+
+	// unwind and prune
+	require.NoError(t, TruncateAppearances(tx, 5))
+	require.NoError(t, PruneAppearances(tx, 3))
+	found, _, err = ReadAppearances(tx, addr1, 0, math.MaxUint64, nil, false, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Appearance{{3, 1}}, found)
+	found, _, err = ReadAppearances(tx, addr2, 0, math.MaxUint64, nil, false, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Appearance{{4, 0}}, found)
+}
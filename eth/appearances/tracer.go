@@ -0,0 +1,87 @@
+package appearances
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// Tracer collects addresses appearing in a single transaction. Call Reset before reusing it for the next transaction.
+type Tracer struct {
+	addrs map[common.Address]struct{}
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{addrs: map[common.Address]struct{}{}}
+}
+
+// Reset prepares the tracer for the next transaction
+func (t *Tracer) Reset() {
+	t.addrs = map[common.Address]struct{}{}
+}
+
+// Addresses returns addresses collected since the last Reset, sorted
+func (t *Tracer) Addresses() []common.Address {
+	addrs := make([]common.Address, 0, len(t.addrs))
+	for addr := range t.addrs {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return addrs
+}
+
+// CaptureLogs adds addresses of the logs emitted by the transaction, and topics which look like addresses:
+// 12 zero bytes followed by a non-zero address, the way ABI encodes indexed address arguments
+func (t *Tracer) CaptureLogs(logs []*types.Log) {
+	for _, l := range logs {
+		t.addrs[l.Address] = struct{}{}
+		for _, topic := range l.Topics {
+			if isAddressTopic(topic) {
+				t.addrs[common.BytesToAddress(topic[12:])] = struct{}{}
+			}
+		}
+	}
+}
+
+func isAddressTopic(topic common.Hash) bool {
+	for _, b := range topic[:12] {
+		if b != 0 {
+			return false
+		}
+	}
+	return common.BytesToAddress(topic[12:]) != (common.Address{})
+}
+
+func (t *Tracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
+	t.addrs[from] = struct{}{}
+	if !precompile {
+		t.addrs[to] = struct{}{}
+	}
+}
+
+func (t *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *Tracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, d time.Duration, err error) {
+}
+
+func (t *Tracer) CaptureSelfDestruct(from common.Address, to common.Address, value *big.Int) {
+	t.addrs[from] = struct{}{}
+	t.addrs[to] = struct{}{}
+}
+
+func (t *Tracer) CaptureAccountRead(account common.Address) error {
+	return nil
+}
+
+func (t *Tracer) CaptureAccountWrite(account common.Address) error {
+	return nil
+}
@@ -240,16 +240,18 @@ func New(stack *node.Node, config *ethconfig.Config, txpoolCfg txpool2.Config, l
 			return res
 		}
 
-		d66, err := setupDiscovery(backend.config.EthDiscoveryURLs)
+		d68, err := setupDiscovery(backend.config.EthDiscoveryURLs)
 		if err != nil {
 			return nil, err
 		}
 
-		cfg66 := stack.Config().P2P
-		cfg66.NodeDatabase = filepath.Join(stack.Config().DataDir, "nodes", "eth66")
-		server66 := sentry.NewGrpcServer(backend.sentryCtx, d66, readNodeInfo, &cfg66, eth.ETH66)
-		backend.sentryServers = append(backend.sentryServers, server66)
-		sentries = []direct.SentryClient{direct.NewSentryClientDirect(eth.ETH66, server66)}
+		cfg68 := stack.Config().P2P
+		cfg68.NodeDatabase = filepath.Join(stack.Config().DataDir, "nodes", eth.ProtocolToString[eth.ETH68])
+		server68 := sentry.NewGrpcServer(backend.sentryCtx, d68, readNodeInfo, &cfg68, eth.ETH68)
+		server68.ServeSnap(backend.chainDB)
+		backend.sentryServers = append(backend.sentryServers, server68)
+		// the sentry speaks eth/66 message ids with the core for any negotiated version
+		sentries = []direct.SentryClient{direct.NewSentryClientDirect(eth.ETH66, server68)}
 
 		go func() {
 			logEvery := time.NewTicker(120 * time.Second)
@@ -318,6 +320,13 @@ func New(stack *node.Node, config *ethconfig.Config, txpoolCfg txpool2.Config, l
 	}
 
 	backend.engine = ethconsensusconfig.CreateConsensusEngine(chainConfig, logger, consensusConfig, config.Miner.Notify, config.Miner.Noverify, config.HeimdallURL, config.WithoutHeimdall, stack.DataDir(), allSnapshots)
+	if casted, ok := backend.engine.(*bor.Bor); ok && config.HeimdallMock != "" {
+		heimdallClient, err := bor.NewMockHeimdallClientFromFile(config.HeimdallMock)
+		if err != nil {
+			return nil, err
+		}
+		casted.SetHeimdallClient(heimdallClient)
+	}
 
 	log.Info("Initialising Ethereum protocol", "network", config.NetworkID)
 
@@ -544,11 +553,14 @@ func New(stack *node.Node, config *ethconfig.Config, txpoolCfg txpool2.Config, l
 			return nil, err
 		}
 
-		var borDb kv.RoDB
+		var borDb, parliaDb kv.RoDB
 		if casted, ok := backend.engine.(*bor.Bor); ok {
 			borDb = casted.DB
 		}
-		apiList := commands.APIList(chainKv, borDb, ethRpcClient, txPoolRpcClient, miningRpcClient, starkNetRpcClient, ff, stateCache, blockReader, httpRpcCfg)
+		if casted, ok := backend.engine.(*parlia.Parlia); ok {
+			parliaDb = casted.DB
+		}
+		apiList := commands.APIList(chainKv, borDb, parliaDb, ethRpcClient, txPoolRpcClient, miningRpcClient, starkNetRpcClient, ff, stateCache, blockReader, httpRpcCfg)
 		go func() {
 			if err := cli.StartRpcServer(ctx, httpRpcCfg, apiList); err != nil {
 				log.Error(err.Error())
@@ -769,6 +781,13 @@ func (s *Ethereum) Peers(ctx context.Context) (*remote.PeersReply, error) {
 	return &reply, nil
 }
 
+func (s *Ethereum) SyncStatusTracker() *stages.StatusTracker {
+	if s.stagedSync == nil { // sync is not initialized yet
+		return nil
+	}
+	return s.stagedSync.Status()
+}
+
 // Protocols returns all the currently configured
 // network protocols to start.
 func (s *Ethereum) Protocols() []p2p.Protocol {
@@ -213,6 +213,9 @@ type Config struct {
 
 	// No heimdall service
 	WithoutHeimdall bool
+
+	// Path to the config of the in-process mock Heimdall, replaces the Heimdall service
+	HeimdallMock string
 	// Ethstats service
 	Ethstats string
 }
@@ -165,7 +165,7 @@ func AnswerGetBlockBodiesQuery(db kv.Tx, query GetBlockBodiesPacket) []rlp.RawVa
 	return bodies
 }
 
-func AnswerGetReceiptsQuery(db kv.Tx, query GetReceiptsPacket) ([]rlp.RawValue, error) { //nolint:unparam
+func AnswerGetReceiptsQuery(ctx context.Context, db kv.Tx, query GetReceiptsPacket, blockReader interfaces.FullBlockReader) ([]rlp.RawValue, error) {
 	// Gather state data until the fetch or network limits is reached
 	var (
 		bytes    int
@@ -177,12 +177,12 @@ func AnswerGetReceiptsQuery(db kv.Tx, query GetReceiptsPacket) ([]rlp.RawValue,
 			break
 		}
 		// Retrieve the requested block's receipts
-		results, err := rawdb.ReadReceiptsByHash(db, hash)
+		results, err := rawdb.ReadReceiptsByHashWithReader(ctx, db, blockReader, hash)
 		if err != nil {
 			return nil, err
 		}
 		if results == nil {
-			header, err := rawdb.ReadHeaderByHash(db, hash)
+			header, err := blockReader.HeaderByHash(ctx, db, hash)
 			if err != nil {
 				return nil, err
 			}
@@ -34,10 +34,14 @@ import (
 // Constants to match up protocol versions and messages
 const (
 	ETH66 = 66
+	ETH67 = 67
+	ETH68 = 68
 )
 
 var ProtocolToString = map[uint]string{
 	ETH66: "eth66",
+	ETH67: "eth67",
+	ETH68: "eth68",
 }
 
 // ProtocolName is the official short name of the `eth` protocol used during
@@ -86,6 +90,34 @@ var ToProto = map[uint]map[uint64]proto_sentry.MessageId{
 		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
 		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
 	},
+	ETH67: {
+		GetBlockHeadersMsg:            proto_sentry.MessageId_GET_BLOCK_HEADERS_66,
+		BlockHeadersMsg:               proto_sentry.MessageId_BLOCK_HEADERS_66,
+		GetBlockBodiesMsg:             proto_sentry.MessageId_GET_BLOCK_BODIES_66,
+		BlockBodiesMsg:                proto_sentry.MessageId_BLOCK_BODIES_66,
+		GetReceiptsMsg:                proto_sentry.MessageId_GET_RECEIPTS_66,
+		ReceiptsMsg:                   proto_sentry.MessageId_RECEIPTS_66,
+		NewBlockHashesMsg:             proto_sentry.MessageId_NEW_BLOCK_HASHES_66,
+		NewBlockMsg:                   proto_sentry.MessageId_NEW_BLOCK_66,
+		TransactionsMsg:               proto_sentry.MessageId_TRANSACTIONS_66,
+		NewPooledTransactionHashesMsg: proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_66,
+		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
+		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
+	},
+	ETH68: {
+		GetBlockHeadersMsg:            proto_sentry.MessageId_GET_BLOCK_HEADERS_66,
+		BlockHeadersMsg:               proto_sentry.MessageId_BLOCK_HEADERS_66,
+		GetBlockBodiesMsg:             proto_sentry.MessageId_GET_BLOCK_BODIES_66,
+		BlockBodiesMsg:                proto_sentry.MessageId_BLOCK_BODIES_66,
+		GetReceiptsMsg:                proto_sentry.MessageId_GET_RECEIPTS_66,
+		ReceiptsMsg:                   proto_sentry.MessageId_RECEIPTS_66,
+		NewBlockHashesMsg:             proto_sentry.MessageId_NEW_BLOCK_HASHES_66,
+		NewBlockMsg:                   proto_sentry.MessageId_NEW_BLOCK_66,
+		TransactionsMsg:               proto_sentry.MessageId_TRANSACTIONS_66,
+		NewPooledTransactionHashesMsg: proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68,
+		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
+		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
+	},
 }
 
 var FromProto = map[uint]map[proto_sentry.MessageId]uint64{
@@ -105,6 +137,34 @@ var FromProto = map[uint]map[proto_sentry.MessageId]uint64{
 		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
 		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
 	},
+	ETH67: {
+		proto_sentry.MessageId_GET_BLOCK_HEADERS_66:             GetBlockHeadersMsg,
+		proto_sentry.MessageId_BLOCK_HEADERS_66:                 BlockHeadersMsg,
+		proto_sentry.MessageId_GET_BLOCK_BODIES_66:              GetBlockBodiesMsg,
+		proto_sentry.MessageId_BLOCK_BODIES_66:                  BlockBodiesMsg,
+		proto_sentry.MessageId_GET_RECEIPTS_66:                  GetReceiptsMsg,
+		proto_sentry.MessageId_RECEIPTS_66:                      ReceiptsMsg,
+		proto_sentry.MessageId_NEW_BLOCK_HASHES_66:              NewBlockHashesMsg,
+		proto_sentry.MessageId_NEW_BLOCK_66:                     NewBlockMsg,
+		proto_sentry.MessageId_TRANSACTIONS_66:                  TransactionsMsg,
+		proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_66: NewPooledTransactionHashesMsg,
+		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
+		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
+	},
+	ETH68: {
+		proto_sentry.MessageId_GET_BLOCK_HEADERS_66:             GetBlockHeadersMsg,
+		proto_sentry.MessageId_BLOCK_HEADERS_66:                 BlockHeadersMsg,
+		proto_sentry.MessageId_GET_BLOCK_BODIES_66:              GetBlockBodiesMsg,
+		proto_sentry.MessageId_BLOCK_BODIES_66:                  BlockBodiesMsg,
+		proto_sentry.MessageId_GET_RECEIPTS_66:                  GetReceiptsMsg,
+		proto_sentry.MessageId_RECEIPTS_66:                      ReceiptsMsg,
+		proto_sentry.MessageId_NEW_BLOCK_HASHES_66:              NewBlockHashesMsg,
+		proto_sentry.MessageId_NEW_BLOCK_66:                     NewBlockMsg,
+		proto_sentry.MessageId_TRANSACTIONS_66:                  TransactionsMsg,
+		proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68: NewPooledTransactionHashesMsg,
+		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
+		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
+	},
 }
 
 // Packet represents a p2p message in the `eth` protocol.
@@ -400,12 +460,14 @@ type BlockBodiesRLPPacket66 struct {
 type BlockBody struct {
 	Transactions []types.Transaction // Transactions contained within a block
 	Uncles       []*types.Header     // Uncles contained within a block
+	Withdrawals  []*types.Withdrawal // Withdrawals contained within a block, nil before Shanghai
 }
 
 // BlockRawBody represents the data content of a single block.
 type BlockRawBody struct {
-	Transactions [][]byte        // Transactions contained within a block
-	Uncles       []*types.Header // Uncles contained within a block
+	Transactions [][]byte            // Transactions contained within a block
+	Uncles       []*types.Header     // Uncles contained within a block
+	Withdrawals  []*types.Withdrawal // Withdrawals contained within a block, nil before Shanghai
 }
 
 func (bb BlockBody) EncodeRLP(w io.Writer) error {
@@ -448,6 +510,10 @@ func (bb BlockBody) EncodeRLP(w io.Writer) error {
 		encodingSize += (bits.Len(uint(unclesLen)) + 7) / 8
 	}
 	encodingSize += unclesLen
+	// size of Withdrawals
+	if bb.Withdrawals != nil {
+		encodingSize += types.WithdrawalsEncodingSize(bb.Withdrawals)
+	}
 	var b [33]byte
 	// prefix
 	if err := types.EncodeStructSizePrefix(encodingSize, w, b[:]); err != nil {
@@ -482,6 +548,12 @@ func (bb BlockBody) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	// encode Withdrawals
+	if bb.Withdrawals != nil {
+		if err := types.EncodeWithdrawals(bb.Withdrawals, w, b[:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -523,6 +595,13 @@ func (bb *BlockBody) DecodeRLP(s *rlp.Stream) error {
 	if err = s.ListEnd(); err != nil {
 		return err
 	}
+	// decode optional Withdrawals
+	if bb.Withdrawals, err = types.DecodeWithdrawals(s); err != nil {
+		if !errors.Is(err, rlp.EOL) {
+			return err
+		}
+		bb.Withdrawals = nil
+	}
 	return s.ListEnd()
 }
 
@@ -571,6 +650,10 @@ func (rb BlockRawBody) EncodeRLP(w io.Writer) error {
 		encodingSize += (bits.Len(uint(unclesLen)) + 7) / 8
 	}
 	encodingSize += unclesLen
+	// size of Withdrawals
+	if rb.Withdrawals != nil {
+		encodingSize += types.WithdrawalsEncodingSize(rb.Withdrawals)
+	}
 	var b [33]byte
 	// prefix
 	if err := types.EncodeStructSizePrefix(encodingSize, w, b[:]); err != nil {
@@ -594,6 +677,12 @@ func (rb BlockRawBody) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	// encode Withdrawals
+	if rb.Withdrawals != nil {
+		if err := types.EncodeWithdrawals(rb.Withdrawals, w, b[:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -638,20 +727,28 @@ func (rb *BlockRawBody) DecodeRLP(s *rlp.Stream) error {
 	if err = s.ListEnd(); err != nil {
 		return err
 	}
+	// decode optional Withdrawals
+	if rb.Withdrawals, err = types.DecodeWithdrawals(s); err != nil {
+		if !errors.Is(err, rlp.EOL) {
+			return err
+		}
+		rb.Withdrawals = nil
+	}
 	return s.ListEnd()
 }
 
-// Unpack retrieves the transactions and uncles from the range packet and returns
+// Unpack retrieves the transactions, uncles and withdrawals from the range packet and returns
 // them in a split flat format that's more consistent with the internal data structures.
-func (p *BlockRawBodiesPacket) Unpack() ([][][]byte, [][]*types.Header) {
+func (p *BlockRawBodiesPacket) Unpack() ([][][]byte, [][]*types.Header, [][]*types.Withdrawal) {
 	var (
-		txset    = make([][][]byte, len(*p))
-		uncleset = make([][]*types.Header, len(*p))
+		txset         = make([][][]byte, len(*p))
+		uncleset      = make([][]*types.Header, len(*p))
+		withdrawalset = make([][]*types.Withdrawal, len(*p))
 	)
 	for i, body := range *p {
-		txset[i], uncleset[i] = body.Transactions, body.Uncles
+		txset[i], uncleset[i], withdrawalset[i] = body.Transactions, body.Uncles, body.Withdrawals
 	}
-	return txset, uncleset
+	return txset, uncleset, withdrawalset
 }
 
 // GetNodeDataPacket represents a trie node data query.
@@ -702,6 +799,14 @@ type ReceiptsRLPPacket66 struct {
 // NewPooledTransactionHashesPacket represents a transaction announcement packet.
 type NewPooledTransactionHashesPacket []common.Hash
 
+// NewPooledTransactionHashesPacket68 represents a transaction announcement packet on eth/68 and newer.
+// Types and sizes allow to skip transactions which are not wanted without requesting them.
+type NewPooledTransactionHashesPacket68 struct {
+	Types  []byte
+	Sizes  []uint32
+	Hashes []common.Hash
+}
+
 // GetPooledTransactionsPacket represents a transaction query.
 type GetPooledTransactionsPacket []common.Hash
 
@@ -929,6 +1034,9 @@ func (*ReceiptsPacket) Kind() byte   { return ReceiptsMsg }
 func (*NewPooledTransactionHashesPacket) Name() string { return "NewPooledTransactionHashes" }
 func (*NewPooledTransactionHashesPacket) Kind() byte   { return NewPooledTransactionHashesMsg }
 
+func (*NewPooledTransactionHashesPacket68) Name() string { return "NewPooledTransactionHashes" }
+func (*NewPooledTransactionHashesPacket68) Kind() byte   { return NewPooledTransactionHashesMsg }
+
 func (*GetPooledTransactionsPacket) Name() string { return "GetPooledTransactions" }
 func (*GetPooledTransactionsPacket) Kind() byte   { return GetPooledTransactionsMsg }
 
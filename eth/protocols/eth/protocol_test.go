@@ -268,6 +268,28 @@ func TestEth66Messages(t *testing.T) {
 		}
 	}
 }
+func TestBlockRawBodyWithdrawals(t *testing.T) {
+	uncle := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(2), Extra: []byte{}}
+	withdrawals := []*types.Withdrawal{{Index: 1, Validator: 2, Address: common.HexToAddress("0x3"), Amount: 4}}
+	for _, body := range []*BlockRawBody{
+		{Transactions: [][]byte{}, Uncles: []*types.Header{uncle}},
+		{Transactions: [][]byte{}, Uncles: []*types.Header{}, Withdrawals: []*types.Withdrawal{}},
+		{Transactions: [][]byte{}, Uncles: []*types.Header{uncle}, Withdrawals: withdrawals},
+	} {
+		enc, err := rlp.EncodeToBytes(BlockRawBodiesPacket66{1111, BlockRawBodiesPacket{body}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var packet BlockRawBodiesPacket66
+		if err := rlp.DecodeBytes(enc, &packet); err != nil {
+			t.Fatal(err)
+		}
+		_, uncles, decoded := packet.Unpack()
+		assert.Equal(t, len(body.Uncles), len(uncles[0]))
+		assert.Equal(t, body.Withdrawals, decoded[0])
+	}
+}
+
 func TestDecodePooledTx(t *testing.T) {
 	in := []string{
 		"f90420a0e2c35c05dbb07cf2e0784116966bd5d582846bdb54c928e740492262ccfeb40ca0c4fd5ae5baa1180ff6dd8c4b01cc2d93f8907de27ac2016648c6373478bef503a0b35dca76c30d732374041438c26b9ee8f643744446ab53c7f38fa2074eaac992a08518fdec2a229d82884b323937f4a0f09b02ac746aaf6773d482e40b597c05c7a08f8d9110ea6acca9554e2f80cac3cedbb143fbe4a1ab3e60ed31f4c74d748deca016b0027c133e3372deffb4e86e1bc5e975d1d610a9f2fdea3a74e4d60ef864baa00c6332c83e85ebb9a3a7afd313df4a82c7e5decb5872e264a3f11da610245f00a08bedc14cf929b536e30df8538b870518016096a4870c7c20e5b48da7b03df384a05a9ff87469a5332cbd18520dcdf25c17425de71d9efb56072e023246617145ffa08a165cd6bdc433f6b97e8a6e204ad59cbee4084421aa810a1e2cf9c828e949e8a09521157629d905e3337a9b54522709ccf8890cfd717def560b053926c1862ed3a0284d676704a205a5c932dba13672caafe13a727b3a3cd7dc56bc49a6c6c9a7e8a09f12e7753596116fd9ebe554428f927e8afc99bad30ad28cd9deeeaebe21cd99a09497716972c5f7ff311769f185f64adac8c54f096cab5beb7ad80cfc7e773ccfa00e0036fd7086e15f045b11d7a22df7dc99958124ccc7100d499d182becb7ed59a000d8094ecdee29e6c916360f1594d68cda566d9bd2a17a19312eb0487d46b537a03b1bc9e360c01b2d6cf9ba42a06b91b30692a086daf3ba6db2b661cfd5f1fb61a08cdd05c1605609f1979fd55ff7de0a16e41decb7ab3e340b9b729a88ea8cbb5ca0151bc4c1e0cbd896cabdaf5d08830cb608337ac247347786b2191f79bd367d96a0c9d895b6094195745740ff05e2fd4e55b33ae554843cabc70ad64b71a1a8093ca0ffdf8f28142597911de68d59e7f73209aa2573583bc3c2aaa144e2727153a1aea0535a8ccc5eb9f69005021093017da51b54289e24fb24f8f36ee8d421e3df99b7a0b0428184b221db9f5bd7c2c3ab1b4e21cf961f9218bce2308a1f1be2da40195ba0294f7127f5306e5a5a57127b1300351dac1affef78fb7e822fab1175e021c261a03b2d6b186168a6ec0a364ff65e58b34c1b4d85cf0d30cf3158c68b3ff9987621a0eba6b975d9d964dd2db4f0e562cb16ca1b51d42c659df15f28a84c31e2fb7a9aa00d9b8879c571278d965e847752f9b97b12a965b44bfb1a653fa0b983949bad8da0752d870c51884688996ebe5c561f7b05602fe7939b5f3651fb2a848921d3a88ea0bc73d7980607d967aca0c8b07a44a62fcc6f5813587987cf65d972396e45b448a0c3fc9e18bf84f8dbc72f1e5d106f760055982ceabb0fe9b62c37cb73ee0d04d1a083bcb5e1bf1871154a16c9d50c40656feeca78ba66d5f2851a031952f2e4f96ba085fe3f02e443d6be1ddbe9ea3949045e9787350caa744f9432e92046d2331e86",
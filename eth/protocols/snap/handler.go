@@ -0,0 +1,112 @@
+package snap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/eth/protocols/eth"
+	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// maxConcurrentQueries limits the number of requests served at the same time (of all peers),
+// answering account, storage and trie node requests walks the state trie
+const maxConcurrentQueries = 4
+
+var ethVersions = []uint{eth.ETH66, eth.ETH67, eth.ETH68}
+
+// MakeProtocol returns the snap/1 protocol serving the state of db to other nodes.
+// snap is a satellite protocol - only peers which also run eth are served.
+func MakeProtocol(ctx context.Context, db kv.RoDB) p2p.Protocol {
+	sem := make(chan struct{}, maxConcurrentQueries)
+	return p2p.Protocol{
+		Name:    ProtocolName,
+		Version: SNAP1,
+		Length:  ProtocolLength,
+		Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) error {
+			if !peer.RunningCap(eth.ProtocolName, ethVersions) {
+				return fmt.Errorf("peer %s runs snap without eth", peer.ID())
+			}
+			for {
+				msg, err := rw.ReadMsg()
+				if err != nil {
+					return fmt.Errorf("reading message: %w", err)
+				}
+				if msg.Size > maxMessageSize {
+					msg.Discard()
+					return fmt.Errorf("message is too large %d, limit %d", msg.Size, maxMessageSize)
+				}
+				err = handleMessage(ctx, db, sem, msg, rw)
+				msg.Discard()
+				if err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
+
+func handleMessage(ctx context.Context, db kv.RoDB, sem chan struct{}, msg p2p.Msg, w p2p.MsgWriter) error {
+	var requestID uint64
+	var query func(tx kv.Tx) (Packet, error)
+	var emptyResponse func() Packet
+
+	switch msg.Code {
+	case GetAccountRangeMsg:
+		var req GetAccountRangePacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("decode %v: %w", msg, err)
+		}
+		requestID = req.ID
+		query = func(tx kv.Tx) (Packet, error) { return AnswerGetAccountRangeQuery(tx, &req, ctx.Done()) }
+		emptyResponse = func() Packet { return &AccountRangePacket{ID: requestID} }
+	case GetStorageRangesMsg:
+		var req GetStorageRangesPacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("decode %v: %w", msg, err)
+		}
+		requestID = req.ID
+		query = func(tx kv.Tx) (Packet, error) { return AnswerGetStorageRangesQuery(tx, &req, ctx.Done()) }
+		emptyResponse = func() Packet { return &StorageRangesPacket{ID: requestID} }
+	case GetByteCodesMsg:
+		var req GetByteCodesPacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("decode %v: %w", msg, err)
+		}
+		requestID = req.ID
+		query = func(tx kv.Tx) (Packet, error) { return AnswerGetByteCodesQuery(tx, &req) }
+		emptyResponse = func() Packet { return &ByteCodesPacket{ID: requestID} }
+	case GetTrieNodesMsg:
+		var req GetTrieNodesPacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("decode %v: %w", msg, err)
+		}
+		requestID = req.ID
+		query = func(tx kv.Tx) (Packet, error) { return AnswerGetTrieNodesQuery(tx, &req, ctx.Done()) }
+		emptyResponse = func() Packet { return &TrieNodesPacket{ID: requestID} }
+	case AccountRangeMsg, StorageRangesMsg, ByteCodesMsg, TrieNodesMsg:
+		// this node doesn't sync with snap and doesn't send requests
+		return nil
+	default:
+		return fmt.Errorf("unknown message code: %d", msg.Code)
+	}
+
+	select {
+	case sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	var response Packet
+	err := db.View(ctx, func(tx kv.Tx) (err error) {
+		response, err = query(tx)
+		return err
+	})
+	<-sem
+	if err != nil {
+		// it's not the peer's fault, reply with nothing instead of disconnecting
+		log.Warn("[snap] Failed to serve request", "msg", msg.Code, "err", err)
+		response = emptyResponse()
+	}
+	return p2p.Send(w, uint64(response.Kind()), response)
+}
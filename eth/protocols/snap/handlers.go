@@ -0,0 +1,397 @@
+package snap
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+const (
+	// softResponseLimit is the target maximum size of replies to data retrievals.
+	softResponseLimit = 2 * 1024 * 1024
+
+	// maxCodeLookups is the maximum number of bytecodes to serve. This number is
+	// there to limit the number of disk lookups.
+	maxCodeLookups = 1024
+
+	// maxTrieNodeLookups is the maximum number of state trie nodes to serve. This
+	// number is there to limit the number of disk lookups.
+	maxTrieNodeLookups = 1024
+)
+
+var maxHash = common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+// StateRoot returns the root of the state which can be served - the one of hashed state tables and intermediate hashes.
+// Returns an empty hash while these tables are not in sync with each other.
+func StateRoot(tx kv.Tx) (common.Hash, error) {
+	ihProgress, err := stages.GetStageProgress(tx, stages.IntermediateHashes)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hashStateProgress, err := stages.GetStageProgress(tx, stages.HashState)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if ihProgress != hashStateProgress {
+		return common.Hash{}, nil
+	}
+	hash, err := rawdb.ReadCanonicalHash(tx, ihProgress)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	header := rawdb.ReadHeader(tx, hash, ihProgress)
+	if header == nil {
+		return common.Hash{}, nil
+	}
+	return header.Root, nil
+}
+
+func canServe(tx kv.Tx, root common.Hash) (bool, error) {
+	stateRoot, err := StateRoot(tx)
+	if err != nil {
+		return false, err
+	}
+	return (stateRoot != common.Hash{}) && (stateRoot == root), nil
+}
+
+func responseLimit(requested uint64) uint64 {
+	if requested > softResponseLimit {
+		return softResponseLimit
+	}
+	return requested
+}
+
+// proofTrie constructs trie nodes on the paths to the keys (in HEX encoding) from hashed state and intermediate hashes,
+// storage keys are prefixed with the account hash and incarnation
+func proofTrie(tx kv.Tx, root common.Hash, hexes [][]byte, quit <-chan struct{}) (*trie.Trie, error) {
+	// rl - what loader can't take from intermediate hashes, proofRl - what has to be constructed
+	rl, proofRl := trie.NewRetainList(0), trie.NewRetainList(0)
+	for _, hex := range hexes {
+		rl.AddHexWithMarker(hex, true)
+		proofRl.AddHex(hex)
+	}
+	loader := trie.NewFlatDBTrieLoader("snap")
+	if err := loader.Reset(rl, nil, nil, false); err != nil {
+		return nil, err
+	}
+	loader.SetProofRetainer(proofRl)
+	calculatedRoot, err := loader.CalcTrieRoot(tx, []byte{}, quit)
+	if err != nil {
+		return nil, err
+	}
+	if calculatedRoot != root {
+		return nil, fmt.Errorf("state root mismatch: %x, expected %x", calculatedRoot, root)
+	}
+	return loader.ProofTrie(), nil
+}
+
+func keyToHex(key []byte) []byte {
+	var hex []byte
+	hexutil.DecompressNibbles(key, &hex)
+	return hex
+}
+
+// appendProof adds nodes to the proof skipping the ones which are already there
+func appendProof(proof [][]byte, nodes [][]byte) [][]byte {
+	for _, node := range nodes {
+		known := false
+		for _, proofNode := range proof {
+			if bytes.Equal(proofNode, node) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			proof = append(proof, node)
+		}
+	}
+	return proof
+}
+
+func readAccount(tx kv.Tx, addrHash common.Hash) (*accounts.Account, error) {
+	enc, err := tx.GetOne(kv.HashedAccounts, addrHash[:])
+	if err != nil || len(enc) == 0 {
+		return nil, err
+	}
+	var acc accounts.Account
+	if err = acc.DecodeForStorage(enc); err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func slimAccountRLP(acc *accounts.Account) (rlp.RawValue, error) {
+	slim := SlimAccount{Nonce: acc.Nonce, Balance: acc.Balance.ToBig()}
+	if acc.Root != trie.EmptyRoot {
+		slim.Root = acc.Root[:]
+	}
+	if acc.CodeHash != trie.EmptyCodeHash {
+		slim.CodeHash = acc.CodeHash[:]
+	}
+	return rlp.EncodeToBytes(&slim)
+}
+
+// AnswerGetAccountRangeQuery returns consecutive accounts starting from the origin with Merkle proofs
+// of the origin and of the last account. The response is empty if the requested state root can't be served.
+func AnswerGetAccountRangeQuery(tx kv.Tx, req *GetAccountRangePacket, quit <-chan struct{}) (*AccountRangePacket, error) {
+	response := &AccountRangePacket{ID: req.ID}
+	if ok, err := canServe(tx, req.Root); err != nil || !ok {
+		return response, err
+	}
+	limit := responseLimit(req.Bytes)
+
+	c, err := tx.Cursor(kv.HashedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	var hashes []common.Hash
+	size := uint64(0)
+	for k, _, err := c.Seek(req.Origin[:]); k != nil && size < limit; k, _, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		hash := common.BytesToHash(k)
+		hashes = append(hashes, hash)
+		// the size of the slim account is not known before the storage root is calculated
+		size += common.HashLength + 80
+		if bytes.Compare(k, req.Limit[:]) >= 0 {
+			break
+		}
+	}
+
+	// storage roots of the accounts are also taken from the proof trie
+	hexes := [][]byte{keyToHex(req.Origin[:])}
+	for _, hash := range hashes {
+		hexes = append(hexes, keyToHex(hash[:]))
+	}
+	tr, err := proofTrie(tx, req.Root, hexes, quit)
+	if err != nil {
+		return nil, err
+	}
+	for _, hash := range hashes {
+		acc, ok := tr.GetAccount(hash[:])
+		if !ok || acc == nil {
+			return nil, fmt.Errorf("account %x not found in the proof trie", hash)
+		}
+		body, err := slimAccountRLP(acc)
+		if err != nil {
+			return nil, err
+		}
+		response.Accounts = append(response.Accounts, &AccountData{Hash: hash, Body: body})
+	}
+
+	if response.Proof, err = tr.Prove(req.Origin[:], 0, false); err != nil {
+		return nil, err
+	}
+	if len(hashes) > 0 {
+		proof, err := tr.Prove(hashes[len(hashes)-1][:], 0, false)
+		if err != nil {
+			return nil, err
+		}
+		response.Proof = appendProof(response.Proof, proof)
+	}
+	return response, nil
+}
+
+// AnswerGetStorageRangesQuery returns storage slots of the requested accounts. Origin and limit apply to the first account.
+// If its storage is served partially (starting from a non-zero origin or capped by the response size),
+// Merkle proofs of the first and the last slots are added, and this account is the last one in the response.
+func AnswerGetStorageRangesQuery(tx kv.Tx, req *GetStorageRangesPacket, quit <-chan struct{}) (*StorageRangesPacket, error) {
+	response := &StorageRangesPacket{ID: req.ID}
+	if ok, err := canServe(tx, req.Root); err != nil || !ok {
+		return response, err
+	}
+	limit := responseLimit(req.Bytes)
+
+	c, err := tx.CursorDupSort(kv.HashedStorage)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	size := uint64(0)
+	for i, addrHash := range req.Accounts {
+		if size >= limit {
+			break
+		}
+		slotOrigin, slotLimit := common.Hash{}, maxHash
+		if i == 0 {
+			slotOrigin = common.BytesToHash(req.Origin)
+			if len(req.Limit) > 0 {
+				slotLimit = common.BytesToHash(req.Limit)
+			}
+		}
+		acc, err := readAccount(tx, addrHash)
+		if err != nil {
+			return nil, err
+		}
+		if (acc == nil) || (acc.Incarnation == 0) {
+			continue
+		}
+
+		var slots []*StorageData
+		var lastSlot common.Hash
+		abort := false
+		prefix := dbutils.GenerateStoragePrefix(addrHash[:], acc.Incarnation)
+		v, err := c.SeekBothRange(prefix, slotOrigin[:])
+		if err != nil {
+			return nil, err
+		}
+		for ; v != nil; _, v, err = c.NextDup() {
+			if err != nil {
+				return nil, err
+			}
+			if size >= limit {
+				abort = true
+				break
+			}
+			lastSlot = common.BytesToHash(v[:common.HashLength])
+			body, err := rlp.EncodeToBytes(v[common.HashLength:])
+			if err != nil {
+				return nil, err
+			}
+			size += uint64(common.HashLength + len(body))
+			slots = append(slots, &StorageData{Hash: lastSlot, Body: body})
+			if bytes.Compare(lastSlot[:], slotLimit[:]) >= 0 {
+				break
+			}
+		}
+		if len(slots) > 0 {
+			response.Slots = append(response.Slots, slots)
+		}
+
+		if (slotOrigin != common.Hash{}) || (abort && len(slots) > 0) {
+			storagePrefix := keyToHex(prefix)
+			hexes := [][]byte{append(common.CopyBytes(storagePrefix), keyToHex(slotOrigin[:])...)}
+			if len(slots) > 0 {
+				hexes = append(hexes, append(common.CopyBytes(storagePrefix), keyToHex(lastSlot[:])...))
+			}
+			tr, err := proofTrie(tx, req.Root, hexes, quit)
+			if err != nil {
+				return nil, err
+			}
+			if response.Proof, err = tr.Prove(append(common.CopyBytes(addrHash[:]), slotOrigin[:]...), 64, true); err != nil {
+				return nil, err
+			}
+			if len(slots) > 0 {
+				proof, err := tr.Prove(append(common.CopyBytes(addrHash[:]), lastSlot[:]...), 64, true)
+				if err != nil {
+					return nil, err
+				}
+				response.Proof = appendProof(response.Proof, proof)
+			}
+			// proofs are only added for the last account
+			break
+		}
+	}
+	return response, nil
+}
+
+// AnswerGetByteCodesQuery returns bytecodes by their hashes, unknown hashes are skipped
+func AnswerGetByteCodesQuery(tx kv.Tx, req *GetByteCodesPacket) (*ByteCodesPacket, error) {
+	response := &ByteCodesPacket{ID: req.ID}
+	limit := responseLimit(req.Bytes)
+	hashes := req.Hashes
+	if len(hashes) > maxCodeLookups {
+		hashes = hashes[:maxCodeLookups]
+	}
+	size := uint64(0)
+	for _, hash := range hashes {
+		if hash == trie.EmptyCodeHash {
+			response.Codes = append(response.Codes, []byte{})
+			continue
+		}
+		code, err := tx.GetOne(kv.Code, hash[:])
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			continue
+		}
+		response.Codes = append(response.Codes, common.CopyBytes(code))
+		if size += uint64(len(code)); size >= limit {
+			break
+		}
+	}
+	return response, nil
+}
+
+// AnswerGetTrieNodesQuery returns trie nodes by their paths, nodes which are not found are skipped
+func AnswerGetTrieNodesQuery(tx kv.Tx, req *GetTrieNodesPacket, quit <-chan struct{}) (*TrieNodesPacket, error) {
+	response := &TrieNodesPacket{ID: req.ID}
+	if ok, err := canServe(tx, req.Root); err != nil || !ok {
+		return response, err
+	}
+	limit := responseLimit(req.Bytes)
+
+	type lookup struct {
+		hex     []byte
+		storage bool
+	}
+	var lookups []lookup
+	var hexes [][]byte
+	for _, pathSet := range req.Paths {
+		if len(lookups) >= maxTrieNodeLookups {
+			break
+		}
+		switch len(pathSet) {
+		case 0:
+			continue
+		case 1:
+			hex := trie.CompactToHex(pathSet[0])
+			lookups = append(lookups, lookup{hex: hex})
+			hexes = append(hexes, hex)
+		default:
+			addrHash := common.BytesToHash(pathSet[0])
+			acc, err := readAccount(tx, addrHash)
+			if err != nil {
+				return nil, err
+			}
+			if (acc == nil) || (acc.Incarnation == 0) {
+				continue
+			}
+			storagePrefix := keyToHex(dbutils.GenerateStoragePrefix(addrHash[:], acc.Incarnation))
+			for _, path := range pathSet[1:] {
+				hex := trie.CompactToHex(path)
+				lookups = append(lookups, lookup{hex: append(keyToHex(addrHash[:]), hex...), storage: true})
+				hexes = append(hexes, append(common.CopyBytes(storagePrefix), hex...))
+			}
+		}
+	}
+	if len(lookups) > maxTrieNodeLookups {
+		lookups = lookups[:maxTrieNodeLookups]
+	}
+	if len(lookups) == 0 {
+		return response, nil
+	}
+
+	tr, err := proofTrie(tx, req.Root, hexes, quit)
+	if err != nil {
+		return nil, err
+	}
+	size := uint64(0)
+	for _, l := range lookups {
+		node, ok, err := tr.NodeAt(l.hex, l.storage)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		response.Nodes = append(response.Nodes, node)
+		if size += uint64(len(node)); size >= limit {
+			break
+		}
+	}
+	return response, nil
+}
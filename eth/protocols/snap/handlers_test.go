@@ -0,0 +1,213 @@
+package snap
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testState struct {
+	root         common.Hash
+	accounts     []common.Hash // sorted
+	contract     common.Hash
+	contractCode []byte
+	slots        []common.Hash // sorted
+}
+
+func hashOf(i int) common.Hash {
+	return common.BytesToHash(crypto.Keccak256(big.NewInt(int64(i)).Bytes()))
+}
+
+func makeTestState(t *testing.T, tx kv.RwTx) *testState {
+	state := &testState{contractCode: []byte{0x60, 0x00, 0x60, 0x00, 0xf3}}
+	for i := 0; i < 100; i++ {
+		addrHash := hashOf(i)
+		acc := accounts.NewAccount()
+		acc.Nonce = uint64(i)
+		acc.Balance.SetUint64(uint64(i) * 1000)
+		acc.Initialised = true
+		if i == 42 {
+			acc.Incarnation = 1
+			acc.CodeHash = common.BytesToHash(crypto.Keccak256(state.contractCode))
+			require.Nil(t, tx.Put(kv.Code, acc.CodeHash[:], state.contractCode))
+			state.contract = addrHash
+		}
+		enc := make([]byte, acc.EncodingLengthForStorage())
+		acc.EncodeForStorage(enc)
+		require.Nil(t, tx.Put(kv.HashedAccounts, addrHash[:], enc))
+		state.accounts = append(state.accounts, addrHash)
+	}
+	prefix := dbutils.GenerateStoragePrefix(state.contract[:], 1)
+	for i := 0; i < 50; i++ {
+		slot := hashOf(1000 + i)
+		require.Nil(t, tx.Put(kv.HashedStorage, append(common.CopyBytes(prefix), slot[:]...), []byte{byte(i + 1)}))
+		state.slots = append(state.slots, slot)
+	}
+	sortHashes(state.accounts)
+	sortHashes(state.slots)
+
+	root, err := trie.CalcRoot("test", tx)
+	require.Nil(t, err)
+	state.root = root
+	header := &types.Header{Number: big.NewInt(0), Root: root, Difficulty: big.NewInt(1)}
+	rawdb.WriteHeader(tx, header)
+	require.Nil(t, rawdb.WriteCanonicalHash(tx, header.Hash(), 0))
+	return state
+}
+
+func sortHashes(hashes []common.Hash) {
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+}
+
+// verifyProof - checks that proof starts from root and every node is referenced by previous one
+func verifyProof(t *testing.T, root common.Hash, proof [][]byte) {
+	t.Helper()
+	require.NotEmpty(t, proof)
+	assert.Equal(t, root[:], crypto.Keccak256(proof[0]))
+	for _, node := range proof[1:] {
+		referenced := false
+		for _, parent := range proof {
+			if bytes.Contains(parent, crypto.Keccak256(node)) || (len(node) < common.HashLength && bytes.Contains(parent, node)) {
+				referenced = true
+			}
+		}
+		assert.True(t, referenced, "proof node %x is not referenced", node)
+	}
+}
+
+func TestAnswerGetAccountRangeQuery(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	state := makeTestState(t, tx)
+
+	response, err := AnswerGetAccountRangeQuery(tx, &GetAccountRangePacket{ID: 1, Root: state.root, Limit: maxHash, Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(1), response.ID)
+	require.Equal(t, len(state.accounts), len(response.Accounts))
+	for i, acc := range response.Accounts {
+		assert.Equal(t, state.accounts[i], acc.Hash)
+	}
+	verifyProof(t, state.root, response.Proof)
+
+	var contract SlimAccount
+	for _, acc := range response.Accounts {
+		if acc.Hash == state.contract {
+			require.Nil(t, rlp.DecodeBytes(acc.Body, &contract))
+		}
+	}
+	assert.Equal(t, crypto.Keccak256(state.contractCode), contract.CodeHash)
+	assert.NotEmpty(t, contract.Root)
+
+	// a range in the middle is limited by the limit hash
+	response, err = AnswerGetAccountRangeQuery(tx, &GetAccountRangePacket{ID: 2, Root: state.root, Origin: state.accounts[10], Limit: state.accounts[19], Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	require.Equal(t, 10, len(response.Accounts))
+	assert.Equal(t, state.accounts[10], response.Accounts[0].Hash)
+	verifyProof(t, state.root, response.Proof)
+
+	// ... and by the response size
+	response, err = AnswerGetAccountRangeQuery(tx, &GetAccountRangePacket{ID: 3, Root: state.root, Limit: maxHash, Bytes: 1}, nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(response.Accounts))
+
+	// unknown state root
+	response, err = AnswerGetAccountRangeQuery(tx, &GetAccountRangePacket{ID: 4, Root: common.Hash{1}, Limit: maxHash, Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(4), response.ID)
+	assert.Empty(t, response.Accounts)
+	assert.Empty(t, response.Proof)
+}
+
+func TestAnswerGetStorageRangesQuery(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	state := makeTestState(t, tx)
+
+	// the whole storage doesn't need proofs
+	response, err := AnswerGetStorageRangesQuery(tx, &GetStorageRangesPacket{ID: 1, Root: state.root, Accounts: []common.Hash{state.contract}, Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(response.Slots))
+	require.Equal(t, len(state.slots), len(response.Slots[0]))
+	assert.Equal(t, state.slots[0], response.Slots[0][0].Hash)
+	assert.Empty(t, response.Proof)
+
+	// a partial range is proven against the storage root
+	response, err = AnswerGetStorageRangesQuery(tx, &GetStorageRangesPacket{ID: 2, Root: state.root, Accounts: []common.Hash{state.contract}, Origin: state.slots[5][:], Limit: state.slots[9][:], Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(response.Slots))
+	require.Equal(t, 5, len(response.Slots[0]))
+	assert.Equal(t, state.slots[5], response.Slots[0][0].Hash)
+
+	accounts, err := AnswerGetAccountRangeQuery(tx, &GetAccountRangePacket{Root: state.root, Origin: state.contract, Limit: state.contract, Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	var contract SlimAccount
+	require.Nil(t, rlp.DecodeBytes(accounts.Accounts[0].Body, &contract))
+	verifyProof(t, common.BytesToHash(contract.Root), response.Proof)
+
+	var value []byte
+	require.Nil(t, rlp.DecodeBytes(response.Slots[0][0].Body, &value))
+	assert.Equal(t, 1, len(value))
+}
+
+func TestAnswerGetByteCodesQuery(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	state := makeTestState(t, tx)
+
+	codeHash := common.BytesToHash(crypto.Keccak256(state.contractCode))
+	response, err := AnswerGetByteCodesQuery(tx, &GetByteCodesPacket{ID: 1, Hashes: []common.Hash{{1}, codeHash}, Bytes: softResponseLimit})
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{state.contractCode}, response.Codes)
+}
+
+func TestAnswerGetTrieNodesQuery(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	state := makeTestState(t, tx)
+
+	accounts, err := AnswerGetAccountRangeQuery(tx, &GetAccountRangePacket{Root: state.root, Origin: state.contract, Limit: state.contract, Bytes: softResponseLimit}, nil)
+	require.Nil(t, err)
+	var contract SlimAccount
+	require.Nil(t, rlp.DecodeBytes(accounts.Accounts[0].Body, &contract))
+
+	rootPath := []byte{0} // empty path in compact encoding
+	response, err := AnswerGetTrieNodesQuery(tx, &GetTrieNodesPacket{
+		ID:   1,
+		Root: state.root,
+		Paths: []TrieNodePathSet{
+			{rootPath},
+			{{0x1f}}, // first nibble is f
+			{state.contract[:], rootPath},
+		},
+		Bytes: softResponseLimit,
+	}, nil)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(response.Nodes))
+	assert.Equal(t, state.root[:], crypto.Keccak256(response.Nodes[0]))
+	assert.True(t, bytes.Contains(response.Nodes[0], crypto.Keccak256(response.Nodes[1])))
+	assert.Equal(t, contract.Root, crypto.Keccak256(response.Nodes[2]))
+}
+
+func TestStateRoot(t *testing.T) {
+	db, tx := memdb.NewTestTx(t)
+	state := makeTestState(t, tx)
+	require.Nil(t, tx.Commit())
+
+	require.Nil(t, db.View(context.Background(), func(tx kv.Tx) error {
+		root, err := StateRoot(tx)
+		require.Nil(t, err)
+		assert.Equal(t, state.root, root)
+		return nil
+	}))
+}
@@ -0,0 +1,104 @@
+package stagedsync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// CustomStageFactory builds a stage of downstream project (see cmd/erigoncustom).
+// It may return nil to not add the stage (for example if it's disabled by flag).
+// Forward/Unwind/Prune functions of the stage may get nil tx (when the sync cycle doesn't run in one transaction),
+// in this case they have to open own transaction on db - the same way as default stages do.
+type CustomStageFactory func(ctx context.Context, db kv.RwDB, tmpdir string) *Stage
+
+type customStage struct {
+	after stages.SyncStage
+	id    stages.SyncStage
+	build CustomStageFactory
+}
+
+var customStages []customStage
+
+// RegisterCustomStage - adds stage `id` to the staged sync pipeline right after stage `after`
+// (for example stages.Execution or stages.LogIndex, or another custom stage).
+// Custom stage is unwound and pruned right before `after`, several stages registered after the same stage
+// go forward in the order of registration and unwind in reverse order.
+// Progress of the stage is stored as for default stages (see StageState.Update) and is reset by `integration state_stages`.
+// Must be called before the node is created, for example from init().
+func RegisterCustomStage(after, id stages.SyncStage, build CustomStageFactory) {
+	for _, s := range stages.AllStages {
+		if s == id {
+			panic(fmt.Sprintf("stage %s is already registered", id))
+		}
+	}
+	customStages = append(customStages, customStage{after: after, id: id, build: build})
+	stages.AllStages = append(stages.AllStages, id)
+}
+
+// WithCustomStages - inserts stages registered by RegisterCustomStage into stages list and unwind/prune orders
+func WithCustomStages(ctx context.Context, db kv.RwDB, tmpdir string, stagesList []*Stage, unwindOrder UnwindOrder, pruneOrder PruneOrder) ([]*Stage, UnwindOrder, PruneOrder) {
+	if len(customStages) == 0 {
+		return stagesList, unwindOrder, pruneOrder
+	}
+	built := map[stages.SyncStage]*Stage{}
+	for _, c := range customStages {
+		if s := c.build(ctx, db, tmpdir); s != nil {
+			s.ID = c.id
+			built[c.id] = s
+		}
+	}
+	// stages which go right after given one
+	next := map[stages.SyncStage][]stages.SyncStage{}
+	for _, c := range customStages {
+		if _, ok := built[c.id]; ok {
+			next[c.after] = append(next[c.after], c.id)
+		}
+	}
+
+	var resStages []*Stage
+	added := map[stages.SyncStage]bool{}
+	var forward func(id stages.SyncStage)
+	forward = func(id stages.SyncStage) {
+		for _, nextID := range next[id] {
+			resStages = append(resStages, built[nextID])
+			added[nextID] = true
+			forward(nextID)
+		}
+	}
+	for _, s := range stagesList {
+		resStages = append(resStages, s)
+		forward(s.ID)
+	}
+	for id := range built {
+		if !added[id] {
+			log.Warn("Custom stage is not added: stage to run after is not found", "stage", id)
+		}
+	}
+
+	var backward func(id stages.SyncStage, order []stages.SyncStage) []stages.SyncStage
+	backward = func(id stages.SyncStage, order []stages.SyncStage) []stages.SyncStage {
+		for i := len(next[id]) - 1; i >= 0; i-- {
+			if !added[next[id][i]] {
+				continue
+			}
+			order = backward(next[id][i], order)
+			order = append(order, next[id][i])
+		}
+		return order
+	}
+	var resUnwind UnwindOrder
+	for _, id := range unwindOrder {
+		resUnwind = backward(id, resUnwind)
+		resUnwind = append(resUnwind, id)
+	}
+	var resPrune PruneOrder
+	for _, id := range pruneOrder {
+		resPrune = backward(id, resPrune)
+		resPrune = append(resPrune, id)
+	}
+	return resStages, resUnwind, resPrune
+}
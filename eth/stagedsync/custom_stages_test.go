@@ -0,0 +1,86 @@
+package stagedsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomStages(t *testing.T) {
+	defer func(registered []customStage, all []stages.SyncStage) {
+		customStages, stages.AllStages = registered, all
+	}(customStages, stages.AllStages)
+
+	const transfers, swaps, disabled, other stages.SyncStage = "test.Transfers", "test.Swaps", "test.Disabled", "test.Other"
+	flow := make([]stages.SyncStage, 0)
+	unwound := false
+	newStage := func(id stages.SyncStage) *Stage {
+		return &Stage{
+			ID: id,
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx) error {
+				flow = append(flow, id)
+				if s.BlockNumber == 0 {
+					if err := s.Update(tx, 100); err != nil {
+						return err
+					}
+				}
+				if id == stages.Finish && !unwound {
+					unwound = true
+					u.UnwindTo(50, common.Hash{})
+				}
+				return nil
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx) error {
+				flow = append(flow, unwindOf(id))
+				return u.Done(tx)
+			},
+		}
+	}
+	custom := func(id stages.SyncStage) CustomStageFactory {
+		return func(ctx context.Context, db kv.RwDB, tmpdir string) *Stage { return newStage(id) }
+	}
+	RegisterCustomStage(stages.Execution, transfers, custom(transfers))
+	RegisterCustomStage(transfers, swaps, custom(swaps))
+	RegisterCustomStage(stages.Execution, disabled, func(ctx context.Context, db kv.RwDB, tmpdir string) *Stage { return nil })
+	RegisterCustomStage(stages.Execution, other, custom(other))
+	require.Panics(t, func() { RegisterCustomStage(stages.Execution, other, custom(other)) })
+	require.Contains(t, stages.AllStages, transfers)
+
+	s, unwindOrder, pruneOrder := WithCustomStages(context.Background(), nil, "",
+		[]*Stage{newStage(stages.Headers), newStage(stages.Execution), newStage(stages.Finish)},
+		UnwindOrder{stages.Finish, stages.Execution, stages.Headers},
+		PruneOrder{stages.Finish, stages.Execution, stages.Headers},
+	)
+	require.Equal(t, UnwindOrder{stages.Finish, other, swaps, transfers, stages.Execution, stages.Headers}, unwindOrder)
+	require.Equal(t, PruneOrder{stages.Finish, other, swaps, transfers, stages.Execution, stages.Headers}, pruneOrder)
+
+	state := New(s, unwindOrder, pruneOrder)
+	db, tx := memdb.NewTestTx(t)
+	err := state.Run(db, tx, true)
+	assert.NoError(t, err)
+
+	expectedFlow := []stages.SyncStage{
+		stages.Headers, stages.Execution, transfers, swaps, other, stages.Finish,
+		unwindOf(stages.Finish), unwindOf(other), unwindOf(swaps), unwindOf(transfers), unwindOf(stages.Execution), unwindOf(stages.Headers),
+		stages.Headers, stages.Execution, transfers, swaps, other, stages.Finish,
+	}
+	assert.Equal(t, expectedFlow, flow)
+
+	progress, err := stages.GetStageProgress(tx, swaps)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, int(progress))
+
+	status, err := stages.ReadSyncStatus(tx, state.Status())
+	require.NoError(t, err)
+	reported := make([]stages.SyncStage, 0, len(status.Stages))
+	for _, st := range status.Stages {
+		reported = append(reported, st.Stage)
+	}
+	assert.Equal(t, []stages.SyncStage{stages.Headers, stages.Execution, transfers, swaps, other, stages.Finish}, reported)
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/ledgerwatch/erigon/ethdb/prune"
 )
 
-func DefaultStages(ctx context.Context, sm prune.Mode, headers HeadersCfg, cumulativeIndex CumulativeIndexCfg, blockHashCfg BlockHashesCfg, bodies BodiesCfg, issuance IssuanceCfg, senders SendersCfg, exec ExecuteBlockCfg, trans TranspileCfg, hashState HashStateCfg, trieCfg TrieCfg, history HistoryCfg, logIndex LogIndexCfg, callTraces CallTracesCfg, txLookup TxLookupCfg, finish FinishCfg, test bool) []*Stage {
+func DefaultStages(ctx context.Context, sm prune.Mode, headers HeadersCfg, cumulativeIndex CumulativeIndexCfg, blockHashCfg BlockHashesCfg, bodies BodiesCfg, issuance IssuanceCfg, senders SendersCfg, exec ExecuteBlockCfg, trans TranspileCfg, hashState HashStateCfg, trieCfg TrieCfg, history HistoryCfg, logIndex LogIndexCfg, callTraces CallTracesCfg, traceIndex TraceIndexCfg, addressAppearances AddressAppearancesCfg, txLookup TxLookupCfg, finish FinishCfg, test bool) []*Stage {
 	return []*Stage{
 		{
 			ID:          stages.Headers,
@@ -186,6 +186,36 @@ func DefaultStages(ctx context.Context, sm prune.Mode, headers HeadersCfg, cumul
 				return PruneLogIndex(p, tx, logIndex, ctx)
 			},
 		},
+		{
+			ID:                  stages.TraceIndex,
+			Description:         "Store Parity-style traces",
+			Disabled:            !sm.Experiments.Traces,
+			DisabledDescription: "Enable by adding `traces` to --experiments",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx) error {
+				return SpawnTraceIndex(s, tx, traceIndex, ctx)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx) error {
+				return UnwindTraceIndex(u, s, tx, traceIndex, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx) error {
+				return PruneTraceIndex(p, tx, traceIndex, ctx)
+			},
+		},
+		{
+			ID:                  stages.AddressAppearances,
+			Description:         "Index transactions by addresses",
+			Disabled:            !sm.Experiments.Appearances,
+			DisabledDescription: "Enable by adding `appearances` to --experiments",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx) error {
+				return SpawnAddressAppearances(s, tx, addressAppearances, ctx)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx) error {
+				return UnwindAddressAppearances(u, s, tx, addressAppearances, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx) error {
+				return PruneAddressAppearances(p, tx, addressAppearances, ctx)
+			},
+		},
 		{
 			ID:          stages.TxLookup,
 			Description: "Generate tx lookup index",
@@ -243,6 +273,8 @@ var DefaultForwardOrder = UnwindOrder{
 	stages.AccountHistoryIndex,
 	stages.StorageHistoryIndex,
 	stages.LogIndex,
+	stages.TraceIndex,
+	stages.AddressAppearances,
 	stages.TxLookup,
 	stages.Finish,
 }
@@ -257,6 +289,8 @@ type PruneOrder []stages.SyncStage
 var DefaultUnwindOrder = UnwindOrder{
 	stages.Finish,
 	stages.TxLookup,
+	stages.AddressAppearances,
+	stages.TraceIndex,
 	stages.LogIndex,
 	stages.StorageHistoryIndex,
 	stages.AccountHistoryIndex,
@@ -278,6 +312,8 @@ var DefaultUnwindOrder = UnwindOrder{
 var DefaultPruneOrder = PruneOrder{
 	stages.Finish,
 	stages.TxLookup,
+	stages.AddressAppearances,
+	stages.TraceIndex,
 	stages.LogIndex,
 	stages.StorageHistoryIndex,
 	stages.AccountHistoryIndex,
@@ -0,0 +1,104 @@
+package stagedsync
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/interfaces"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/appearances"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+type AddressAppearancesCfg struct {
+	replayCfg
+}
+
+func StageAddressAppearancesCfg(
+	db kv.RwDB,
+	prune prune.Mode,
+	chainConfig *params.ChainConfig,
+	engine consensus.Engine,
+	blockReader interfaces.FullBlockReader,
+) AddressAppearancesCfg {
+	return AddressAppearancesCfg{replayCfg{
+		db:          db,
+		prune:       prune,
+		chainConfig: chainConfig,
+		engine:      engine,
+		blockReader: blockReader,
+	}}
+}
+
+// SpawnAddressAppearances - re-executes blocks on top of historical state and indexes their transactions by addresses appearing in them.
+// Must run after history index stages.
+func SpawnAddressAppearances(s *StageState, tx kv.RwTx, cfg AddressAppearancesCfg, ctx context.Context) error {
+	return spawnReplayStage(s, tx, cfg.replayCfg, ctx, "Indexing address appearances", func(tx kv.RwTx, blockNum uint64) error {
+		return indexBlockAppearances(ctx, tx, cfg, blockNum)
+	})
+}
+
+func indexBlockAppearances(ctx context.Context, tx kv.RwTx, cfg AddressAppearancesCfg, blockNum uint64) error {
+	tracer := appearances.NewTracer()
+	return replayBlock(ctx, tx, cfg.replayCfg, blockNum, tracer, func(txIndex int, txn types.Transaction, ibs *state.IntraBlockState) error {
+		defer tracer.Reset()
+		tracer.CaptureLogs(ibs.GetLogs(txn.Hash()))
+		return appearances.WriteTxAppearances(tx, blockNum, txIndex, tracer.Addresses())
+	})
+}
+
+func UnwindAddressAppearances(u *UnwindState, s *StageState, tx kv.RwTx, cfg AddressAppearancesCfg, ctx context.Context) (err error) {
+	if s.BlockNumber <= u.UnwindPoint {
+		return nil
+	}
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if err = appearances.TruncateAppearances(tx, u.UnwindPoint+1); err != nil {
+		return err
+	}
+	if err = u.Done(tx); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PruneAddressAppearances(s *PruneState, tx kv.RwTx, cfg AddressAppearancesCfg, ctx context.Context) (err error) {
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if cfg.prune.CallTraces.Enabled() {
+		if err = appearances.PruneAppearances(tx, cfg.prune.CallTraces.PruneTo(s.ForwardProgress)); err != nil {
+			return err
+		}
+	}
+	if err = s.Done(tx); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
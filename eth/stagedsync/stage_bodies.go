@@ -184,6 +184,11 @@ Loop:
 				u.UnwindTo(blockHeight-1, header.Hash())
 				break Loop
 			}
+			if err = bodydownload.VerifyWithdrawals(header, rawBody.Withdrawals); err != nil {
+				log.Error(fmt.Sprintf("[%s] Withdrawals verification failed", logPrefix), "number", blockHeight, "hash", header.Hash().String(), "err", err)
+				u.UnwindTo(blockHeight-1, header.Hash())
+				break Loop
+			}
 
 			// Check existence before write - because WriteRawBody isn't idempotent (it allocates new sequence range for transactions on every call)
 			if err = rawdb.WriteRawBodyIfNotExists(tx, header.Hash(), blockHeight, rawBody); err != nil {
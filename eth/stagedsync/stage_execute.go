@@ -21,6 +21,7 @@ import (
 	"github.com/ledgerwatch/erigon/common/dbutils"
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/consensus/bor"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
@@ -120,11 +121,17 @@ func executeBlock(
 
 	var receipts types.Receipts
 	var stateSyncReceipt *types.ReceiptForStorage
+	engine := cfg.engine
+	var stateSyncRecorder *bor.StateSyncRecorder // state-sync events committed by the block are returned by its finalization
+	if borEngine, ok := engine.(*bor.Bor); ok {
+		stateSyncRecorder = bor.NewStateSyncRecorder(borEngine)
+		engine = stateSyncRecorder
+	}
 	_, isPoSa := cfg.engine.(consensus.PoSA)
 	if isPoSa {
 		receipts, err = core.ExecuteBlockEphemerallyForBSC(cfg.chainConfig, &vmConfig, getHeader, cfg.engine, block, stateReader, stateWriter, epochReader{tx: tx}, chainReader{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, contractHasTEVM)
 	} else {
-		receipts, stateSyncReceipt, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHeader, cfg.engine, block, stateReader, stateWriter, epochReader{tx: tx}, chainReader{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, contractHasTEVM)
+		receipts, stateSyncReceipt, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHeader, engine, block, stateReader, stateWriter, epochReader{tx: tx}, chainReader{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, contractHasTEVM)
 	}
 	if err != nil {
 		return err
@@ -139,6 +146,14 @@ func executeBlock(
 			if err := rawdb.WriteBorReceipt(tx, block.Hash(), block.NumberU64(), stateSyncReceipt); err != nil {
 				return err
 			}
+			if err := rawdb.WriteBorTxLookupEntry(tx, block.Hash(), block.NumberU64()); err != nil {
+				return err
+			}
+			if stateSyncRecorder != nil {
+				if err := bor.WriteStateSyncEvents(tx, block.NumberU64(), stateSyncRecorder.Committed()); err != nil {
+					return err
+				}
+			}
 		}
 
 	}
@@ -334,7 +349,7 @@ Loop:
 			if estimateRatio != 0 {
 				estimatedTime = commonold.PrettyDuration((elapsed.Seconds() / estimateRatio) * float64(time.Second))
 			}
-			logBlock, logTx, logTime = logProgress(logPrefix, logBlock, logTime, blockNum, logTx, lastLogTx, gas, float64(currentStateGas)/float64(gasState), estimatedTime, batch)
+			logBlock, logTx, logTime = logProgress(logPrefix, s.state.Status(), logBlock, logTime, blockNum, logTx, lastLogTx, gas, float64(currentStateGas)/float64(gasState), estimatedTime, batch)
 			gas = 0
 			tx.CollectMetrics()
 			syncMetrics[stages.Execution].Set(blockNum)
@@ -354,16 +369,36 @@ Loop:
 		}
 	}
 
+	if logBlock == s.BlockNumber && stageProgress > logBlock { // cycle was shorter than log interval
+		reportThroughput(s.state.Status(), logBlock, logTime, stageProgress, logTx, lastLogTx, gas)
+	}
 	log.Info(fmt.Sprintf("[%s] Completed on", logPrefix), "block", stageProgress)
 	return stoppedErr
 }
 
-func logProgress(logPrefix string, prevBlock uint64, prevTime time.Time, currentBlock uint64, prevTx, currentTx uint64, gas uint64, gasState float64, estimatedTime commonold.PrettyDuration, batch ethdb.DbWithPendingMutations) (uint64, uint64, time.Time) {
+// reportThroughput - updates Execution speed visible by erigon_syncStatus
+func reportThroughput(status *stages.StatusTracker, prevBlock uint64, prevTime time.Time, currentBlock uint64, prevTx, currentTx uint64, gas uint64) {
+	currentTime := time.Now()
+	seconds := currentTime.Sub(prevTime).Seconds()
+	if seconds <= 0 {
+		return
+	}
+	status.SetThroughput(stages.Throughput{
+		Block:           currentBlock,
+		BlocksPerSecond: float64(currentBlock-prevBlock) / seconds,
+		TxsPerSecond:    float64(currentTx-prevTx) / seconds,
+		GasPerSecond:    float64(gas) / seconds,
+		MeasuredAt:      currentTime,
+	})
+}
+
+func logProgress(logPrefix string, status *stages.StatusTracker, prevBlock uint64, prevTime time.Time, currentBlock uint64, prevTx, currentTx uint64, gas uint64, gasState float64, estimatedTime commonold.PrettyDuration, batch ethdb.DbWithPendingMutations) (uint64, uint64, time.Time) {
 	currentTime := time.Now()
 	interval := currentTime.Sub(prevTime)
 	speed := float64(currentBlock-prevBlock) / (float64(interval) / float64(time.Second))
 	speedTx := float64(currentTx-prevTx) / (float64(interval) / float64(time.Second))
 	speedMgas := float64(gas) / 1_000_000 / (float64(interval) / float64(time.Second))
+	reportThroughput(status, prevBlock, prevTime, currentBlock, prevTx, currentTx, gas)
 
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
@@ -526,6 +561,9 @@ func unwindExecutionStage(u *UnwindState, s *StageState, tx kv.RwTx, quit <-chan
 	if err := rawdb.TruncateBorReceipts(tx, u.UnwindPoint+1); err != nil {
 		return fmt.Errorf("truncate bor receipts: %w", err)
 	}
+	if err := bor.UnwindStateSyncEvents(tx, u.UnwindPoint+1); err != nil {
+		return fmt.Errorf("unwind state sync events: %w", err)
+	}
 	if err := rawdb.DeleteNewerEpochs(tx, u.UnwindPoint+1); err != nil {
 		return fmt.Errorf("delete newer epochs: %w", err)
 	}
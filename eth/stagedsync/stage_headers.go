@@ -232,6 +232,14 @@ func safeAndFinalizedBlocksAreCanonical(
 	return true, nil
 }
 
+// writeForkChoiceHashes persists safe and finalized blocks of the fork choice, they are served as "safe" and "finalized" block tags by RPC
+func writeForkChoiceHashes(forkChoice *engineapi.ForkChoiceMessage, tx kv.RwTx) error {
+	if err := rawdb.WriteForkchoiceSafe(tx, forkChoice.SafeBlockHash); err != nil {
+		return err
+	}
+	return rawdb.WriteForkchoiceFinalized(tx, forkChoice.FinalizedBlockHash)
+}
+
 func startHandlingForkChoice(
 	forkChoice *engineapi.ForkChoiceMessage,
 	requestStatus engineapi.RequestStatus,
@@ -258,6 +266,11 @@ func startHandlingForkChoice(
 			}
 			return err
 		}
+		if canonical {
+			if err = writeForkChoiceHashes(forkChoice, tx); err != nil {
+				return err
+			}
+		}
 		if canonical && requestStatus == engineapi.New {
 			cfg.hd.PayloadStatusCh <- privateapi.PayloadStatus{
 				Status:          remote.EngineStatus_VALID,
@@ -363,7 +376,11 @@ func finishHandlingForkChoice(
 	if err != nil {
 		return err
 	}
-	if !canonical {
+	if canonical {
+		if err = writeForkChoiceHashes(forkChoice, tx); err != nil {
+			return err
+		}
+	} else {
 		cfg.hd.ClearPendingPayloadStatus()
 	}
 
@@ -489,7 +506,7 @@ func handleNewPayload(
 	}
 
 	if cfg.bodyDownload != nil {
-		block := types.NewBlockFromStorage(headerHash, header, transactions, nil)
+		block := types.NewBlockFromStorage(headerHash, header, transactions, nil, payloadMessage.Body.Withdrawals)
 		cfg.bodyDownload.AddToPrefetch(block)
 	}
 
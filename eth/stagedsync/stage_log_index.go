@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"runtime"
 	"sort"
 	"time"
@@ -12,13 +13,16 @@ import (
 	"github.com/RoaringBitmap/roaring"
 	"github.com/c2h5oh/datasize"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/cmp"
 	"github.com/ledgerwatch/erigon-lib/etl"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/interfaces"
 	"github.com/ledgerwatch/erigon/common/dbutils"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/ethdb/bitmapdb"
 	"github.com/ledgerwatch/erigon/ethdb/cbor"
 	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -28,23 +32,73 @@ const (
 )
 
 type LogIndexCfg struct {
-	tmpdir     string
-	db         kv.RwDB
-	prune      prune.Mode
-	bufLimit   datasize.ByteSize
-	flushEvery time.Duration
+	tmpdir      string
+	db          kv.RwDB
+	prune       prune.Mode
+	bufLimit    datasize.ByteSize
+	flushEvery  time.Duration
+	snapshots   *snapshotsync.RoSnapshots
+	blockReader interfaces.FullBlockReader
 }
 
-func StageLogIndexCfg(db kv.RwDB, prune prune.Mode, tmpDir string) LogIndexCfg {
+func StageLogIndexCfg(db kv.RwDB, prune prune.Mode, tmpDir string, snapshots *snapshotsync.RoSnapshots, blockReader interfaces.FullBlockReader) LogIndexCfg {
 	return LogIndexCfg{
-		db:         db,
-		prune:      prune,
-		bufLimit:   bitmapsBufLimit,
-		flushEvery: bitmapsFlushEvery,
-		tmpdir:     tmpDir,
+		db:          db,
+		prune:       prune,
+		bufLimit:    bitmapsBufLimit,
+		flushEvery:  bitmapsFlushEvery,
+		tmpdir:      tmpDir,
+		snapshots:   snapshots,
+		blockReader: blockReader,
 	}
 }
 
+// walkLogs - calls walker with logs of each transaction of blocks [from, to).
+// Blocks covered by receipts segments are read from them, because kv.Log is pruned after retire, the rest from kv.Log.
+func walkLogs(ctx context.Context, tx kv.Tx, cfg LogIndexCfg, from, to uint64, walker func(blockNum uint64, logs types.Logs) error) error {
+	if cfg.snapshots != nil && cfg.blockReader != nil {
+		for snapshotsTo := cmp.Min(cfg.snapshots.ReceiptsAvailableTo(from), to); from < snapshotsTo; from++ {
+			receipts, err := cfg.blockReader.RawReceipts(ctx, tx, from)
+			if err != nil {
+				return err
+			}
+			for _, receipt := range receipts {
+				if len(receipt.Logs) == 0 {
+					continue
+				}
+				if err := walker(from, receipt.Logs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	logs, err := tx.Cursor(kv.Log)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+	reader := bytes.NewReader(nil)
+	for k, v, err := logs.Seek(dbutils.EncodeBlockNumber(from)); k != nil; k, v, err = logs.Next() {
+		if err != nil {
+			return err
+		}
+		blockNum := binary.BigEndian.Uint64(k[:8])
+		if blockNum >= to {
+			break
+		}
+		var ll types.Logs
+		reader.Reset(v)
+		if err := cbor.Unmarshal(&ll, reader); err != nil {
+			return fmt.Errorf("receipt unmarshal failed: %w, block=%d", err, blockNum)
+		}
+		if err := walker(blockNum, ll); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func SpawnLogIndex(s *StageState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Context) error {
 	useExternalTx := tx != nil
 	if !useExternalTx {
@@ -97,11 +151,6 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, cfg LogIndexCfg
 
 	topics := map[string]*roaring.Bitmap{}
 	addresses := map[string]*roaring.Bitmap{}
-	logs, err := tx.Cursor(kv.Log)
-	if err != nil {
-		return err
-	}
-	defer logs.Close()
 	checkFlushEvery := time.NewTicker(cfg.flushEvery)
 	defer checkFlushEvery.Stop()
 
@@ -110,17 +159,10 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, cfg LogIndexCfg
 	collectorAddrs := etl.NewCollector(logPrefix, cfg.tmpdir, etl.NewSortableBuffer(etl.BufferOptimalSize))
 	defer collectorAddrs.Close()
 
-	reader := bytes.NewReader(nil)
-
-	for k, v, err := logs.Seek(dbutils.LogKey(start, 0)); k != nil; k, v, err = logs.Next() {
-		if err != nil {
-			return err
-		}
-
+	if err := walkLogs(ctx, tx, cfg, start, math.MaxUint64, func(blockNum uint64, ll types.Logs) error {
 		if err := libcommon.Stopped(quit); err != nil {
 			return err
 		}
-		blockNum := binary.BigEndian.Uint64(k[:8])
 
 		select {
 		default:
@@ -144,12 +186,6 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, cfg LogIndexCfg
 			}
 		}
 
-		var ll types.Logs
-		reader.Reset(v)
-		if err := cbor.Unmarshal(&ll, reader); err != nil {
-			return fmt.Errorf("receipt unmarshal failed: %w, blocl=%d", err, blockNum)
-		}
-
 		for _, l := range ll {
 			for _, topic := range l.Topics {
 				topicStr := string(topic.Bytes())
@@ -169,6 +205,9 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, cfg LogIndexCfg
 			}
 			m.Add(uint32(blockNum))
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	if err := flushBitmaps(collectorTopics, topics); err != nil {
@@ -224,7 +263,6 @@ func promoteLogIndex(logPrefix string, tx kv.RwTx, start uint64, cfg LogIndexCfg
 }
 
 func UnwindLogIndex(u *UnwindState, s *StageState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Context) (err error) {
-	quitCh := ctx.Done()
 	useExternalTx := tx != nil
 	if !useExternalTx {
 		tx, err = cfg.db.BeginRw(ctx)
@@ -235,7 +273,7 @@ func UnwindLogIndex(u *UnwindState, s *StageState, tx kv.RwTx, cfg LogIndexCfg,
 	}
 
 	logPrefix := s.LogPrefix()
-	if err := unwindLogIndex(logPrefix, tx, u.UnwindPoint, cfg, quitCh); err != nil {
+	if err := unwindLogIndex(logPrefix, tx, u.UnwindPoint, cfg, ctx); err != nil {
 		return err
 	}
 
@@ -250,36 +288,23 @@ func UnwindLogIndex(u *UnwindState, s *StageState, tx kv.RwTx, cfg LogIndexCfg,
 	return nil
 }
 
-func unwindLogIndex(logPrefix string, db kv.RwTx, to uint64, cfg LogIndexCfg, quitCh <-chan struct{}) error {
+func unwindLogIndex(logPrefix string, db kv.RwTx, to uint64, cfg LogIndexCfg, ctx context.Context) error {
 	topics := map[string]struct{}{}
 	addrs := map[string]struct{}{}
 
-	reader := bytes.NewReader(nil)
-	c, err := db.Cursor(kv.Log)
-	if err != nil {
-		return err
-	}
-	defer c.Close()
-	for k, v, err := c.Seek(dbutils.EncodeBlockNumber(to + 1)); k != nil; k, v, err = c.Next() {
-		if err != nil {
+	if err := walkLogs(ctx, db, cfg, to+1, math.MaxUint64, func(blockNum uint64, logs types.Logs) error {
+		if err := libcommon.Stopped(ctx.Done()); err != nil {
 			return err
 		}
-
-		if err := libcommon.Stopped(quitCh); err != nil {
-			return err
-		}
-		var logs types.Logs
-		reader.Reset(v)
-		if err := cbor.Unmarshal(&logs, reader); err != nil {
-			return fmt.Errorf("receipt unmarshal: %w, block=%d", err, binary.BigEndian.Uint64(k))
-		}
-
 		for _, l := range logs {
 			for _, topic := range l.Topics {
 				topics[string(topic.Bytes())] = struct{}{}
 			}
 			addrs[string(l.Address.Bytes())] = struct{}{}
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	if err := truncateBitmaps(db, kv.LogTopicIndex, topics, to); err != nil {
@@ -386,7 +411,7 @@ func PruneLogIndex(s *PruneState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Conte
 	}
 
 	pruneTo := cfg.prune.Receipts.PruneTo(s.ForwardProgress)
-	if err = pruneLogIndex(logPrefix, tx, cfg.tmpdir, pruneTo, ctx); err != nil {
+	if err = pruneLogIndex(logPrefix, tx, cfg, pruneTo, ctx); err != nil {
 		return err
 	}
 	if err = s.Done(tx); err != nil {
@@ -401,50 +426,31 @@ func PruneLogIndex(s *PruneState, tx kv.RwTx, cfg LogIndexCfg, ctx context.Conte
 	return nil
 }
 
-func pruneLogIndex(logPrefix string, tx kv.RwTx, tmpDir string, pruneTo uint64, ctx context.Context) error {
+func pruneLogIndex(logPrefix string, tx kv.RwTx, cfg LogIndexCfg, pruneTo uint64, ctx context.Context) error {
 	logEvery := time.NewTicker(logInterval)
 	defer logEvery.Stop()
 
 	topics := map[string]struct{}{}
 	addrs := map[string]struct{}{}
 
-	reader := bytes.NewReader(nil)
-	{
-		c, err := tx.Cursor(kv.Log)
-		if err != nil {
-			return err
+	if err := walkLogs(ctx, tx, cfg, 0, pruneTo, func(blockNum uint64, logs types.Logs) error {
+		select {
+		case <-logEvery.C:
+			log.Info(fmt.Sprintf("[%s]", logPrefix), "table", kv.Log, "block", blockNum)
+		case <-ctx.Done():
+			return libcommon.ErrStopped
+		default:
 		}
-		defer c.Close()
-
-		for k, v, err := c.First(); k != nil; k, v, err = c.Next() {
-			if err != nil {
-				return err
-			}
-			blockNum := binary.BigEndian.Uint64(k)
-			if blockNum >= pruneTo {
-				break
-			}
-			select {
-			case <-logEvery.C:
-				log.Info(fmt.Sprintf("[%s]", logPrefix), "table", kv.Log, "block", blockNum)
-			case <-ctx.Done():
-				return libcommon.ErrStopped
-			default:
-			}
-
-			var logs types.Logs
-			reader.Reset(v)
-			if err := cbor.Unmarshal(&logs, reader); err != nil {
-				return fmt.Errorf("receipt unmarshal failed: %w, block=%d", err, binary.BigEndian.Uint64(k))
-			}
 
-			for _, l := range logs {
-				for _, topic := range l.Topics {
-					topics[string(topic.Bytes())] = struct{}{}
-				}
-				addrs[string(l.Address.Bytes())] = struct{}{}
+		for _, l := range logs {
+			for _, topic := range l.Topics {
+				topics[string(topic.Bytes())] = struct{}{}
 			}
+			addrs[string(l.Address.Bytes())] = struct{}{}
 		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	if err := pruneOldLogChunks(tx, kv.LogTopicIndex, topics, pruneTo, logPrefix, ctx); err != nil {
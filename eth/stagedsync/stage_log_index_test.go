@@ -91,12 +91,12 @@ func genReceipts(t *testing.T, tx kv.RwTx, blocks uint64) (map[common.Address]ui
 }
 
 func TestLogIndex(t *testing.T) {
-	require, tmpDir, ctx := require.New(t), t.TempDir(), context.Background()
+	require, ctx := require.New(t), context.Background()
 	_, tx := memdb.NewTestTx(t)
 
 	expectAddrs, expectTopics := genReceipts(t, tx, 100)
 
-	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "")
+	cfg := StageLogIndexCfg(nil, prune.DefaultMode, "", nil, nil)
 	cfgCopy := cfg
 	cfgCopy.bufLimit = 10
 	cfgCopy.flushEvery = time.Nanosecond
@@ -116,7 +116,7 @@ func TestLogIndex(t *testing.T) {
 	}
 
 	// Mode test
-	err = pruneLogIndex("", tx, tmpDir, 50, ctx)
+	err = pruneLogIndex("", tx, cfg, 50, ctx)
 	require.NoError(err)
 
 	{
@@ -141,7 +141,7 @@ func TestLogIndex(t *testing.T) {
 	}
 
 	// Unwind test
-	err = unwindLogIndex("logPrefix", tx, 70, cfg, nil)
+	err = unwindLogIndex("logPrefix", tx, 70, cfg, ctx)
 	require.NoError(err)
 
 	for addr := range expectAddrs {
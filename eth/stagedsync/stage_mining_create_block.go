@@ -27,10 +27,11 @@ import (
 )
 
 type MiningBlock struct {
-	Header   *types.Header
-	Uncles   []*types.Header
-	Txs      types.Transactions
-	Receipts types.Receipts
+	Header      *types.Header
+	Uncles      []*types.Header
+	Txs         types.Transactions
+	Receipts    types.Receipts
+	Withdrawals []*types.Withdrawal
 
 	LocalTxs  types.TransactionsStream
 	RemoteTxs types.TransactionsStream
@@ -221,6 +222,14 @@ func SpawnMiningCreateBlockStage(s *StageState, tx kv.RwTx, cfg MiningCreateBloc
 
 		current.Header = header
 		current.Uncles = nil
+		current.Withdrawals = cfg.blockProposerParameters.Withdrawals
+		if cfg.chainConfig.IsShanghai(header.Time) {
+			if current.Withdrawals == nil {
+				current.Withdrawals = []*types.Withdrawal{}
+			}
+			withdrawalsHash := types.DeriveSha(types.Withdrawals(current.Withdrawals))
+			header.WithdrawalsHash = &withdrawalsHash
+		}
 		return nil
 	}
 
@@ -311,6 +320,7 @@ func SpawnMiningCreateBlockStage(s *StageState, tx kv.RwTx, cfg MiningCreateBloc
 
 	current.Header = header
 	current.Uncles = makeUncles(env.uncles)
+	current.Withdrawals = nil
 	return nil
 }
 
@@ -44,7 +44,7 @@ func SpawnMiningFinishStage(s *StageState, tx kv.RwTx, cfg MiningFinishCfg, quit
 	//	continue
 	//}
 
-	block := types.NewBlock(current.Header, current.Txs, current.Uncles, current.Receipts)
+	block := types.NewBlockWithWithdrawals(current.Header, current.Txs, current.Uncles, current.Receipts, current.Withdrawals)
 	*current = MiningBlock{} // hack to clean global data
 
 	//sealHash := engine.SealHash(block.Header())
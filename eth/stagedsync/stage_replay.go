@@ -0,0 +1,154 @@
+package stagedsync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/interfaces"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/ethdb"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// replayCfg - config of the stages re-executing blocks on top of historical state (TraceIndex, AddressAppearances)
+type replayCfg struct {
+	db          kv.RwDB
+	prune       prune.Mode
+	chainConfig *params.ChainConfig
+	engine      consensus.Engine
+	blockReader interfaces.FullBlockReader
+}
+
+// spawnReplayStage re-executes blocks from the stage progress up to the executed block, indexing every block with replay.
+// Blocks without historical state (pruned history) are skipped.
+func spawnReplayStage(s *StageState, tx kv.RwTx, cfg replayCfg, ctx context.Context, what string, replay func(tx kv.RwTx, blockNum uint64) error) error {
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		var err error
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	endBlock, err := s.ExecutionAt(tx)
+	if err != nil {
+		return fmt.Errorf("getting last executed block: %w", err)
+	}
+	if endBlock <= s.BlockNumber {
+		return nil
+	}
+	logPrefix := s.LogPrefix()
+	startBlock := s.BlockNumber + 1
+	// Historical state is not available for pruned blocks
+	if cfg.prune.History.Enabled() {
+		if pruneTo := cfg.prune.History.PruneTo(endBlock); startBlock < pruneTo {
+			startBlock = pruneTo
+		}
+	}
+	if endBlock-startBlock > 16 {
+		log.Info(fmt.Sprintf("[%s] %s", logPrefix, what), "from", startBlock, "to", endBlock)
+	}
+
+	logEvery := time.NewTicker(logInterval)
+	defer logEvery.Stop()
+	for blockNum := startBlock; blockNum <= endBlock; blockNum++ {
+		if err = libcommon.Stopped(ctx.Done()); err != nil {
+			return err
+		}
+		if err = replay(tx, blockNum); err != nil {
+			return err
+		}
+		select {
+		default:
+		case <-logEvery.C:
+			log.Info(fmt.Sprintf("[%s] Progress", logPrefix), "number", blockNum)
+		}
+	}
+
+	if err = s.Update(tx, endBlock); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayBlock re-executes transactions of the canonical block on top of historical state with the given tracer,
+// calling onTx after every transaction. System transactions of PoSA engines are not replayed.
+func replayBlock(ctx context.Context, tx kv.RwTx, cfg replayCfg, blockNum uint64,
+	tracer vm.Tracer, onTx func(txIndex int, txn types.Transaction, ibs *state.IntraBlockState) error) error {
+	chainConfig, engine, blockReader := cfg.chainConfig, cfg.engine, cfg.blockReader
+	blockHash, err := rawdb.ReadCanonicalHash(tx, blockNum)
+	if err != nil {
+		return err
+	}
+	block, _, err := blockReader.BlockWithSenders(ctx, tx, blockHash, blockNum)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return fmt.Errorf("block %d not found", blockNum)
+	}
+	if len(block.Transactions()) == 0 {
+		return nil
+	}
+
+	getHeader := func(hash common.Hash, number uint64) *types.Header {
+		h, _ := blockReader.Header(ctx, tx, hash, number)
+		return h
+	}
+	var contractHasTEVM func(contractHash common.Hash) (bool, error)
+	if cfg.prune.Experiments.TEVM {
+		contractHasTEVM = ethdb.GetHasTEVM(tx)
+	}
+
+	header := block.Header()
+	ibs := state.New(state.NewPlainState(tx, blockNum))
+	if err = core.InitializeBlockExecution(engine, chainReader{config: chainConfig, tx: tx, blockReader: blockReader}, epochReader{tx: tx}, header, block.Transactions(), block.Uncles(), chainConfig, ibs); err != nil {
+		return err
+	}
+	if chainConfig.DAOForkSupport && chainConfig.DAOForkBlock != nil && chainConfig.DAOForkBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyDAOHardFork(ibs)
+	}
+
+	posa, isPoSa := engine.(consensus.PoSA)
+	noop := state.NewNoopWriter()
+	gp := new(core.GasPool).AddGas(block.GasLimit())
+	usedGas := new(uint64)
+	vmConfig := vm.Config{Debug: true, Tracer: tracer}
+	for i, txn := range block.Transactions() {
+		if isPoSa {
+			// System transactions are applied by the engine at the end of the block
+			if isSystemTx, err := posa.IsSystemTransaction(txn, header); err != nil {
+				return err
+			} else if isSystemTx {
+				break
+			}
+		}
+		ibs.Prepare(txn.Hash(), blockHash, i)
+		if _, _, err = core.ApplyTransaction(chainConfig, getHeader, engine, nil, gp, ibs, noop, header, txn, usedGas, vmConfig, contractHasTEVM); err != nil {
+			return fmt.Errorf("could not apply tx %d from block %d [%v]: %w", i, blockNum, txn.Hash().Hex(), err)
+		}
+		if err = onTx(i, txn, ibs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
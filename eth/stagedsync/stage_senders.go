@@ -384,6 +384,20 @@ func PruneSendersStage(s *PruneState, tx kv.RwTx, cfg SendersCfg, ctx context.Co
 			if err = PruneTable(tx, kv.Senders, canDeleteTo, ctx, 1_000); err != nil {
 				return err
 			}
+			// receipts and logs are kept in db until they are in receipts segments, unless pruned by --prune=r
+			if !cfg.prune.Receipts.Enabled() {
+				receiptsFrom, err := rawdb.ReceiptsAvailableFrom(tx)
+				if err != nil {
+					return err
+				}
+				receiptsTo := cmp.Min(cfg.blockRetire.Snapshots().ReceiptsAvailableTo(receiptsFrom), canDeleteTo)
+				if err = PruneTable(tx, kv.Receipts, receiptsTo, ctx, 1_000); err != nil {
+					return err
+				}
+				if err = PruneTable(tx, kv.Log, receiptsTo, ctx, 1_000); err != nil {
+					return err
+				}
+			}
 		}
 
 		if err := retireBlocksInSingleBackgroundThread(s, cfg, ctx); err != nil {
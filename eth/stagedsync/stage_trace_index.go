@@ -0,0 +1,103 @@
+package stagedsync
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/interfaces"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/tracestore"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+type TraceIndexCfg struct {
+	replayCfg
+}
+
+func StageTraceIndexCfg(
+	db kv.RwDB,
+	prune prune.Mode,
+	chainConfig *params.ChainConfig,
+	engine consensus.Engine,
+	blockReader interfaces.FullBlockReader,
+) TraceIndexCfg {
+	return TraceIndexCfg{replayCfg{
+		db:          db,
+		prune:       prune,
+		chainConfig: chainConfig,
+		engine:      engine,
+		blockReader: blockReader,
+	}}
+}
+
+// SpawnTraceIndex - re-executes blocks on top of historical state and stores Parity-style traces of their transactions.
+// Must run after history index stages.
+func SpawnTraceIndex(s *StageState, tx kv.RwTx, cfg TraceIndexCfg, ctx context.Context) error {
+	return spawnReplayStage(s, tx, cfg.replayCfg, ctx, "Storing traces", func(tx kv.RwTx, blockNum uint64) error {
+		return traceBlock(ctx, tx, cfg, blockNum)
+	})
+}
+
+func traceBlock(ctx context.Context, tx kv.RwTx, cfg TraceIndexCfg, blockNum uint64) error {
+	tracer := tracestore.NewTracer()
+	return replayBlock(ctx, tx, cfg.replayCfg, blockNum, tracer, func(txIndex int, txn types.Transaction, ibs *state.IntraBlockState) error {
+		defer tracer.Reset()
+		return tracestore.WriteTxTraces(tx, blockNum, txIndex, tracer.Traces())
+	})
+}
+
+func UnwindTraceIndex(u *UnwindState, s *StageState, tx kv.RwTx, cfg TraceIndexCfg, ctx context.Context) (err error) {
+	if s.BlockNumber <= u.UnwindPoint {
+		return nil
+	}
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if err = tracestore.TruncateTraces(tx, u.UnwindPoint+1); err != nil {
+		return err
+	}
+	if err = u.Done(tx); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PruneTraceIndex(s *PruneState, tx kv.RwTx, cfg TraceIndexCfg, ctx context.Context) (err error) {
+	useExternalTx := tx != nil
+	if !useExternalTx {
+		tx, err = cfg.db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+
+	if cfg.prune.CallTraces.Enabled() {
+		if err = tracestore.PruneTraces(tx, cfg.prune.CallTraces.PruneTo(s.ForwardProgress)); err != nil {
+			return err
+		}
+	}
+	if err = s.Done(tx); err != nil {
+		return err
+	}
+	if !useExternalTx {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
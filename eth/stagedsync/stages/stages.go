@@ -42,6 +42,8 @@ var (
 	StorageHistoryIndex SyncStage = "StorageHistoryIndex" // Generating history index for storage
 	LogIndex            SyncStage = "LogIndex"            // Generating logs index (from receipts)
 	CallTraces          SyncStage = "CallTraces"          // Generating call traces index
+	TraceIndex          SyncStage = "TraceIndex"          // Storing Parity-style traces of transactions (optional)
+	AddressAppearances  SyncStage = "AddressAppearances"  // Indexing transactions by addresses appearing in them (optional)
 	TxLookup            SyncStage = "TxLookup"            // Generating transactions lookup index
 	Issuance            SyncStage = "WatchTheBurn"        // Compute ether issuance for each block
 	Finish              SyncStage = "Finish"              // Nominal stage after all other stages
@@ -64,6 +66,8 @@ var AllStages = []SyncStage{
 	StorageHistoryIndex,
 	LogIndex,
 	CallTraces,
+	TraceIndex,
+	AddressAppearances,
 	TxLookup,
 	Finish,
 }
@@ -0,0 +1,128 @@
+package stages
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// SyncStatus - progress of staged sync. Per-stage progress is read from DB,
+// throughput and timings are in-memory stats of the running sync loop (see StatusTracker).
+type SyncStatus struct {
+	CurrentBlock uint64        `json:"currentBlock"` // progress of Finish stage
+	HighestBlock uint64        `json:"highestBlock"` // progress of Headers stage
+	CurrentStage SyncStage     `json:"currentStage"` // empty if sync loop is waiting for new blocks
+	Stages       []StageStatus `json:"stages"`
+	Throughput   Throughput    `json:"throughput"`
+	LastCycle    []StageTiming `json:"lastCycle"` // timings of last finished sync cycle
+	// ETA - estimated time (in seconds) to execute blocks up to HighestBlock with current Execution throughput,
+	// 0 if sync is done or throughput is unknown
+	ETA float64 `json:"eta"`
+}
+
+type StageStatus struct {
+	Stage         SyncStage `json:"stage"`
+	Progress      uint64    `json:"progress"`
+	PruneProgress uint64    `json:"pruneProgress"`
+}
+
+// Throughput - speed of Execution stage measured over last log interval
+type Throughput struct {
+	Block           uint64    `json:"block"` // last executed block at the moment of measurement
+	BlocksPerSecond float64   `json:"blocksPerSecond"`
+	TxsPerSecond    float64   `json:"txsPerSecond"`
+	GasPerSecond    float64   `json:"gasPerSecond"`
+	MeasuredAt      time.Time `json:"measuredAt"`
+}
+
+type StageTiming struct {
+	Stage  SyncStage `json:"stage"`
+	Unwind bool      `json:"unwind,omitempty"`
+	Prune  bool      `json:"prune,omitempty"`
+	Took   float64   `json:"took"` // seconds
+}
+
+// StatusTracker - collects in-memory stats of sync loop. Safe for concurrent use, nil-safe.
+type StatusTracker struct {
+	stages       []SyncStage // stages run by sync loop, in order of execution
+	lock         sync.RWMutex
+	currentStage SyncStage
+	throughput   Throughput
+	lastCycle    []StageTiming
+}
+
+func NewStatusTracker(stages []SyncStage) *StatusTracker { return &StatusTracker{stages: stages} }
+
+func (t *StatusTracker) SetCurrentStage(id SyncStage) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.currentStage = id
+}
+
+func (t *StatusTracker) SetThroughput(throughput Throughput) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.throughput = throughput
+}
+
+func (t *StatusTracker) SetLastCycle(timings []StageTiming) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.lastCycle = append(t.lastCycle[:0:0], timings...)
+}
+
+// ReadSyncStatus - collects progress of stages run by sync loop of tracker (of AllStages if tracker is nil)
+// from DB and merges it with stats of tracker
+func ReadSyncStatus(db kv.Getter, tracker *StatusTracker) (*SyncStatus, error) {
+	ids := AllStages
+	if tracker != nil {
+		ids = tracker.stages
+	}
+	st := &SyncStatus{Stages: make([]StageStatus, len(ids))}
+	var err error
+	for i, stage := range ids {
+		st.Stages[i].Stage = stage
+		if st.Stages[i].Progress, err = GetStageProgress(db, stage); err != nil {
+			return nil, err
+		}
+		if st.Stages[i].PruneProgress, err = GetStagePruneProgress(db, stage); err != nil {
+			return nil, err
+		}
+	}
+	if st.HighestBlock, err = GetStageProgress(db, Headers); err != nil {
+		return nil, err
+	}
+	if st.CurrentBlock, err = GetStageProgress(db, Finish); err != nil {
+		return nil, err
+	}
+	executed, err := GetStageProgress(db, Execution)
+	if err != nil {
+		return nil, err
+	}
+
+	if tracker != nil {
+		tracker.lock.RLock()
+		st.CurrentStage = tracker.currentStage
+		st.Throughput = tracker.throughput
+		st.LastCycle = append([]StageTiming{}, tracker.lastCycle...)
+		tracker.lock.RUnlock()
+	}
+	// progress of Execution is committed to DB only at the end of cycle, tracker knows more recent one
+	if st.Throughput.Block > executed {
+		executed = st.Throughput.Block
+	}
+	if st.HighestBlock > executed && st.Throughput.BlocksPerSecond > 0 {
+		st.ETA = float64(st.HighestBlock-executed) / st.Throughput.BlocksPerSecond
+	}
+	return st, nil
+}
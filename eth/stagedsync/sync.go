@@ -25,6 +25,7 @@ type Sync struct {
 	currentStage uint
 	timings      []Timing
 	logPrefixes  []string
+	status       *stages.StatusTracker // in-memory stats of sync loop, served by erigon_syncStatus
 }
 
 type Timing struct {
@@ -34,8 +35,9 @@ type Timing struct {
 	took     time.Duration
 }
 
-func (s *Sync) Len() int                 { return len(s.stages) }
-func (s *Sync) PrevUnwindPoint() *uint64 { return s.prevUnwindPoint }
+func (s *Sync) Len() int                      { return len(s.stages) }
+func (s *Sync) PrevUnwindPoint() *uint64      { return s.prevUnwindPoint }
+func (s *Sync) Status() *stages.StatusTracker { return s.status }
 
 func (s *Sync) NewUnwindState(id stages.SyncStage, unwindPoint, currentProgress uint64) *UnwindState {
 	return &UnwindState{id, unwindPoint, currentProgress, common.Hash{}, s}
@@ -153,8 +155,10 @@ func New(stagesList []*Stage, unwindOrder UnwindOrder, pruneOrder PruneOrder) *S
 		}
 	}
 	logPrefixes := make([]string, len(stagesList))
+	ids := make([]stages.SyncStage, len(stagesList))
 	for i := range stagesList {
 		logPrefixes[i] = fmt.Sprintf("%d/%d %s", i+1, len(stagesList), stagesList[i].ID)
+		ids[i] = stagesList[i].ID
 	}
 
 	return &Sync{
@@ -163,6 +167,7 @@ func New(stagesList []*Stage, unwindOrder UnwindOrder, pruneOrder PruneOrder) *S
 		unwindOrder:  unwindStages,
 		pruningOrder: pruneStages,
 		logPrefixes:  logPrefixes,
+		status:       stages.NewStatusTracker(ids),
 	}
 }
 
@@ -193,6 +198,7 @@ func (s *Sync) StageState(stage stages.SyncStage, tx kv.Tx, db kv.RoDB) (*StageS
 func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 	s.prevUnwindPoint = nil
 	s.timings = s.timings[:0]
+	defer s.status.SetCurrentStage("")
 
 	for !s.IsDone() {
 		var badBlockUnwind bool
@@ -252,6 +258,7 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 		return err
 	}
 
+	s.status.SetLastCycle(stageTimings(s.timings))
 	if err := printLogs(tx, s.timings); err != nil {
 		return err
 	}
@@ -259,6 +266,14 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 	return nil
 }
 
+func stageTimings(timings []Timing) []stages.StageTiming {
+	res := make([]stages.StageTiming, len(timings))
+	for i := range timings {
+		res[i] = stages.StageTiming{Stage: timings[i].stage, Unwind: timings[i].isUnwind, Prune: timings[i].isPrune, Took: timings[i].took.Seconds()}
+	}
+	return res
+}
+
 func printLogs(tx kv.RwTx, timings []Timing) error {
 	var logCtx []interface{}
 	count := 0
@@ -310,6 +325,7 @@ func printLogs(tx kv.RwTx, timings []Timing) error {
 }
 
 func (s *Sync) runStage(stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, badBlockUnwind bool) (err error) {
+	s.status.SetCurrentStage(stage.ID)
 	start := time.Now()
 	stageState, err := s.StageState(stage.ID, tx, db)
 	if err != nil {
@@ -330,6 +346,7 @@ func (s *Sync) runStage(stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, b
 }
 
 func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) error {
+	s.status.SetCurrentStage(stage.ID)
 	start := time.Now()
 	log.Trace("Unwind...", "stage", stage.ID)
 	stageState, err := s.StageState(stage.ID, tx, db)
@@ -363,6 +380,7 @@ func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx
 }
 
 func (s *Sync) pruneStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) error {
+	s.status.SetCurrentStage(stage.ID)
 	start := time.Now()
 	log.Trace("Prune...", "stage", stage.ID)
 
@@ -58,13 +58,14 @@ func (vm *JSVM) PushFixedBuffer(size int) unsafe.Pointer {
 }
 
 func (vm *JSVM) PushGoFunction(fn0 func(*JSVM) int) {
-	fn := func(this goja.Value, args ...goja.Value) (goja.Value, error) {
-		vm.stack = append(vm.stack, this)
-		vm.stack = append(vm.stack, args...)
+	// goja.FunctionCall signature lets goja pass the arguments as is, without
+	// reflection (which spreads a buffer passed as the last argument)
+	fn := func(call goja.FunctionCall) goja.Value {
+		vm.stack = append(vm.stack, call.Arguments...)
 		_ = fn0(vm)
 		result := vm.stack[len(vm.stack)-1]
 		vm.Pop()
-		return result, nil
+		return result
 	}
 	vm.pushAny(fn)
 }
@@ -138,15 +139,16 @@ func (vm *JSVM) PutGlobalString(key string) {
 }
 
 func (vm *JSVM) PushGlobalGoFunction(name string, fn0 func(*JSVM) int) {
-	fn := func(this goja.Value, args ...goja.Value) (goja.Value, error) {
-		vm.stack = append(vm.stack, this)
-		vm.stack = append(vm.stack, args...)
+	// goja.FunctionCall signature lets goja pass the arguments as is, without
+	// reflection (which spreads a buffer passed as the last argument)
+	fn := func(call goja.FunctionCall) goja.Value {
+		vm.stack = append(vm.stack, call.Arguments...)
 		_ = fn0(vm)
 		result := vm.stack[len(vm.stack)-1]
 		vm.Pop()
-		return result, nil
+		return result
 	}
-	err := vm.vm.GlobalObject().Set(name, goja.Callable(fn))
+	err := vm.vm.GlobalObject().Set(name, fn)
 	if err != nil {
 		panic(err)
 	}
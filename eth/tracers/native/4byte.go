@@ -0,0 +1,111 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+func init() {
+	register("4byteTracer", newFourByteTracer)
+}
+
+// fourByteTracer is a port of 4byte_tracer.js, it searches for 4byte-identifiers
+// of all internal calls and reports them together with the size of call data.
+// The result is a map of "<id>-<size>" keys to the number of occurrences.
+type fourByteTracer struct {
+	ids               map[string]int // ids aggregates the 4byte ids found
+	activePrecompiles []common.Address
+	input             []byte
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+func newFourByteTracer() Tracer {
+	return &fourByteTracer{ids: make(map[string]int)}
+}
+
+// store saves the given identifier and datasize.
+func (t *fourByteTracer) store(id []byte, size uint64) {
+	t.ids[hexutil.Encode(id)+"-"+strconv.FormatUint(size, 10)]++
+}
+
+func (t *fourByteTracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
+	if depth != 0 {
+		return
+	}
+	t.input = common.CopyBytes(input)
+	t.activePrecompiles = vm.ActivePrecompiles(env.ChainRules())
+}
+
+func (t *fourByteTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	// Skip any opcodes that are not internal calls, find the stack position of
+	// the first param after 'value', i.e. meminstart
+	var ct int
+	switch op {
+	case vm.CALL, vm.CALLCODE:
+		// gas, addr, val, memin, meminsz, memout, memoutsz
+		ct = 3
+	case vm.DELEGATECALL, vm.STATICCALL:
+		// gas, addr, memin, meminsz, memout, memoutsz
+		ct = 2
+	default:
+		return
+	}
+	stack := scope.Stack
+	// Skip any pre-compile invocations, those are just fancy opcodes
+	if isPrecompiled(t.activePrecompiles, peek(stack, 1).Bytes20()) {
+		return
+	}
+	// Gather internal call details
+	inSz := peek(stack, ct+1).Uint64()
+	if inSz >= 4 {
+		inOff := peek(stack, ct).Uint64()
+		t.store(memorySlice(scope.Memory, inOff, inOff+4), inSz-4)
+	}
+}
+
+func (t *fourByteTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *fourByteTracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, d time.Duration, err error) {
+}
+
+func (t *fourByteTracer) CaptureSelfDestruct(from common.Address, to common.Address, value *big.Int) {
+}
+
+func (t *fourByteTracer) CaptureAccountRead(account common.Address) error {
+	return nil
+}
+
+func (t *fourByteTracer) CaptureAccountWrite(account common.Address) error {
+	return nil
+}
+
+// GetResult returns the json-encoded identifiers, or the reason of interruption.
+func (t *fourByteTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil {
+		return nil, t.reason
+	}
+	// Save the outer calldata also
+	if len(t.input) >= 4 {
+		t.store(t.input[:4], uint64(len(t.input)-4))
+	}
+	return json.Marshal(t.ids)
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *fourByteTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
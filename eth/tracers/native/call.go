@@ -0,0 +1,298 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+func init() {
+	register("callTracer", newCallTracer)
+}
+
+// callFrame is a single call of the call tracer output. All fields are kept
+// already formatted, unset fields are omitted from the output the same way
+// undefined fields are omitted by the JavaScript callTracer.
+type callFrame struct {
+	Type    string      `json:"type"`
+	From    string      `json:"from,omitempty"`
+	To      string      `json:"to,omitempty"`
+	Value   string      `json:"value,omitempty"`
+	Gas     string      `json:"gas,omitempty"`
+	GasUsed string      `json:"gasUsed,omitempty"`
+	Input   *string     `json:"input,omitempty"`
+	Output  *string     `json:"output,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Time    string      `json:"time,omitempty"`
+	Calls   []callFrame `json:"calls,omitempty"`
+
+	// Intermediate values which are needed until the call returns
+	gasIn   uint64
+	gasCost uint64
+	gas     *uint64
+	outOff  uint64
+	outLen  uint64
+}
+
+// callTracer is a port of call_tracer.js, it reports all internal calls made
+// by a transaction as a tree.
+type callTracer struct {
+	callstack []callFrame // Current recursive call stack of the EVM execution
+	// descended tracks whether we've just descended from an outer transaction into an inner call
+	descended bool
+
+	activePrecompiles []common.Address
+
+	typ     string
+	from    common.Address
+	to      common.Address
+	input   []byte
+	gas     uint64
+	value   *big.Int
+	output  []byte
+	gasUsed uint64
+	time    string
+	err     error
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+func newCallTracer() Tracer {
+	return &callTracer{callstack: make([]callFrame, 1)}
+}
+
+func (t *callTracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
+	if depth != 0 {
+		return
+	}
+	t.typ = "CALL"
+	if create {
+		t.typ = "CREATE"
+	}
+	t.from, t.to = from, to
+	t.input = common.CopyBytes(input)
+	t.gas = gas
+	t.value = value
+	t.activePrecompiles = vm.ActivePrecompiles(env.ChainRules())
+}
+
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	// Capture any errors immediately
+	if err != nil {
+		t.fault(err)
+		return
+	}
+	stack, memory, contract := scope.Stack, scope.Memory, scope.Contract
+	// We only care about system opcodes, faster if we pre-check once
+	syscall := op&0xf0 == 0xf0
+
+	// If a new contract is being created, add to the call stack
+	if syscall && (op == vm.CREATE || op == vm.CREATE2) {
+		inOff := peek(stack, 1).Uint64()
+		inEnd := inOff + peek(stack, 2).Uint64()
+		input := hexutil.Encode(memorySlice(memory, inOff, inEnd))
+		t.callstack = append(t.callstack, callFrame{
+			Type:    op.String(),
+			From:    hexutil.Encode(contract.Address().Bytes()),
+			Input:   &input,
+			Value:   bigToHex(peek(stack, 0).ToBig()),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		t.descended = true
+		return
+	}
+	// If a contract is being self destructed, gather that as a subcall too
+	if syscall && op == vm.SELFDESTRUCT {
+		to := common.Address(peek(stack, 0).Bytes20())
+		left := len(t.callstack)
+		t.callstack[left-1].Calls = append(t.callstack[left-1].Calls, callFrame{
+			Type:    op.String(),
+			From:    hexutil.Encode(contract.Address().Bytes()),
+			To:      hexutil.Encode(to.Bytes()),
+			Value:   bigToHex(env.IntraBlockState().GetBalance(contract.Address()).ToBig()),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		return
+	}
+	// If a new method invocation is being done, add to the call stack
+	if syscall && (op == vm.CALL || op == vm.CALLCODE || op == vm.DELEGATECALL || op == vm.STATICCALL) {
+		// Skip any pre-compile invocations, those are just fancy opcodes
+		to := common.Address(peek(stack, 1).Bytes20())
+		if isPrecompiled(t.activePrecompiles, to) {
+			return
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		inOff := peek(stack, 2+off).Uint64()
+		inEnd := inOff + peek(stack, 3+off).Uint64()
+		input := hexutil.Encode(memorySlice(memory, inOff, inEnd))
+		call := callFrame{
+			Type:    op.String(),
+			From:    hexutil.Encode(contract.Address().Bytes()),
+			To:      hexutil.Encode(to.Bytes()),
+			Input:   &input,
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  peek(stack, 4+off).Uint64(),
+			outLen:  peek(stack, 5+off).Uint64(),
+		}
+		if op != vm.DELEGATECALL && op != vm.STATICCALL {
+			call.Value = bigToHex(peek(stack, 2).ToBig())
+		}
+		t.callstack = append(t.callstack, call)
+		t.descended = true
+		return
+	}
+	// If we've just descended into an inner call, retrieve it's true allowance. We
+	// need to extract if from within the call as there may be funky gas dynamics
+	// with regard to requested and actually given gas (2300 stipend, 63/64 rule).
+	if t.descended {
+		if depth >= len(t.callstack) {
+			callGas := gas
+			t.callstack[len(t.callstack)-1].gas = &callGas
+		}
+		t.descended = false
+	}
+	// If an existing call is returning, pop off the call stack
+	if syscall && op == vm.REVERT {
+		t.callstack[len(t.callstack)-1].Error = "execution reverted"
+		return
+	}
+	if depth == len(t.callstack)-1 {
+		// Pop off the last call and get the execution results
+		call := t.callstack[len(t.callstack)-1]
+		t.callstack = t.callstack[:len(t.callstack)-1]
+
+		ret := peek(stack, 0)
+		if call.Type == "CREATE" || call.Type == "CREATE2" {
+			// If the call was a CREATE, retrieve the contract address and output code
+			call.GasUsed = hexutil.EncodeUint64(call.gasIn - call.gasCost - gas)
+			if !ret.IsZero() {
+				created := common.Address(ret.Bytes20())
+				output := hexutil.Encode(env.IntraBlockState().GetCode(created))
+				call.To = hexutil.Encode(created.Bytes())
+				call.Output = &output
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		} else {
+			// If the call was a contract call, retrieve the gas usage and output
+			if call.gas != nil {
+				call.GasUsed = hexutil.EncodeUint64(call.gasIn - call.gasCost + *call.gas - gas)
+			}
+			if !ret.IsZero() {
+				output := hexutil.Encode(memorySlice(memory, call.outOff, call.outOff+call.outLen))
+				call.Output = &output
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		}
+		if call.gas != nil {
+			call.Gas = hexutil.EncodeUint64(*call.gas)
+		}
+		// Inject the call into the previous one
+		left := len(t.callstack)
+		t.callstack[left-1].Calls = append(t.callstack[left-1].Calls, call)
+	}
+}
+
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	t.fault(err)
+}
+
+// fault handles the failure of the currently executing call.
+func (t *callTracer) fault(err error) {
+	// If the topmost call already reverted, don't handle the additional fault again
+	if t.callstack[len(t.callstack)-1].Error != "" {
+		return
+	}
+	// Pop off the just failed call
+	call := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	call.Error = err.Error()
+
+	// Consume all available gas and clean any leftovers
+	if call.gas != nil {
+		call.Gas = hexutil.EncodeUint64(*call.gas)
+		call.GasUsed = call.Gas
+	}
+	// Flatten the failed call into its parent
+	if left := len(t.callstack); left > 0 {
+		t.callstack[left-1].Calls = append(t.callstack[left-1].Calls, call)
+		return
+	}
+	// Last call failed too, leave it in the stack
+	t.callstack = append(t.callstack, call)
+}
+
+func (t *callTracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, d time.Duration, err error) {
+	if depth != 0 {
+		return
+	}
+	t.output = common.CopyBytes(output)
+	t.gasUsed = startGas - endGas
+	t.time = d.String()
+	t.err = err
+}
+
+func (t *callTracer) CaptureSelfDestruct(from common.Address, to common.Address, value *big.Int) {
+}
+
+func (t *callTracer) CaptureAccountRead(account common.Address) error {
+	return nil
+}
+
+func (t *callTracer) CaptureAccountWrite(account common.Address) error {
+	return nil
+}
+
+// GetResult returns the json-encoded tree of calls, or the reason of interruption.
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil {
+		return nil, t.reason
+	}
+	input, output := hexutil.Encode(t.input), hexutil.Encode(t.output)
+	result := callFrame{
+		Type:    t.typ,
+		From:    hexutil.Encode(t.from.Bytes()),
+		To:      hexutil.Encode(t.to.Bytes()),
+		Value:   bigToHex(t.value),
+		Gas:     hexutil.EncodeUint64(t.gas),
+		GasUsed: hexutil.EncodeUint64(t.gasUsed),
+		Input:   &input,
+		Output:  &output,
+		Time:    t.time,
+		Calls:   t.callstack[0].Calls,
+	}
+	if t.callstack[0].Error != "" {
+		result.Error = t.callstack[0].Error
+	} else if t.err != nil {
+		result.Error = t.err.Error()
+	}
+	if result.Error != "" && (result.Error != "execution reverted" || output == "0x") {
+		result.Output = nil
+	}
+	return json.Marshal(result)
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *callTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
@@ -0,0 +1,194 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+func init() {
+	register("prestateTracer", newPrestateTracer)
+}
+
+type prestateAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash][]byte // values without leading zeros, as db.getState returns them to JavaScript
+}
+
+func (a *prestateAccount) MarshalJSON() ([]byte, error) {
+	storage := make(map[string]string, len(a.storage))
+	for k, v := range a.storage {
+		storage[hexutil.Encode(k[:])] = hexutil.Encode(v)
+	}
+	return json.Marshal(struct {
+		Balance string            `json:"balance"`
+		Nonce   uint64            `json:"nonce"`
+		Code    string            `json:"code"`
+		Storage map[string]string `json:"storage"`
+	}{bigToHex(a.balance), a.nonce, hexutil.Encode(a.code), storage})
+}
+
+// prestateTracer is a port of prestate_tracer.js, it collects the state of all
+// accounts and storage slots touched by a transaction as it was before the
+// transaction was executed.
+type prestateTracer struct {
+	prestate map[common.Address]*prestateAccount
+	ibs      vm.IntraBlockState
+
+	create       bool
+	from, to     common.Address
+	value        *big.Int
+	gasPrice     *big.Int
+	intrinsicGas uint64
+	gasUsed      uint64
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+func newPrestateTracer() Tracer {
+	return &prestateTracer{}
+}
+
+func (t *prestateTracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
+	if depth != 0 {
+		return
+	}
+	t.ibs = env.IntraBlockState()
+	t.create = create
+	t.from, t.to = from, to
+	t.value = value
+	t.gasPrice = env.TxContext().GasPrice
+	isHomestead := env.ChainConfig().IsHomestead(env.Context().BlockNumber)
+	isIstanbul := env.ChainConfig().IsIstanbul(env.Context().BlockNumber)
+	if intrinsicGas, err := core.IntrinsicGas(input, nil, create, isHomestead, isIstanbul); err == nil {
+		t.intrinsicGas = intrinsicGas
+	}
+}
+
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	stack, contract := scope.Stack, scope.Contract
+	// Add the current account if we just started tracing
+	if t.prestate == nil {
+		t.prestate = make(map[common.Address]*prestateAccount)
+		// Balance will potentially be wrong here, since this will include the value
+		// sent along with the message. We fix that in GetResult.
+		t.lookupAccount(contract.Address())
+	}
+	// Whenever new state is accessed, add it to the prestate
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.BALANCE:
+		t.lookupAccount(peek(stack, 0).Bytes20())
+	case vm.CREATE:
+		from := contract.Address()
+		t.lookupAccount(crypto.CreateAddress(from, t.ibs.GetNonce(from)))
+	case vm.CREATE2:
+		// stack: salt, size, offset, endowment
+		offset := peek(stack, 1).Uint64()
+		size := peek(stack, 2).Uint64()
+		salt := common.Hash(peek(stack, 3).Bytes32())
+		initCode := memorySlice(scope.Memory, offset, offset+size)
+		t.lookupAccount(crypto.CreateAddress2(contract.Address(), salt, crypto.Keccak256(initCode)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(peek(stack, 1).Bytes20())
+	case vm.SSTORE, vm.SLOAD:
+		t.lookupStorage(contract.Address(), peek(stack, 0).Bytes32())
+	}
+}
+
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *prestateTracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, d time.Duration, err error) {
+	if depth != 0 {
+		return
+	}
+	t.gasUsed = startGas - endGas
+}
+
+func (t *prestateTracer) CaptureSelfDestruct(from common.Address, to common.Address, value *big.Int) {
+}
+
+func (t *prestateTracer) CaptureAccountRead(account common.Address) error {
+	return nil
+}
+
+func (t *prestateTracer) CaptureAccountWrite(account common.Address) error {
+	return nil
+}
+
+// GetResult returns the json-encoded prestate, or the reason of interruption.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil {
+		return nil, t.reason
+	}
+	if t.ibs == nil {
+		return json.Marshal(map[common.Address]*prestateAccount{})
+	}
+	if t.prestate == nil {
+		// No code was executed, the recipient is looked up after the value transfer
+		t.prestate = make(map[common.Address]*prestateAccount)
+	}
+	// At this point, we need to deduct the 'value' from the
+	// outer transaction, and move it back to the origin
+	t.lookupAccount(t.from)
+	t.lookupAccount(t.to)
+
+	fromAcc, toAcc := t.prestate[t.from], t.prestate[t.to]
+	toAcc.balance = new(big.Int).Sub(toAcc.balance, t.value)
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(t.gasUsed+t.intrinsicGas), t.gasPrice)
+	fromAcc.balance = new(big.Int).Add(fromAcc.balance, new(big.Int).Add(t.value, fee))
+
+	// Decrement the caller's nonce, and remove empty create targets
+	fromAcc.nonce--
+	if t.create {
+		// We can blindly delete the contract prestate, as any existing state would
+		// have caused the transaction to be rejected as invalid in the first place.
+		delete(t.prestate, t.to)
+	}
+	return json.Marshal(t.prestate)
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// lookupAccount injects the specified account into the prestate.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.prestate[addr]; ok {
+		return
+	}
+	t.prestate[addr] = &prestateAccount{
+		balance: t.ibs.GetBalance(addr).ToBig(),
+		nonce:   t.ibs.GetNonce(addr),
+		code:    common.CopyBytes(t.ibs.GetCode(addr)),
+		storage: make(map[common.Hash][]byte),
+	}
+}
+
+// lookupStorage injects the specified storage entry of the given account into
+// the prestate.
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	if _, ok := t.prestate[addr].storage[key]; ok {
+		return
+	}
+	var value uint256.Int
+	t.ibs.GetState(addr, &key, &value)
+	t.prestate[addr].storage[key] = value.Bytes()
+}
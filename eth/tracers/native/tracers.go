@@ -0,0 +1,80 @@
+// Package native is a collection of tracers written in go. They mirror the
+// JavaScript tracers of the same name and produce the same output, but run
+// without the overhead of the JavaScript VM.
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/stack"
+)
+
+// Tracer is implemented by all tracers, the JavaScript ones included (as
+// tracers.Tracer). In addition to the vm.Tracer hooks it allows to retrieve
+// the result of tracing and to interrupt it.
+type Tracer interface {
+	vm.Tracer
+	GetResult() (json.RawMessage, error)
+	// Stop terminates execution of the tracer at the first opportune moment.
+	Stop(err error)
+}
+
+// ctors contains constructors of all native tracers by name.
+var ctors = make(map[string]func() Tracer)
+
+// register makes a native tracer available under the given name.
+func register(name string, ctor func() Tracer) {
+	ctors[name] = ctor
+}
+
+// New instantiates the native tracer with the given name, returns false if
+// there is no such tracer.
+func New(name string) (Tracer, bool) {
+	ctor, ok := ctors[name]
+	if !ok {
+		return nil, false
+	}
+	return ctor(), true
+}
+
+// peek returns the nth-from-the-top element of the stack, or zero if the stack
+// is not deep enough.
+func peek(s *stack.Stack, n int) *uint256.Int {
+	if s.Len() <= n || n < 0 {
+		return new(uint256.Int)
+	}
+	return s.Back(n)
+}
+
+// memorySlice returns a copy of the requested range of memory, or an empty
+// slice if the range is out of bounds.
+func memorySlice(m *vm.Memory, begin, end uint64) []byte {
+	if end <= begin || uint64(m.Len()) < end {
+		return []byte{}
+	}
+	return m.GetCopy(begin, end-begin)
+}
+
+// isPrecompiled reports whether the address belongs to one of the active
+// precompiled contracts.
+func isPrecompiled(active []common.Address, addr common.Address) bool {
+	for _, p := range active {
+		if p == addr {
+			return true
+		}
+	}
+	_, ok := vm.PrecompiledContractsIstanbul[addr]
+	return ok
+}
+
+// bigToHex formats the number in the same way as '0x' + n.toString(16) does in JavaScript.
+func bigToHex(n *big.Int) string {
+	if n == nil {
+		return "0x0"
+	}
+	return "0x" + n.Text(16)
+}
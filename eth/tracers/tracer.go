@@ -33,6 +33,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/core/vm/stack"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/tracers/native"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -289,9 +290,9 @@ func (cw *contractWrapper) pushObject(vm *JSVM) {
 	vm.PutPropString(obj, "getInput")
 }
 
-// Tracer provides an implementation of Tracer that evaluates a Javascript
+// JsTracer provides an implementation of Tracer that evaluates a Javascript
 // function for each VM execution step.
-type Tracer struct {
+type JsTracer struct {
 	vm *JSVM // Javascript VM instance
 
 	tracerObject int // Stack index of the tracer JavaScript object
@@ -327,15 +328,24 @@ type Context struct {
 	TxHash    common.Hash // Hash of the transaction being traced (zero if dangling call)
 }
 
-// New instantiates a new tracer instance. code specifies a Javascript snippet,
-// which must evaluate to an expression returning an object with 'step', 'fault'
-// and 'result' functions.
-func New(code string, ctx *Context) (*Tracer, error) {
+// New instantiates a new tracer instance. code specifies either the name of a
+// native tracer, or a Javascript snippet which must evaluate to an expression
+// returning an object with 'step', 'fault' and 'result' functions.
+func New(code string, ctx *Context) (Tracer, error) {
+	if tracer, ok := native.New(code); ok {
+		return tracer, nil
+	}
+	return NewJsTracer(code, ctx)
+}
+
+// NewJsTracer instantiates a new Javascript tracer instance. code specifies
+// either the name of a built in Javascript tracer, or a Javascript snippet.
+func NewJsTracer(code string, ctx *Context) (*JsTracer, error) {
 	// Resolve any tracers by name and assemble the tracer object
 	if tracer, ok := tracer(code); ok {
 		code = tracer
 	}
-	tracer := &Tracer{
+	tracer := &JsTracer{
 		vm:              JSVMNew(),
 		ctx:             make(map[string]interface{}),
 		opWrapper:       new(opWrapper),
@@ -536,14 +546,14 @@ func New(code string, ctx *Context) (*Tracer, error) {
 }
 
 // Stop terminates execution of the tracer at the first opportune moment.
-func (jst *Tracer) Stop(err error) {
+func (jst *JsTracer) Stop(err error) {
 	jst.reason = err
 	atomic.StoreUint32(&jst.interrupt, 1)
 }
 
 // call executes a method on a JS object, catching any errors, formatting and
 // returning them as error objects.
-func (jst *Tracer) call(noret bool, method string, args ...string) (json.RawMessage, error) {
+func (jst *JsTracer) call(noret bool, method string, args ...string) (json.RawMessage, error) {
 	// Execute the JavaScript call and return any error
 	jst.vm.PushString(method)
 	for _, arg := range args {
@@ -578,7 +588,7 @@ func wrapError(context string, err error) error {
 }
 
 // CaptureStart implements the Tracer interface to initialize the tracing operation.
-func (jst *Tracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
+func (jst *JsTracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
 	if depth != 0 {
 		return
 	}
@@ -607,7 +617,7 @@ func (jst *Tracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to
 }
 
 // CaptureState implements the Tracer interface to trace a single step of VM execution.
-func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rdata []byte, depth int, err error) {
+func (jst *JsTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rdata []byte, depth int, err error) {
 	if jst.err != nil {
 		return
 	}
@@ -640,7 +650,7 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 
 // CaptureFault implements the Tracer interface to trace an execution fault
 // while running an opcode.
-func (jst *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+func (jst *JsTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
 	if jst.err != nil {
 		return
 	}
@@ -653,7 +663,7 @@ func (jst *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 }
 
 // CaptureEnd is called after the call finishes to finalize the tracing.
-func (jst *Tracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, t time.Duration, err error) {
+func (jst *JsTracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, t time.Duration, err error) {
 	if depth != 0 {
 		return
 	}
@@ -666,19 +676,19 @@ func (jst *Tracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64,
 	}
 }
 
-func (jst *Tracer) CaptureSelfDestruct(from, to common.Address, value *big.Int) {
+func (jst *JsTracer) CaptureSelfDestruct(from, to common.Address, value *big.Int) {
 }
 
-func (jst *Tracer) CaptureAccountRead(account common.Address) error {
+func (jst *JsTracer) CaptureAccountRead(account common.Address) error {
 	return nil
 }
 
-func (jst *Tracer) CaptureAccountWrite(account common.Address) error {
+func (jst *JsTracer) CaptureAccountWrite(account common.Address) error {
 	return nil
 }
 
 // GetResult calls the Javascript 'result' function and returns its value, or any accumulated error
-func (jst *Tracer) GetResult() (json.RawMessage, error) {
+func (jst *JsTracer) GetResult() (json.RawMessage, error) {
 	// Transform the context into a JavaScript object and inject into the state
 	obj := jst.vm.PushObject()
 
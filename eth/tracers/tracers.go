@@ -14,7 +14,8 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package tracers is a collection of JavaScript transaction tracers.
+// Package tracers is a collection of JavaScript transaction tracers. The most
+// used of them also have native counterparts in package native.
 package tracers
 
 import (
@@ -22,8 +23,13 @@ import (
 	"unicode"
 
 	"github.com/ledgerwatch/erigon/eth/tracers/internal/tracers"
+	"github.com/ledgerwatch/erigon/eth/tracers/native"
 )
 
+// Tracer is implemented by both the JavaScript and the native tracers, it's
+// defined in package native which can't depend on this one.
+type Tracer = native.Tracer
+
 // all contains all the built in JavaScript tracers by name.
 var all = make(map[string]string)
 
@@ -211,6 +211,73 @@ func TestPrestateTracerCreate2(t *testing.T) {
 // Iterates over all the input-output datasets in the tracer test harness and
 // runs the JavaScript tracers against them.
 func TestCallTracer(t *testing.T) {
+	testCallTracer(t, func() (Tracer, error) { return NewJsTracer("callTracer", new(Context)) })
+}
+
+// Same as TestCallTracer, but runs the native call tracer.
+func TestCallTracerNative(t *testing.T) {
+	testCallTracer(t, func() (Tracer, error) { return New("callTracer", new(Context)) })
+}
+
+func testCallTracer(t *testing.T, newTracer func() (Tracer, error)) {
+	forEachCallTracerTest(t, func(t *testing.T, test *callTracerTest) {
+		// Create the tracer, run it and compare the result against the etalon
+		tracer, err := newTracer()
+		if err != nil {
+			t.Fatalf("failed to create call tracer: %v", err)
+		}
+		res := runCallTracerTest(t, test, tracer)
+		ret := new(callTrace)
+		if err := json.Unmarshal(res, ret); err != nil {
+			t.Fatalf("failed to unmarshal trace result: %v", err)
+		}
+
+		if !jsonEqual(ret, test.Result) {
+			// uncomment this for easier debugging
+			//have, _ := json.MarshalIndent(ret, "", " ")
+			//want, _ := json.MarshalIndent(test.Result, "", " ")
+			//t.Fatalf("trace mismatch: \nhave %+v\nwant %+v", string(have), string(want))
+			t.Fatalf("trace mismatch: \nhave %+v\nwant %+v", ret, test.Result)
+		}
+	})
+}
+
+// Runs the native tracers and their JavaScript counterparts over the tracer test
+// harness and checks that they produce the same output.
+func TestNativeTracersMatchJs(t *testing.T) {
+	for _, name := range []string{"callTracer", "prestateTracer", "4byteTracer"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			forEachCallTracerTest(t, func(t *testing.T, test *callTracerTest) {
+				jsTracer, err := NewJsTracer(name, new(Context))
+				if err != nil {
+					t.Fatalf("failed to create js tracer: %v", err)
+				}
+				nativeTracer, err := New(name, new(Context))
+				if err != nil {
+					t.Fatalf("failed to create native tracer: %v", err)
+				}
+				if _, ok := nativeTracer.(*JsTracer); ok {
+					t.Fatalf("tracer %s is not native", name)
+				}
+				var want, have map[string]interface{}
+				if err := json.Unmarshal(runCallTracerTest(t, test, jsTracer), &want); err != nil {
+					t.Fatalf("failed to unmarshal js trace result: %v", err)
+				}
+				if err := json.Unmarshal(runCallTracerTest(t, test, nativeTracer), &have); err != nil {
+					t.Fatalf("failed to unmarshal native trace result: %v", err)
+				}
+				// Execution time differs between runs
+				delete(want, "time")
+				delete(have, "time")
+				require.Equal(t, want, have)
+			})
+		})
+	}
+}
+
+// forEachCallTracerTest runs f for every call tracer test case in testdata.
+func forEachCallTracerTest(t *testing.T, f func(t *testing.T, test *callTracerTest)) {
 	files, filesErr := os.ReadDir("testdata")
 	if filesErr != nil {
 		t.Fatalf("failed to retrieve tracer test suite: %v", filesErr)
@@ -232,66 +299,57 @@ func TestCallTracer(t *testing.T) {
 			if err := json.Unmarshal(blob, test); err != nil {
 				t.Fatalf("failed to parse testcase: %v", err)
 			}
-			// Configure a blockchain with the given prestate
-			txn, err := types.DecodeTransaction(rlp.NewStream(bytes.NewReader(common.FromHex(test.Input)), 0))
-			if err != nil {
-				t.Fatalf("failed to parse testcase input: %v", err)
-			}
-			signer := types.MakeSigner(test.Genesis.Config, uint64(test.Context.Number))
-			origin, _ := signer.Sender(txn)
-			txContext := vm.TxContext{
-				Origin:   origin,
-				GasPrice: big.NewInt(int64(txn.GetPrice().Uint64())),
-			}
-			context := vm.BlockContext{
-				CanTransfer:     core.CanTransfer,
-				Transfer:        core.Transfer,
-				Coinbase:        test.Context.Miner,
-				BlockNumber:     uint64(test.Context.Number),
-				Time:            uint64(test.Context.Time),
-				Difficulty:      (*big.Int)(test.Context.Difficulty),
-				GasLimit:        uint64(test.Context.GasLimit),
-				ContractHasTEVM: func(common.Hash) (bool, error) { return false, nil },
-			}
+			f(t, test)
+		})
+	}
+}
 
-			_, tx := memdb.NewTestTx(t)
-			statedb, err := tests.MakePreState(params.Rules{}, tx, test.Genesis.Alloc, uint64(test.Context.Number))
-			require.NoError(t, err)
+// runCallTracerTest executes the transaction of the test case on top of its
+// prestate with the given tracer and returns the trace result.
+func runCallTracerTest(t *testing.T, test *callTracerTest, tracer Tracer) json.RawMessage {
+	// Configure a blockchain with the given prestate
+	txn, err := types.DecodeTransaction(rlp.NewStream(bytes.NewReader(common.FromHex(test.Input)), 0))
+	if err != nil {
+		t.Fatalf("failed to parse testcase input: %v", err)
+	}
+	signer := types.MakeSigner(test.Genesis.Config, uint64(test.Context.Number))
+	origin, _ := signer.Sender(txn)
+	txContext := vm.TxContext{
+		Origin:   origin,
+		GasPrice: big.NewInt(int64(txn.GetPrice().Uint64())),
+	}
+	context := vm.BlockContext{
+		CanTransfer:     core.CanTransfer,
+		Transfer:        core.Transfer,
+		Coinbase:        test.Context.Miner,
+		BlockNumber:     uint64(test.Context.Number),
+		Time:            uint64(test.Context.Time),
+		Difficulty:      (*big.Int)(test.Context.Difficulty),
+		GasLimit:        uint64(test.Context.GasLimit),
+		ContractHasTEVM: func(common.Hash) (bool, error) { return false, nil },
+	}
 
-			// Create the tracer, the EVM environment and run it
-			tracer, err := New("callTracer", new(Context))
-			if err != nil {
-				t.Fatalf("failed to create call tracer: %v", err)
-			}
-			evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+	_, tx := memdb.NewTestTx(t)
+	statedb, err := tests.MakePreState(params.Rules{}, tx, test.Genesis.Alloc, uint64(test.Context.Number))
+	require.NoError(t, err)
 
-			msg, err := txn.AsMessage(*signer, nil)
-			if err != nil {
-				t.Fatalf("failed to prepare transaction for tracing: %v", err)
-			}
-			st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(txn.GetGas()))
-			if _, err = st.TransitionDb(false, false); err != nil {
-				t.Fatalf("failed to execute transaction: %v", err)
-			}
-			// Retrieve the trace result and compare against the etalon
-			res, err := tracer.GetResult()
-			if err != nil {
-				t.Fatalf("failed to retrieve trace result: %v", err)
-			}
-			ret := new(callTrace)
-			if err := json.Unmarshal(res, ret); err != nil {
-				t.Fatalf("failed to unmarshal trace result: %v", err)
-			}
+	// Run the tracer in the EVM environment
+	evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
 
-			if !jsonEqual(ret, test.Result) {
-				// uncomment this for easier debugging
-				//have, _ := json.MarshalIndent(ret, "", " ")
-				//want, _ := json.MarshalIndent(test.Result, "", " ")
-				//t.Fatalf("trace mismatch: \nhave %+v\nwant %+v", string(have), string(want))
-				t.Fatalf("trace mismatch: \nhave %+v\nwant %+v", ret, test.Result)
-			}
-		})
+	msg, err := txn.AsMessage(*signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(txn.GetGas()))
+	if _, err = st.TransitionDb(false, false); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	// Retrieve the trace result
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
 	}
+	return res
 }
 
 // jsonEqual is similar to reflect.DeepEqual, but does a 'bounce' via json prior to
@@ -0,0 +1,132 @@
+package tracestore
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// Tracer collects traces of a single transaction in the same way as the OpenEthereum-style
+// tracer of the rpcdaemon does. Call Reset before reusing it for the next transaction.
+type Tracer struct {
+	traces     []Trace
+	traceAddr  []uint64
+	stack      []int // positions in traces of the calls being executed
+	precompile bool  // Whether the last CaptureStart was called with `precompile = true`
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Reset prepares the tracer for the next transaction
+func (t *Tracer) Reset() {
+	t.traces = nil
+	t.traceAddr = t.traceAddr[:0]
+	t.stack = t.stack[:0]
+	t.precompile = false
+}
+
+// Traces returns traces collected since the last Reset
+func (t *Tracer) Traces() []Trace {
+	return t.traces
+}
+
+func (t *Tracer) CaptureStart(env *vm.EVM, depth int, from common.Address, to common.Address, precompile bool, create bool, calltype vm.CallType, input []byte, gas uint64, value *big.Int, code []byte) {
+	if precompile && depth > 0 && value.Sign() <= 0 {
+		t.precompile = true
+		return
+	}
+	if gas > 500000000 {
+		gas = 500000001 - (0x8000000000000000 - gas)
+	}
+	trace := Trace{CallType: uint8(calltype), From: from, To: to, Gas: gas, Input: common.CopyBytes(input)}
+	if create {
+		trace.Kind = KindCreate
+	}
+	if depth > 0 {
+		top := &t.traces[t.stack[len(t.stack)-1]]
+		t.traceAddr = append(t.traceAddr, top.Subtraces)
+		top.Subtraces++
+		switch calltype {
+		case vm.DELEGATECALLT:
+			value = top.Value
+		case vm.STATICCALLT:
+			value = new(big.Int)
+		}
+	}
+	trace.Value = new(big.Int).Set(value)
+	trace.TraceAddress = make([]uint64, len(t.traceAddr))
+	copy(trace.TraceAddress, t.traceAddr)
+	t.stack = append(t.stack, len(t.traces))
+	t.traces = append(t.traces, trace)
+}
+
+func (t *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *Tracer) CaptureEnd(depth int, output []byte, startGas, endGas uint64, d time.Duration, err error) {
+	if t.precompile {
+		t.precompile = false
+		return
+	}
+	top := &t.traces[t.stack[len(t.stack)-1]]
+	if err != nil {
+		top.Error = ErrorString(err)
+	}
+	top.Output = common.CopyBytes(output)
+	top.GasUsed = startGas - endGas
+	t.stack = t.stack[:len(t.stack)-1]
+	if depth > 0 {
+		t.traceAddr = t.traceAddr[:len(t.traceAddr)-1]
+	}
+}
+
+func (t *Tracer) CaptureSelfDestruct(from common.Address, to common.Address, value *big.Int) {
+	top := &t.traces[t.stack[len(t.stack)-1]]
+	traceAddr := make([]uint64, len(t.traceAddr)+1)
+	copy(traceAddr, t.traceAddr)
+	traceAddr[len(t.traceAddr)] = top.Subtraces
+	top.Subtraces++
+	t.traces = append(t.traces, Trace{
+		Kind:         KindSuicide,
+		From:         from,
+		To:           to,
+		Value:        new(big.Int).Set(value),
+		TraceAddress: traceAddr,
+	})
+}
+
+func (t *Tracer) CaptureAccountRead(account common.Address) error {
+	return nil
+}
+
+func (t *Tracer) CaptureAccountWrite(account common.Address) error {
+	return nil
+}
+
+// ErrorString converts an EVM error into the message reported by OpenEthereum
+func ErrorString(err error) string {
+	switch err {
+	case vm.ErrInvalidJump:
+		return "Bad jump destination"
+	case vm.ErrContractAddressCollision, vm.ErrCodeStoreOutOfGas, vm.ErrOutOfGas, vm.ErrGasUintOverflow:
+		return "Out of gas"
+	case vm.ErrExecutionReverted:
+		return "Reverted"
+	case vm.ErrWriteProtection:
+		return "Mutable Call In Static Context"
+	}
+	switch err.(type) {
+	case *vm.ErrStackUnderflow:
+		return "Stack underflow"
+	case *vm.ErrInvalidOpCode:
+		return "Bad instruction"
+	}
+	return err.Error()
+}
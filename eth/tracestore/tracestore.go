@@ -0,0 +1,150 @@
+// Package tracestore keeps Parity-style (OpenEthereum) traces of transactions in the database, so
+// that trace_filter, trace_block and trace_transaction can be served without re-executing blocks.
+// Traces are produced by the TraceIndex stage.
+package tracestore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// TxTraces - traces of every transaction
+// blockNum_u64 + txIndex_u32 -> rlp([]Trace)
+const TxTraces = "TxTraces"
+
+func init() {
+	// The table is not (yet) known by erigon-lib, register it before any database is opened
+	kv.ChaindataTables = append(kv.ChaindataTables, TxTraces)
+	kv.ChaindataTablesCfg[TxTraces] = kv.TableCfgItem{}
+}
+
+// Kind of the trace
+const (
+	KindCall uint8 = iota
+	KindCreate
+	KindSuicide
+)
+
+// Trace is a compact form of a single Parity trace. Fields are reused between kinds:
+//  - call:    From calls To with Value, Input is call data, Output is returned data
+//  - create:  From creates To with Value, Input is init code, Output is deployed code
+//  - suicide: From self-destructs sending Value (balance) to To (refund address)
+// Output and GasUsed are kept for failed calls too, the error is applied when traces are rendered.
+type Trace struct {
+	Kind         uint8
+	CallType     uint8 // vm.CallType of the call
+	From         common.Address
+	To           common.Address
+	Value        *big.Int
+	Gas          uint64
+	GasUsed      uint64
+	Input        []byte
+	Output       []byte
+	Error        string
+	TraceAddress []uint64
+	Subtraces    uint64
+}
+
+func traceKey(blockNum uint64, txIndex int) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint64(k, blockNum)
+	binary.BigEndian.PutUint32(k[8:], uint32(txIndex))
+	return k
+}
+
+// WriteTxTraces stores traces of the transaction with the given index in the block
+func WriteTxTraces(tx kv.Putter, blockNum uint64, txIndex int, traces []Trace) error {
+	v, err := rlp.EncodeToBytes(traces)
+	if err != nil {
+		return fmt.Errorf("encode traces of tx %d in block %d: %w", txIndex, blockNum, err)
+	}
+	return tx.Put(TxTraces, traceKey(blockNum, txIndex), v)
+}
+
+// ReadTxTraces returns traces of the transaction with the given index in the block, nil if they are not stored
+func ReadTxTraces(tx kv.Getter, blockNum uint64, txIndex int) ([]Trace, error) {
+	v, err := tx.GetOne(TxTraces, traceKey(blockNum, txIndex))
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	var traces []Trace
+	if err = rlp.DecodeBytes(v, &traces); err != nil {
+		return nil, fmt.Errorf("decode traces of tx %d in block %d: %w", txIndex, blockNum, err)
+	}
+	return traces, nil
+}
+
+// ReadBlockTraces returns traces of all stored transactions of the block, indexed by transaction position
+func ReadBlockTraces(tx kv.Tx, blockNum uint64) ([][]Trace, error) {
+	c, err := tx.Cursor(TxTraces)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	var result [][]Trace
+	prefix := dbutils.EncodeBlockNumber(blockNum)
+	for k, v, err := c.Seek(prefix); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint64(k) != blockNum {
+			break
+		}
+		txIndex := int(binary.BigEndian.Uint32(k[8:]))
+		for len(result) <= txIndex {
+			result = append(result, nil)
+		}
+		if err = rlp.DecodeBytes(v, &result[txIndex]); err != nil {
+			return nil, fmt.Errorf("decode traces of tx %d in block %d: %w", txIndex, blockNum, err)
+		}
+	}
+	return result, nil
+}
+
+// TruncateTraces deletes traces of all blocks starting from the given one
+func TruncateTraces(tx kv.RwTx, fromBlock uint64) error {
+	c, err := tx.RwCursor(TxTraces)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(dbutils.EncodeBlockNumber(fromBlock)); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if err = c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneTraces deletes traces of all blocks before the given one
+func PruneTraces(tx kv.RwTx, toBlock uint64) error {
+	c, err := tx.RwCursor(TxTraces)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.First(); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint64(k) >= toBlock {
+			break
+		}
+		if err = c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
@@ -36,7 +37,9 @@ type assemblePayloadPOSFunc func(param *core.BlockProposerParametersPOS) (*types
 // 2.2.0 - add NodesInfo function
 // 3.0.0 - adding PoS interfaces
 // 3.1.0 - add Subscribe to logs
-var EthBackendAPIVersion = &types2.VersionReply{Major: 3, Minor: 1, Patch: 0}
+// 3.2.0 - add Engine V2 methods with withdrawals
+// 3.3.0 - add SyncStatus
+var EthBackendAPIVersion = &types2.VersionReply{Major: 3, Minor: 3, Patch: 0}
 
 const MaxPendingPayloads = 128
 
@@ -71,6 +74,7 @@ type EthBackend interface {
 	NetPeerCount() (uint64, error)
 	NodesInfo(limit int) (*remote.NodesInfoReply, error)
 	Peers(ctx context.Context) (*remote.PeersReply, error)
+	SyncStatusTracker() *stages.StatusTracker
 }
 
 // This is the status of a newly execute block.
@@ -279,6 +283,19 @@ func (s *EthBackendServer) stageLoopIsBusy() bool {
 
 // EngineNewPayloadV1 validates and possibly executes payload
 func (s *EthBackendServer) EngineNewPayloadV1(ctx context.Context, req *types2.ExecutionPayload) (*remote.EnginePayloadStatus, error) {
+	return s.engineNewPayload(ctx, req, nil)
+}
+
+// EngineNewPayloadV2 validates and possibly executes payload with withdrawals
+func (s *EthBackendServer) EngineNewPayloadV2(ctx context.Context, req *types2.ExecutionPayloadV2) (*remote.EnginePayloadStatus, error) {
+	if req.Payload == nil {
+		return nil, fmt.Errorf("missing execution payload")
+	}
+	return s.engineNewPayload(ctx, req.Payload, engineapi.ConvertWithdrawalsFromRpc(req.Withdrawals))
+}
+
+// engineNewPayload is shared by the V1 and V2 handlers; nil withdrawals mean a pre-Shanghai payload.
+func (s *EthBackendServer) engineNewPayload(ctx context.Context, req *types2.ExecutionPayload, withdrawals []*types.Withdrawal) (*remote.EnginePayloadStatus, error) {
 	log.Trace("[NewPayload] acquiring lock")
 	s.syncCond.L.Lock()
 	defer s.syncCond.L.Unlock()
@@ -317,6 +334,15 @@ func (s *EthBackendServer) EngineNewPayloadV1(ctx context.Context, req *types2.E
 		TxHash:      types.DeriveSha(types.RawTransactions(req.Transactions)),
 	}
 
+	if s.config.IsShanghai(header.Time) != (withdrawals != nil) {
+		log.Warn("[NewPayload] withdrawals presence doesn't match the fork", "height", header.Number, "shanghai", s.config.IsShanghai(header.Time))
+		return nil, fmt.Errorf("withdrawals must be provided if and only if Shanghai is active")
+	}
+	if withdrawals != nil {
+		withdrawalsHash := types.DeriveSha(types.Withdrawals(withdrawals))
+		header.WithdrawalsHash = &withdrawalsHash
+	}
+
 	blockHash := gointerfaces.ConvertH256ToHash(req.BlockHash)
 	if header.Hash() != blockHash {
 		log.Error("[NewPayload] invalid block hash", "stated", common.Hash(blockHash), "actual", header.Hash())
@@ -354,6 +380,7 @@ func (s *EthBackendServer) EngineNewPayloadV1(ctx context.Context, req *types2.E
 		Body: &types.RawBody{
 			Transactions: req.Transactions,
 			Uncles:       nil,
+			Withdrawals:  withdrawals,
 		},
 	})
 
@@ -369,6 +396,23 @@ func (s *EthBackendServer) EngineNewPayloadV1(ctx context.Context, req *types2.E
 
 // EngineGetPayloadV1 retrieves previously assembled payload (Validators only)
 func (s *EthBackendServer) EngineGetPayloadV1(ctx context.Context, req *remote.EngineGetPayloadRequest) (*types2.ExecutionPayload, error) {
+	payload, _, err := s.engineGetPayload(req.PayloadId)
+	return payload, err
+}
+
+// EngineGetPayloadV2 retrieves previously assembled payload together with its withdrawals (Validators only)
+func (s *EthBackendServer) EngineGetPayloadV2(ctx context.Context, req *remote.EngineGetPayloadRequest) (*types2.ExecutionPayloadV2, error) {
+	payload, withdrawals, err := s.engineGetPayload(req.PayloadId)
+	if err != nil {
+		return nil, err
+	}
+	return &types2.ExecutionPayloadV2{
+		Payload:     payload,
+		Withdrawals: engineapi.ConvertWithdrawalsToRpc(withdrawals),
+	}, nil
+}
+
+func (s *EthBackendServer) engineGetPayload(payloadId uint64) (*types2.ExecutionPayload, []*types.Withdrawal, error) {
 	// TODO(yperbasis): getPayload should stop block assembly if that's currently in fly
 
 	log.Trace("[GetPayload] acquiring lock")
@@ -377,16 +421,16 @@ func (s *EthBackendServer) EngineGetPayloadV1(ctx context.Context, req *remote.E
 	log.Trace("[GetPayload] lock acquired")
 
 	if !s.proposing {
-		return nil, fmt.Errorf("execution layer not running as a proposer. enable proposer by taking out the --proposer.disable flag on startup")
+		return nil, nil, fmt.Errorf("execution layer not running as a proposer. enable proposer by taking out the --proposer.disable flag on startup")
 	}
 
 	if s.config.TerminalTotalDifficulty == nil {
-		return nil, fmt.Errorf("not a proof-of-stake chain")
+		return nil, nil, fmt.Errorf("not a proof-of-stake chain")
 	}
 
-	payload, ok := s.pendingPayloads[req.PayloadId]
+	payload, ok := s.pendingPayloads[payloadId]
 	if !ok {
-		return nil, &UnknownPayload
+		return nil, nil, &UnknownPayload
 	}
 
 	// getPayload should stop the build process
@@ -404,10 +448,10 @@ func (s *EthBackendServer) EngineGetPayloadV1(ctx context.Context, req *remote.E
 
 	encodedTransactions, err := types.MarshalTransactionsBinary(block.Transactions())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &types2.ExecutionPayload{
+	payloadReply := &types2.ExecutionPayload{
 		ParentHash:    gointerfaces.ConvertHashToH256(block.Header().ParentHash),
 		Coinbase:      gointerfaces.ConvertAddressToH160(block.Header().Coinbase),
 		Timestamp:     block.Header().Time,
@@ -422,11 +466,29 @@ func (s *EthBackendServer) EngineGetPayloadV1(ctx context.Context, req *remote.E
 		BaseFeePerGas: baseFeeReply,
 		BlockHash:     gointerfaces.ConvertHashToH256(block.Header().Hash()),
 		Transactions:  encodedTransactions,
-	}, nil
+	}
+	return payloadReply, block.Withdrawals(), nil
 }
 
 // EngineForkChoiceUpdatedV1 either states new block head or request the assembling of a new block
 func (s *EthBackendServer) EngineForkChoiceUpdatedV1(ctx context.Context, req *remote.EngineForkChoiceUpdatedRequest) (*remote.EngineForkChoiceUpdatedReply, error) {
+	return s.engineForkChoiceUpdated(ctx, req.ForkchoiceState, req.PayloadAttributes, nil)
+}
+
+// EngineForkChoiceUpdatedV2 is EngineForkChoiceUpdatedV1 with withdrawals in the payload attributes
+func (s *EthBackendServer) EngineForkChoiceUpdatedV2(ctx context.Context, req *remote.EngineForkChoiceUpdatedRequestV2) (*remote.EngineForkChoiceUpdatedReply, error) {
+	if req.PayloadAttributes == nil {
+		return s.engineForkChoiceUpdated(ctx, req.ForkchoiceState, nil, nil)
+	}
+	if req.PayloadAttributes.Attributes == nil {
+		return nil, fmt.Errorf("missing payload attributes")
+	}
+	withdrawals := engineapi.ConvertWithdrawalsFromRpc(req.PayloadAttributes.Withdrawals)
+	return s.engineForkChoiceUpdated(ctx, req.ForkchoiceState, req.PayloadAttributes.Attributes, withdrawals)
+}
+
+// engineForkChoiceUpdated is shared by the V1 and V2 handlers; nil withdrawals mean pre-Shanghai payload attributes.
+func (s *EthBackendServer) engineForkChoiceUpdated(ctx context.Context, forkchoiceState *remote.EngineForkChoiceState, payloadAttributes *remote.EnginePayloadAttributes, withdrawals []*types.Withdrawal) (*remote.EngineForkChoiceUpdatedReply, error) {
 	log.Trace("[ForkChoiceUpdated] acquiring lock")
 	s.syncCond.L.Lock()
 	defer s.syncCond.L.Unlock()
@@ -437,9 +499,9 @@ func (s *EthBackendServer) EngineForkChoiceUpdatedV1(ctx context.Context, req *r
 	}
 
 	forkChoice := engineapi.ForkChoiceMessage{
-		HeadBlockHash:      gointerfaces.ConvertH256ToHash(req.ForkchoiceState.HeadBlockHash),
-		SafeBlockHash:      gointerfaces.ConvertH256ToHash(req.ForkchoiceState.SafeBlockHash),
-		FinalizedBlockHash: gointerfaces.ConvertH256ToHash(req.ForkchoiceState.FinalizedBlockHash),
+		HeadBlockHash:      gointerfaces.ConvertH256ToHash(forkchoiceState.HeadBlockHash),
+		SafeBlockHash:      gointerfaces.ConvertH256ToHash(forkchoiceState.SafeBlockHash),
+		FinalizedBlockHash: gointerfaces.ConvertH256ToHash(forkchoiceState.FinalizedBlockHash),
 	}
 
 	tx1, err := s.db.BeginRo(ctx)
@@ -481,7 +543,7 @@ func (s *EthBackendServer) EngineForkChoiceUpdatedV1(ctx context.Context, req *r
 	}
 
 	// No need for payload building
-	if req.PayloadAttributes == nil || payloadStatus.Status != remote.EngineStatus_VALID {
+	if payloadAttributes == nil || payloadStatus.Status != remote.EngineStatus_VALID {
 		return &remote.EngineForkChoiceUpdatedReply{PayloadStatus: convertPayloadStatus(&payloadStatus)}, nil
 	}
 
@@ -507,11 +569,15 @@ func (s *EthBackendServer) EngineForkChoiceUpdatedV1(ctx context.Context, req *r
 		return nil, fmt.Errorf("unexpected head hash: %x vs %x", headHeader.Hash(), forkChoice.HeadBlockHash)
 	}
 
-	emptyHeader := core.MakeEmptyHeader(headHeader, s.config, req.PayloadAttributes.Timestamp, nil)
-	emptyHeader.Coinbase = gointerfaces.ConvertH160toAddress(req.PayloadAttributes.SuggestedFeeRecipient)
-	emptyHeader.MixDigest = gointerfaces.ConvertH256ToHash(req.PayloadAttributes.PrevRandao)
+	emptyHeader := core.MakeEmptyHeader(headHeader, s.config, payloadAttributes.Timestamp, nil)
+	emptyHeader.Coinbase = gointerfaces.ConvertH160toAddress(payloadAttributes.SuggestedFeeRecipient)
+	emptyHeader.MixDigest = gointerfaces.ConvertH256ToHash(payloadAttributes.PrevRandao)
+
+	if s.config.IsShanghai(emptyHeader.Time) != (withdrawals != nil) {
+		return nil, fmt.Errorf("withdrawals must be provided if and only if Shanghai is active")
+	}
 
-	s.pendingPayloads[s.payloadId] = &pendingPayload{block: types.NewBlock(emptyHeader, nil, nil, nil)}
+	s.pendingPayloads[s.payloadId] = &pendingPayload{block: types.NewBlockWithWithdrawals(emptyHeader, nil, nil, nil, withdrawals)}
 
 	log.Trace("[ForkChoiceUpdated] unpause assemble process")
 	s.syncCond.Broadcast()
@@ -583,6 +649,7 @@ func (s *EthBackendServer) StartProposer() {
 				Timestamp:             blockToBuild.Header().Time,
 				PrevRandao:            blockToBuild.MixDigest(),
 				SuggestedFeeRecipient: blockToBuild.Header().Coinbase,
+				Withdrawals:           blockToBuild.Withdrawals(),
 			}
 
 			log.Trace("[Proposer] starting assembling...")
@@ -0,0 +1,50 @@
+package privateapi
+
+import (
+	"context"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+// SyncStatus - progress of stages from DB, throughput and timings of running sync loop
+func (s *EthBackendServer) SyncStatus(ctx context.Context, _ *remote.SyncStatusRequest) (*remote.SyncStatusReply, error) {
+	tx, err := s.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	var tracker *stages.StatusTracker
+	if s.eth != nil {
+		tracker = s.eth.SyncStatusTracker()
+	}
+	status, err := stages.ReadSyncStatus(tx, tracker)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &remote.SyncStatusReply{
+		CurrentBlock: status.CurrentBlock,
+		HighestBlock: status.HighestBlock,
+		CurrentStage: string(status.CurrentStage),
+		Stages:       make([]*remote.StageSyncStatus, len(status.Stages)),
+		Throughput: &remote.SyncThroughput{
+			Block:           status.Throughput.Block,
+			BlocksPerSecond: status.Throughput.BlocksPerSecond,
+			TxsPerSecond:    status.Throughput.TxsPerSecond,
+			GasPerSecond:    status.Throughput.GasPerSecond,
+		},
+		LastCycle: make([]*remote.StageTiming, len(status.LastCycle)),
+		Eta:       status.ETA,
+	}
+	if !status.Throughput.MeasuredAt.IsZero() {
+		reply.Throughput.MeasuredAt = uint64(status.Throughput.MeasuredAt.Unix())
+	}
+	for i, st := range status.Stages {
+		reply.Stages[i] = &remote.StageSyncStatus{Stage: string(st.Stage), Progress: st.Progress, PruneProgress: st.PruneProgress}
+	}
+	for i, t := range status.LastCycle {
+		reply.LastCycle[i] = &remote.StageTiming{Stage: string(t.Stage), Unwind: t.Unwind, Prune: t.Prune, Took: t.Took}
+	}
+	return reply, nil
+}
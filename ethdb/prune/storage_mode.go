@@ -21,9 +21,17 @@ var DefaultMode = Mode{
 }
 
 type Experiments struct {
-	TEVM bool
+	TEVM        bool
+	Traces      bool // TraceIndex stage, pruned together with CallTraces
+	Appearances bool // AddressAppearances stage, pruned together with CallTraces
 }
 
+// StorageModeTraces - key in DatabaseInfo, storing whether Parity-style traces are stored by the TraceIndex stage
+var StorageModeTraces = []byte("smTraces")
+
+// StorageModeAppearances - key in DatabaseInfo, storing whether the AddressAppearances stage builds its index
+var StorageModeAppearances = []byte("smAppearances")
+
 func FromCli(flags string, exactHistory, exactReceipts, exactTxIndex, exactCallTraces,
 	beforeH, beforeR, beforeT, beforeC uint64, experiments []string) (Mode, error) {
 	mode := DefaultMode
@@ -76,6 +84,10 @@ func FromCli(flags string, exactHistory, exactReceipts, exactTxIndex, exactCallT
 		switch ex {
 		case "tevm":
 			mode.Experiments.TEVM = true
+		case "traces":
+			mode.Experiments.Traces = true
+		case "appearances":
+			mode.Experiments.Appearances = true
 		case "":
 			// skip
 		default:
@@ -128,6 +140,18 @@ func Get(db kv.Getter) (Mode, error) {
 	}
 	prune.Experiments.TEVM = len(v) == 1 && v[0] == 1
 
+	v, err = db.GetOne(kv.DatabaseInfo, StorageModeTraces)
+	if err != nil {
+		return prune, err
+	}
+	prune.Experiments.Traces = len(v) == 1 && v[0] == 1
+
+	v, err = db.GetOne(kv.DatabaseInfo, StorageModeAppearances)
+	if err != nil {
+		return prune, err
+	}
+	prune.Experiments.Appearances = len(v) == 1 && v[0] == 1
+
 	return prune, nil
 }
 
@@ -223,6 +247,12 @@ func (m Mode) String() string {
 	if m.Experiments.TEVM {
 		long += " --experiments.tevm=enabled"
 	}
+	if m.Experiments.Traces {
+		long += " --experiments.traces=enabled"
+	}
+	if m.Experiments.Appearances {
+		long += " --experiments.appearances=enabled"
+	}
 	return short + long
 }
 
@@ -256,6 +286,16 @@ func Override(db kv.RwTx, sm Mode) error {
 		return err
 	}
 
+	err = setMode(db, StorageModeTraces, sm.Experiments.Traces)
+	if err != nil {
+		return err
+	}
+
+	err = setMode(db, StorageModeAppearances, sm.Experiments.Appearances)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -307,6 +347,16 @@ func setIfNotExist(db kv.GetPut, pm Mode) error {
 		return err
 	}
 
+	err = setModeOnEmpty(db, StorageModeTraces, pm.Experiments.Traces)
+	if err != nil {
+		return err
+	}
+
+	err = setModeOnEmpty(db, StorageModeAppearances, pm.Experiments.Appearances)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,302 @@
+package ethapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/changeset"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+// hashedStateOverlay - read-only view of kv.HashedAccounts and kv.HashedStorage as they were at some historical block.
+// Values changed after that block are taken from AccountChangeSet/StorageChangeSet and kept in memory,
+// everything else is read from underlying transaction. Only cursor methods used by trie.FlatDBTrieLoader are overridden.
+type hashedStateOverlay struct {
+	kv.Tx
+	accounts map[string][]byte // addrHash -> account encoded for storage, empty value means "account didn't exist"
+	storage  map[string][]byte // addrHash+incarnation+keyHash -> value, empty value means "no such slot"
+}
+
+func newHashedStateOverlay(tx kv.Tx) *hashedStateOverlay {
+	return &hashedStateOverlay{Tx: tx, accounts: map[string][]byte{}, storage: map[string][]byte{}}
+}
+
+// rewind - collects values of all keys changed in blocks [from, to), oldest appeared value wins
+func (o *hashedStateOverlay) rewind(from, to uint64, quit <-chan struct{}) error {
+	if err := changeset.ForRange(o.Tx, kv.AccountChangeSet, from, to, func(_ uint64, k, v []byte) error {
+		if err := libcommon.Stopped(quit); err != nil {
+			return err
+		}
+		addrHash, err := common.HashData(k)
+		if err != nil {
+			return err
+		}
+		if _, ok := o.accounts[string(addrHash[:])]; ok {
+			return nil
+		}
+		if v, err = o.restoreCodeHash(addrHash[:], v); err != nil {
+			return err
+		}
+		o.accounts[string(addrHash[:])] = v
+		return nil
+	}); err != nil {
+		return err
+	}
+	return changeset.ForRange(o.Tx, kv.StorageChangeSet, from, to, func(_ uint64, k, v []byte) error {
+		if err := libcommon.Stopped(quit); err != nil {
+			return err
+		}
+		addrHash, err := common.HashData(k[:common.AddressLength])
+		if err != nil {
+			return err
+		}
+		keyHash, err := common.HashData(k[common.AddressLength+common.IncarnationLength:])
+		if err != nil {
+			return err
+		}
+		inc := binary.BigEndian.Uint64(k[common.AddressLength:])
+		newK := dbutils.GenerateCompositeStorageKey(addrHash, inc, keyHash)
+		if _, ok := o.storage[string(newK)]; ok {
+			return nil
+		}
+		o.storage[string(newK)] = common.CopyBytes(v)
+		return nil
+	})
+}
+
+// restoreCodeHash - change sets don't store code hash of contracts, it has to be read from kv.ContractCode
+func (o *hashedStateOverlay) restoreCodeHash(addrHash, v []byte) ([]byte, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	var acc accounts.Account
+	if err := acc.DecodeForStorage(v); err != nil {
+		return nil, err
+	}
+	if !(acc.Incarnation > 0 && acc.IsEmptyCodeHash()) {
+		return common.CopyBytes(v), nil
+	}
+	codeHash, err := o.Tx.GetOne(kv.ContractCode, dbutils.GenerateStoragePrefix(addrHash, acc.Incarnation))
+	if err != nil {
+		return nil, fmt.Errorf("adjusting codeHash for ks %x, inc %d: %w", addrHash, acc.Incarnation, err)
+	}
+	copy(acc.CodeHash[:], codeHash)
+	value := make([]byte, acc.EncodingLengthForStorage())
+	acc.EncodeForStorage(value)
+	return value, nil
+}
+
+// changedKeys - hashed keys of all accounts and storage slots which differ from current state
+func (o *hashedStateOverlay) changedKeys() [][]byte {
+	keys := make([][]byte, 0, len(o.accounts)+len(o.storage))
+	for k := range o.accounts {
+		keys = append(keys, []byte(k))
+	}
+	for k := range o.storage {
+		keys = append(keys, []byte(k))
+	}
+	return keys
+}
+
+func (o *hashedStateOverlay) readAccount(addrHash common.Hash) (*accounts.Account, error) {
+	v, ok := o.accounts[string(addrHash[:])]
+	if !ok {
+		var err error
+		if v, err = o.Tx.GetOne(kv.HashedAccounts, addrHash[:]); err != nil {
+			return nil, err
+		}
+	}
+	if len(v) == 0 {
+		return nil, nil
+	}
+	var acc accounts.Account
+	if err := acc.DecodeForStorage(v); err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+func (o *hashedStateOverlay) Cursor(bucket string) (kv.Cursor, error) {
+	c, err := o.Tx.Cursor(bucket)
+	if err != nil || bucket != kv.HashedAccounts || len(o.accounts) == 0 {
+		return c, err
+	}
+	items := make([]overlayItem, 0, len(o.accounts))
+	for k, v := range o.accounts {
+		items = append(items, overlayItem{k: []byte(k), v: v, deleted: len(v) == 0})
+	}
+	sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i].k, items[j].k) < 0 })
+	return &overlayCursor{Cursor: c, m: overlayMerge{items: items}}, nil
+}
+
+func (o *hashedStateOverlay) CursorDupSort(bucket string) (kv.CursorDupSort, error) {
+	c, err := o.Tx.CursorDupSort(bucket)
+	if err != nil || bucket != kv.HashedStorage || len(o.storage) == 0 {
+		return c, err
+	}
+	byAccount := map[string][]overlayItem{}
+	for k, v := range o.storage {
+		key := []byte(k)
+		accWithInc := string(key[:common.HashLength+common.IncarnationLength])
+		keyHash := key[common.HashLength+common.IncarnationLength:]
+		byAccount[accWithInc] = append(byAccount[accWithInc], overlayItem{k: keyHash, v: append(common.CopyBytes(keyHash), v...), deleted: len(v) == 0})
+	}
+	for _, items := range byAccount {
+		sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i].k, items[j].k) < 0 })
+	}
+	return &overlayDupCursor{CursorDupSort: c, byAccount: byAccount}, nil
+}
+
+type overlayItem struct {
+	k, v    []byte
+	deleted bool
+}
+
+// overlayMerge - merges sorted overlay items with stream of underlying cursor, overlay items win on equal keys
+type overlayMerge struct {
+	items               []overlayItem
+	i                   int
+	fromDB, fromOverlay bool // which source produced last returned pair
+}
+
+// next - returns overlay item to be returned (nil means underlying cursor's pair), dbK - key of underlying cursor's pair
+// (nil when exhausted), skipDB - advances underlying cursor when its key is shadowed by overlay item
+func (m *overlayMerge) next(dbK []byte, skipDB func() ([]byte, error)) (*overlayItem, error) {
+	for {
+		m.fromDB, m.fromOverlay = false, false
+		if m.i >= len(m.items) {
+			m.fromDB = dbK != nil
+			return nil, nil
+		}
+		cmp := -1
+		if dbK != nil {
+			cmp = bytes.Compare(m.items[m.i].k, dbK)
+		}
+		if cmp > 0 {
+			m.fromDB = true
+			return nil, nil
+		}
+		if cmp == 0 {
+			var err error
+			if dbK, err = skipDB(); err != nil {
+				return nil, err
+			}
+		}
+		if !m.items[m.i].deleted {
+			m.fromOverlay = true
+			return &m.items[m.i], nil
+		}
+		m.i++
+	}
+}
+
+func (m *overlayMerge) seek(seek []byte) {
+	m.i = sort.Search(len(m.items), func(i int) bool { return bytes.Compare(m.items[i].k, seek) >= 0 })
+}
+
+type overlayCursor struct {
+	kv.Cursor
+	m    overlayMerge
+	k, v []byte // current pair of underlying cursor
+}
+
+func (c *overlayCursor) Seek(seek []byte) ([]byte, []byte, error) {
+	var err error
+	if c.k, c.v, err = c.Cursor.Seek(seek); err != nil {
+		return nil, nil, err
+	}
+	c.m.seek(seek)
+	return c.current()
+}
+
+func (c *overlayCursor) Next() ([]byte, []byte, error) {
+	var err error
+	if c.m.fromDB {
+		if c.k, c.v, err = c.Cursor.Next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.m.fromOverlay {
+		c.m.i++
+	}
+	return c.current()
+}
+
+func (c *overlayCursor) current() ([]byte, []byte, error) {
+	item, err := c.m.next(c.k, func() ([]byte, error) {
+		var err error
+		c.k, c.v, err = c.Cursor.Next()
+		return c.k, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if item != nil {
+		return item.k, item.v, nil
+	}
+	return c.k, c.v, nil
+}
+
+// overlayDupCursor - values of kv.HashedStorage are keyHash+value, overlay items are compared by keyHash
+type overlayDupCursor struct {
+	kv.CursorDupSort
+	byAccount map[string][]overlayItem
+	m         overlayMerge
+	k, v      []byte // current pair of underlying cursor
+}
+
+func (c *overlayDupCursor) SeekBothRange(key, value []byte) ([]byte, error) {
+	var err error
+	c.k = key
+	if c.v, err = c.CursorDupSort.SeekBothRange(key, value); err != nil {
+		return nil, err
+	}
+	c.m = overlayMerge{items: c.byAccount[string(key)]}
+	c.m.seek(value)
+	_, v, err := c.current()
+	return v, err
+}
+
+func (c *overlayDupCursor) NextDup() ([]byte, []byte, error) {
+	var err error
+	if c.m.fromDB {
+		if _, c.v, err = c.CursorDupSort.NextDup(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.m.fromOverlay {
+		c.m.i++
+	}
+	return c.current()
+}
+
+func (c *overlayDupCursor) subKey() []byte {
+	if c.v == nil {
+		return nil
+	}
+	return c.v[:common.HashLength]
+}
+
+func (c *overlayDupCursor) current() ([]byte, []byte, error) {
+	item, err := c.m.next(c.subKey(), func() ([]byte, error) {
+		var err error
+		_, c.v, err = c.CursorDupSort.NextDup()
+		return c.subKey(), err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if item != nil {
+		return c.k, item.v, nil
+	}
+	if c.v == nil {
+		return nil, nil, nil
+	}
+	return c.k, c.v, nil
+}
@@ -363,6 +363,11 @@ func (t *UDPv5) RequestENR(n *enode.Node) (*enode.Node, error) {
 	return nodes[0], nil
 }
 
+// FindNode calls FINDNODE on a node and returns the nodes it knows at the given log-distances from itself.
+func (t *UDPv5) FindNode(n *enode.Node, distances []uint) ([]*enode.Node, error) {
+	return t.findnode(n, distances)
+}
+
 // findnode calls FINDNODE on a node and waits for responses.
 func (t *UDPv5) findnode(n *enode.Node, distances []uint) ([]*enode.Node, error) {
 	resp := t.call(n, v5wire.NodesMsg, &v5wire.Findnode{Distances: distances})
@@ -64,7 +64,8 @@ type handler struct {
 	log            log.Logger
 	allowSubscribe bool
 
-	allowList AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
+	allowList AllowList    // a list of explicitly allowed methods, if empty -- everything is allowed
+	limiter   *connLimiter // charges the cost of calls, nil -- no limits
 
 	subLock             sync.Mutex
 	serverSubs          map[ID]*Subscription
@@ -76,7 +77,7 @@ type callProc struct {
 	notifiers []*Notifier
 }
 
-func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, allowList AllowList, maxBatchConcurrency uint) *handler {
+func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, allowList AllowList, limiter *connLimiter, maxBatchConcurrency uint) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 	h := &handler{
 		reg:            reg,
@@ -90,6 +91,7 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 		serverSubs:     make(map[ID]*Subscription),
 		log:            log.Root(),
 		allowList:      allowList,
+		limiter:        limiter,
 
 		maxBatchConcurrency: maxBatchConcurrency,
 	}
@@ -370,6 +372,9 @@ func (h *handler) isMethodAllowedByGranularControl(method string) bool {
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter.Stream) *jsonrpcMessage {
 	if msg.isSubscribe() {
+		if err := h.limiter.allow(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
 		return h.handleSubscribe(cp, msg, stream)
 	}
 	var callb *callback
@@ -381,6 +386,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if callb != h.unsubscribeCb {
+		if err := h.limiter.allow(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
@@ -250,6 +250,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if origin := r.Header.Get("Origin"); origin != "" {
 		ctx = context.WithValue(ctx, "Origin", origin)
 	}
+	if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+		ctx = context.WithValue(ctx, APIKeyHeader, apiKey)
+	}
 
 	w.Header().Set("content-type", contentType)
 	codec := newHTTPServerConn(r, w)
@@ -257,6 +260,25 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.serveSingleRequest(ctx, codec)
 }
 
+// LimitedHandler wraps a HTTP handler served next to JSON-RPC (e.g. GraphQL) with the allow list
+// and the rate limits of the server, every request is charged as one call of the given method.
+func (s *Server) LimitedHandler(method string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.methodAllowList) != 0 {
+			if _, ok := s.methodAllowList[method]; !ok {
+				http.Error(w, fmt.Sprintf("%s is not allowed", method), http.StatusForbidden)
+				return
+			}
+		}
+		limiter := s.rateLimiter.requestLimiter(r.Header.Get(APIKeyHeader), r.RemoteAddr)
+		if err := limiter.allow(method); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // validateRequest returns a non-zero response code and error message if the
 // request is invalid.
 func validateRequest(r *http.Request) (int, error) {
@@ -0,0 +1,196 @@
+package rpc
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+// APIKeyHeader - HTTP header of requests (and websocket upgrade requests) carrying the API key
+const APIKeyHeader = "X-Api-Key"
+
+// RateLimit - token bucket refilled with Rate cost units per second, holding at most Burst units
+type RateLimit struct {
+	Rate  float64 `json:"rate"` // 0 - no limit
+	Burst float64 `json:"burst"`
+}
+
+// RateLimits configures how much of the server a single client may use. Each call takes the cost of its method
+// from the client's bucket: the API key's one if the request carries a known key, otherwise the bucket of the
+// connection (websocket, IPC) or of the remote IP address (HTTP).
+type RateLimits struct {
+	Connection RateLimit            `json:"connection"`
+	Keys       map[string]RateLimit `json:"keys"`
+	Costs      map[string]uint64    `json:"costs"` // cost of methods not listed is 1
+}
+
+// maxAddrBuckets - buckets of HTTP clients above this amount are forgotten, the least recently used first
+const maxAddrBuckets = 10_000
+
+// rateLimiter hands out buckets according to RateLimits, nil rateLimiter doesn't limit anything
+type rateLimiter struct {
+	limits RateLimits
+
+	lock  sync.Mutex
+	keys  map[string]*tokenBucket // by API key, as many as configured
+	addrs *simplelru.LRU          // by remote IP
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	if limits.Connection.Rate == 0 && len(limits.Keys) == 0 {
+		return nil
+	}
+	addrs, err := simplelru.NewLRU(maxAddrBuckets, nil)
+	if err != nil {
+		panic(err)
+	}
+	return &rateLimiter{limits: limits, keys: map[string]*tokenBucket{}, addrs: addrs}
+}
+
+// connLimiter returns the limiter of a persistent connection
+func (l *rateLimiter) connLimiter(apiKey string) *connLimiter {
+	if l == nil {
+		return nil
+	}
+	if limit, ok := l.limits.Keys[apiKey]; ok && apiKey != "" {
+		return l.newConnLimiter(l.keyBucket(apiKey, limit))
+	}
+	if l.limits.Connection.Rate == 0 {
+		return nil
+	}
+	return l.newConnLimiter(newTokenBucket(l.limits.Connection))
+}
+
+// requestLimiter returns the limiter of a single HTTP request, sharing the bucket with other requests of the client
+func (l *rateLimiter) requestLimiter(apiKey, remoteAddr string) *connLimiter {
+	if l == nil {
+		return nil
+	}
+	if limit, ok := l.limits.Keys[apiKey]; ok && apiKey != "" {
+		return l.newConnLimiter(l.keyBucket(apiKey, limit))
+	}
+	if l.limits.Connection.Rate == 0 {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return l.newConnLimiter(l.addrBucket(host))
+}
+
+func (l *rateLimiter) newConnLimiter(bucket *tokenBucket) *connLimiter {
+	if bucket == nil {
+		return nil
+	}
+	return &connLimiter{costs: l.limits.Costs, bucket: bucket}
+}
+
+// keyBucket returns the bucket shared by all connections and requests with the API key
+func (l *rateLimiter) keyBucket(apiKey string, limit RateLimit) *tokenBucket {
+	if limit.Rate == 0 {
+		return nil
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if b, ok := l.keys[apiKey]; ok {
+		return b
+	}
+	b := newTokenBucket(limit)
+	l.keys[apiKey] = b
+	return b
+}
+
+// addrBucket returns the bucket shared by the HTTP requests from the host
+func (l *rateLimiter) addrBucket(host string) *tokenBucket {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if b, ok := l.addrs.Get(host); ok {
+		return b.(*tokenBucket)
+	}
+	b := newTokenBucket(l.limits.Connection)
+	l.addrs.Add(host, b)
+	return b
+}
+
+// connLimiter charges calls of a single connection, nil connLimiter allows everything
+type connLimiter struct {
+	costs  map[string]uint64
+	bucket *tokenBucket
+}
+
+func (l *connLimiter) cost(method string) uint64 {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	return 1
+}
+
+// allow takes the cost of the method from the bucket, or returns an error if there are not enough tokens
+func (l *connLimiter) allow(method string) error {
+	if l == nil {
+		return nil
+	}
+	cost := l.cost(method)
+	if wait, ok := l.bucket.take(float64(cost), time.Now()); !ok {
+		rateLimitedCounter(method).Inc()
+		return &rateLimitError{method: method, retryAfter: wait}
+	}
+	rateLimitCostCounter(method).Add(int(cost))
+	return nil
+}
+
+type tokenBucket struct {
+	limit RateLimit
+
+	lock    sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst < limit.Rate {
+		limit.Burst = limit.Rate
+	}
+	return &tokenBucket{limit: limit, tokens: limit.Burst, updated: time.Now()}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(b.limit.Burst, b.tokens+elapsed.Seconds()*b.limit.Rate)
+		b.updated = now
+	}
+}
+
+// take returns false and the time to wait for enough tokens if the bucket holds less than cost
+func (b *tokenBucket) take(cost float64, now time.Time) (time.Duration, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill(now)
+	if b.tokens < cost {
+		if cost > b.limit.Burst {
+			return 0, false // never fits
+		}
+		return time.Duration((cost - b.tokens) / b.limit.Rate * float64(time.Second)), false
+	}
+	b.tokens -= cost
+	return 0, true
+}
+
+type rateLimitError struct {
+	method     string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) ErrorCode() int { return -32005 } // limit exceeded, EIP-1474
+
+func (e *rateLimitError) Error() string {
+	if e.retryAfter == 0 {
+		return fmt.Sprintf("rate limit exceeded: cost of %s is greater than the burst limit", e.method)
+	}
+	return fmt.Sprintf("rate limit exceeded: retry %s in %v", e.method, e.retryAfter.Round(time.Millisecond))
+}
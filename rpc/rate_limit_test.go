@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireRateLimited(t *testing.T, err error) {
+	t.Helper()
+	require.Error(t, err)
+	rpcErr, ok := err.(Error)
+	require.True(t, ok, "unexpected error %v", err)
+	require.Equal(t, -32005, rpcErr.ErrorCode())
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 2, Burst: 4})
+	now := b.updated
+	_, ok := b.take(3, now)
+	require.True(t, ok)
+	wait, ok := b.take(3, now)
+	require.False(t, ok)
+	require.Equal(t, time.Second, wait)
+	_, ok = b.take(3, now.Add(time.Second))
+	require.True(t, ok)
+	_, ok = b.take(5, now.Add(time.Hour))
+	require.False(t, ok, "cost greater than burst never fits")
+	_, ok = b.take(4, now.Add(time.Hour))
+	require.True(t, ok, "refilled up to burst")
+}
+
+func TestRateLimiterAddrBuckets(t *testing.T) {
+	l := newRateLimiter(RateLimits{Connection: RateLimit{Rate: 0.001, Burst: 1}})
+	first := l.requestLimiter("", "10.0.0.1:1234")
+	require.NoError(t, first.allow("test_echo"))
+	for i := 0; i < maxAddrBuckets; i++ {
+		l.requestLimiter("", fmt.Sprintf("host%d:80", i))
+		if i == maxAddrBuckets/2 {
+			// the bucket of a recent client stays, also with another port
+			requireRateLimited(t, l.requestLimiter("", "10.0.0.1:5678").allow("test_echo"))
+		}
+	}
+	require.Equal(t, maxAddrBuckets, l.addrs.Len())
+	require.True(t, l.addrs.Contains("10.0.0.1"))
+	require.False(t, l.addrs.Contains("host0"), "the least recently used bucket is forgotten")
+}
+
+func TestRateLimitsPerConnection(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetRateLimits(RateLimits{
+		Connection: RateLimit{Rate: 0.001, Burst: 3},
+		Costs:      map[string]uint64{"test_echo": 2},
+	})
+
+	client := DialInProc(server)
+	defer client.Close()
+	var result echoResult
+	require.NoError(t, client.Call(&result, "test_echo", "x", 1))
+	requireRateLimited(t, client.Call(&result, "test_echo", "x", 1))
+	require.NoError(t, client.Call(nil, "test_noArgsRets"))
+	requireRateLimited(t, client.Call(nil, "test_noArgsRets"))
+
+	// a new connection gets its own bucket
+	other := DialInProc(server)
+	defer other.Close()
+	require.NoError(t, other.Call(&result, "test_echo", "x", 1))
+}
+
+func TestRateLimitsHTTP(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetRateLimits(RateLimits{
+		Connection: RateLimit{Rate: 0.001, Burst: 2},
+		Keys:       map[string]RateLimit{"secret": {Rate: 0.001, Burst: 3}},
+	})
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	// every HTTP request is a new connection, the bucket is shared by the remote address
+	client, err := DialHTTP(httpsrv.URL)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Call(nil, "test_noArgsRets"))
+	require.NoError(t, client.Call(nil, "test_noArgsRets"))
+	requireRateLimited(t, client.Call(nil, "test_noArgsRets"))
+
+	keyClient, err := DialHTTP(httpsrv.URL)
+	require.NoError(t, err)
+	defer keyClient.Close()
+	keyClient.SetHeader(APIKeyHeader, "secret")
+	for i := 0; i < 3; i++ {
+		require.NoError(t, keyClient.Call(nil, "test_noArgsRets"))
+	}
+	requireRateLimited(t, keyClient.Call(nil, "test_noArgsRets"))
+}
+
+func TestLimitedHandler(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetRateLimits(RateLimits{
+		Connection: RateLimit{Rate: 0.001, Burst: 3},
+		Costs:      map[string]uint64{"graphql": 2},
+	})
+	handler := server.LimitedHandler("graphql", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func() int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+		return w.Code
+	}
+	require.Equal(t, http.StatusOK, serve())
+	require.Equal(t, http.StatusTooManyRequests, serve())
+
+	server.SetAllowList(AllowList{"test_echo": struct{}{}})
+	require.Equal(t, http.StatusForbidden, serve())
+}
@@ -44,6 +44,7 @@ const (
 type Server struct {
 	services        serviceRegistry
 	methodAllowList AllowList
+	rateLimiter     *rateLimiter
 	idgen           func() ID
 	run             int32
 	codecs          mapset.Set
@@ -66,6 +67,11 @@ func (s *Server) SetAllowList(allowList AllowList) {
 	s.methodAllowList = allowList
 }
 
+// SetRateLimits sets limits on the cost of calls a single client may make
+func (s *Server) SetRateLimits(limits RateLimits) {
+	s.rateLimiter = newRateLimiter(limits)
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -91,7 +97,11 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services)
+	var apiKey string
+	if kc, ok := codec.(interface{ apiKey() string }); ok {
+		apiKey = kc.apiKey()
+	}
+	c := initClient(codec, s.idgen, &s.services, s.rateLimiter.connLimiter(apiKey))
 	<-codec.closed()
 	c.Close()
 }
@@ -105,7 +115,10 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency)
+	apiKey, _ := ctx.Value(APIKeyHeader).(string)
+	remote, _ := ctx.Value("remote").(string)
+	limiter := s.rateLimiter.requestLimiter(apiKey, remote)
+	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, limiter, s.batchConcurrency)
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
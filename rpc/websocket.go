@@ -64,6 +64,7 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 			return
 		}
 		codec := newWebsocketCodec(conn)
+		codec.(*websocketCodec).key = r.Header.Get(APIKeyHeader)
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -239,6 +240,7 @@ func wsClientHeaders(endpoint, origin string) (string, http.Header, error) {
 type websocketCodec struct {
 	*jsonCodec
 	conn *websocket.Conn
+	key  string // API key of the upgrade request
 
 	wg        sync.WaitGroup
 	pingReset chan struct{}
@@ -256,6 +258,10 @@ func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
 	return wc
 }
 
+func (wc *websocketCodec) apiKey() string {
+	return wc.key
+}
+
 func (wc *websocketCodec) close() {
 	wc.jsonCodec.close()
 	wc.wg.Wait()
@@ -64,12 +64,16 @@ var DefaultFlags = []cli.Flag{
 	utils.RpcBatchConcurrencyFlag,
 	utils.DBReadConcurrencyFlag,
 	utils.RpcAccessListFlag,
+	utils.RpcRateLimitsFlag,
+	utils.GraphQLEnabledFlag,
 	utils.RpcTraceCompatFlag,
 	utils.RpcGasCapFlag,
 	utils.StarknetGrpcAddressFlag,
 	utils.TevmFlag,
 	utils.TxpoolApiAddrFlag,
 	utils.TraceMaxtracesFlag,
+	utils.RpcLogsMaxRangeFlag,
+	utils.RpcLogsMaxResultsFlag,
 
 	utils.SnapshotKeepBlocksFlag,
 	utils.DbPageSizeFlag,
@@ -77,6 +81,7 @@ var DefaultFlags = []cli.Flag{
 	utils.TorrentMaxPeersFlag,
 	utils.TorrentConnsPerFileFlag,
 	utils.TorrentDownloadSlotsFlag,
+	utils.TorrentWebSeedsFlag,
 	utils.TorrentUploadRateFlag,
 	utils.TorrentDownloadRateFlag,
 	utils.TorrentVerbosityFlag,
@@ -123,6 +128,7 @@ var DefaultFlags = []cli.Flag{
 	HealthCheckFlag,
 	utils.HeimdallURLFlag,
 	utils.WithoutHeimdallFlag,
+	utils.HeimdallMockFlag,
 	utils.EthStatsURLFlag,
 	utils.OverrideTerminalTotalDifficulty,
 	utils.OverrideMergeForkBlock,
@@ -62,7 +62,7 @@ var (
 	h - prune history (ChangeSets, HistoryIndices - used by historical state access, like eth_getStorageAt, eth_getBalanceAt, debug_traceTransaction, trace_block, trace_transaction, etc.)
 	r - prune receipts (Receipts, Logs, LogTopicIndex, LogAddressIndex - used by eth_getLogs and similar RPC methods)
 	t - prune transaction by it's hash index
-	c - prune call traces (used by trace_filter method)
+	c - prune call traces (used by trace_filter method), including traces and appearances stored by '--experiments=traces,appearances'
 	Does delete data older than 90K block (can set another value by '--prune.*.older' flags). 
 	If item is NOT in the list - means NO pruning for this data.s
 	Example: --prune=hrtc`,
@@ -105,7 +105,9 @@ var (
 	ExperimentsFlag = cli.StringFlag{
 		Name: "experiments",
 		Usage: `Enable some experimental stages:
-* tevm - write TEVM translated code to the DB`,
+* tevm - write TEVM translated code to the DB
+* traces - store Parity-style traces of transactions, serves trace_filter, trace_block and trace_transaction without re-execution
+* appearances - index transactions by every address appearing in them, serves erigon_getTransactionsByAddress`,
 		Value: "default",
 	}
 
@@ -300,15 +302,19 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *node.Config) {
 		HttpVirtualHost:         strings.Split(ctx.GlobalString(utils.HTTPVirtualHostsFlag.Name), ","),
 		API:                     strings.Split(ctx.GlobalString(utils.HTTPApiFlag.Name), ","),
 
-		WebsocketEnabled:     ctx.GlobalIsSet(utils.WSEnabledFlag.Name),
-		RpcBatchConcurrency:  ctx.GlobalUint(utils.RpcBatchConcurrencyFlag.Name),
-		DBReadConcurrency:    ctx.GlobalInt(utils.DBReadConcurrencyFlag.Name),
-		RpcAllowListFilePath: ctx.GlobalString(utils.RpcAccessListFlag.Name),
-		Gascap:               ctx.GlobalUint64(utils.RpcGasCapFlag.Name),
-		MaxTraces:            ctx.GlobalUint64(utils.TraceMaxtracesFlag.Name),
-		TraceCompatibility:   ctx.GlobalBool(utils.RpcTraceCompatFlag.Name),
-		StarknetGRPCAddress:  ctx.GlobalString(utils.StarknetGrpcAddressFlag.Name),
-		TevmEnabled:          ctx.GlobalBool(utils.TevmFlag.Name),
+		WebsocketEnabled:      ctx.GlobalIsSet(utils.WSEnabledFlag.Name),
+		GraphQLEnabled:        ctx.GlobalBool(utils.GraphQLEnabledFlag.Name),
+		RpcBatchConcurrency:   ctx.GlobalUint(utils.RpcBatchConcurrencyFlag.Name),
+		DBReadConcurrency:     ctx.GlobalInt(utils.DBReadConcurrencyFlag.Name),
+		RpcAllowListFilePath:  ctx.GlobalString(utils.RpcAccessListFlag.Name),
+		RpcRateLimitsFilePath: ctx.GlobalString(utils.RpcRateLimitsFlag.Name),
+		Gascap:                ctx.GlobalUint64(utils.RpcGasCapFlag.Name),
+		MaxTraces:             ctx.GlobalUint64(utils.TraceMaxtracesFlag.Name),
+		LogsMaxBlockRange:     ctx.GlobalUint64(utils.RpcLogsMaxRangeFlag.Name),
+		LogsMaxResults:        ctx.GlobalUint64(utils.RpcLogsMaxResultsFlag.Name),
+		TraceCompatibility:    ctx.GlobalBool(utils.RpcTraceCompatFlag.Name),
+		StarknetGRPCAddress:   ctx.GlobalString(utils.StarknetGrpcAddressFlag.Name),
+		TevmEnabled:           ctx.GlobalBool(utils.TevmFlag.Name),
 
 		TxPoolApiAddr: ctx.GlobalString(utils.TxpoolApiAddrFlag.Name),
 
@@ -0,0 +1,36 @@
+package engineapi
+
+import (
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// ConvertWithdrawalsToRpc converts EIP-4895 withdrawals to their gRPC representation.
+func ConvertWithdrawalsToRpc(in []*types.Withdrawal) []*types2.Withdrawal {
+	out := make([]*types2.Withdrawal, 0, len(in))
+	for _, w := range in {
+		out = append(out, &types2.Withdrawal{
+			Index:          w.Index,
+			ValidatorIndex: w.Validator,
+			Address:        gointerfaces.ConvertAddressToH160(w.Address),
+			Amount:         w.Amount,
+		})
+	}
+	return out
+}
+
+// ConvertWithdrawalsFromRpc converts gRPC withdrawals back to EIP-4895 withdrawals.
+// The result is never nil, so that an empty list is still distinguishable from a pre-Shanghai payload.
+func ConvertWithdrawalsFromRpc(in []*types2.Withdrawal) []*types.Withdrawal {
+	out := make([]*types.Withdrawal, 0, len(in))
+	for _, w := range in {
+		out = append(out, &types.Withdrawal{
+			Index:     w.Index,
+			Validator: w.ValidatorIndex,
+			Address:   gointerfaces.ConvertH160toAddress(w.Address),
+			Amount:    w.Amount,
+		})
+	}
+	return out
+}
@@ -25,6 +25,36 @@ func (e nonCanonocalHashError) Error() string {
 	return fmt.Sprintf("hash %x is not currently canonical", e.hash)
 }
 
+// unknownBlockError is returned for "safe" and "finalized" block tags before the first post-merge fork choice
+type unknownBlockError struct{ tag string }
+
+func (e unknownBlockError) ErrorCode() int { return -39001 }
+
+func (e unknownBlockError) Error() string {
+	return fmt.Sprintf("%s block not found, the chain has not reached the merge yet", e.tag)
+}
+
+// GetSafeBlockNumber returns the number of the safe block of the latest fork choice
+func GetSafeBlockNumber(tx kv.Tx) (uint64, error) {
+	return forkChoiceBlockNumber(tx, rawdb.ReadForkchoiceSafe(tx), "safe")
+}
+
+// GetFinalizedBlockNumber returns the number of the finalized block of the latest fork choice
+func GetFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
+	return forkChoiceBlockNumber(tx, rawdb.ReadForkchoiceFinalized(tx), "finalized")
+}
+
+func forkChoiceBlockNumber(tx kv.Tx, hash common.Hash, tag string) (uint64, error) {
+	if hash == (common.Hash{}) {
+		return 0, unknownBlockError{tag}
+	}
+	number := rawdb.ReadHeaderNumber(tx, hash)
+	if number == nil {
+		return 0, fmt.Errorf("%s block %x not found", tag, hash)
+	}
+	return *number, nil
+}
+
 func GetBlockNumber(blockNrOrHash rpc.BlockNumberOrHash, tx kv.Tx, filters *filters.Filters) (uint64, common.Hash, bool, error) {
 	return _GetBlockNumber(blockNrOrHash.RequireCanonical, blockNrOrHash, tx, filters)
 }
@@ -46,6 +76,14 @@ func _GetBlockNumber(requireCanonical bool, blockNrOrHash rpc.BlockNumberOrHash,
 			blockNumber = latestBlockNumber
 		} else if number == rpc.EarliestBlockNumber {
 			blockNumber = 0
+		} else if number == rpc.SafeBlockNumber {
+			if blockNumber, err = GetSafeBlockNumber(tx); err != nil {
+				return 0, common.Hash{}, false, err
+			}
+		} else if number == rpc.FinalizedBlockNumber {
+			if blockNumber, err = GetFinalizedBlockNumber(tx); err != nil {
+				return 0, common.Hash{}, false, err
+			}
 		} else if number == rpc.PendingBlockNumber {
 			pendingBlock := filters.LastPendingBlock()
 			if pendingBlock == nil {
@@ -89,6 +89,10 @@ func (back *BlockReader) TxnLookup(ctx context.Context, tx kv.Getter, txnHash co
 	return *n, true, nil
 }
 
+func (back *BlockReader) RawReceipts(ctx context.Context, tx kv.Tx, blockHeight uint64) (types.Receipts, error) {
+	return rawdb.ReadRawReceipts(tx, blockHeight), nil
+}
+
 //func (back *BlockReader) TxnByHashDeprecated(ctx context.Context, tx kv.Getter, txnHash common.Hash) (txn types.Transaction, blockHash common.Hash, blockNum, txnIndex uint64, err error) {
 //	return rawdb.ReadTransactionByHash(tx, txnHash)
 //}
@@ -179,6 +183,10 @@ func (back *RemoteBlockReader) BodyRlp(ctx context.Context, tx kv.Getter, hash c
 	return bodyRlp, nil
 }
 
+func (back *RemoteBlockReader) RawReceipts(ctx context.Context, tx kv.Tx, blockHeight uint64) (types.Receipts, error) {
+	return rawdb.ReadRawReceipts(tx, blockHeight), nil
+}
+
 // BlockReaderWithSnapshots can read blocks from db and snapshots
 type BlockReaderWithSnapshots struct {
 	sn *RoSnapshots
@@ -392,7 +400,7 @@ func (back *BlockReaderWithSnapshots) BlockWithSenders(ctx context.Context, tx k
 		}
 		if ok && b != nil {
 			if txsAmount == 0 {
-				block = types.NewBlockFromStorage(hash, h, nil, b.Uncles)
+				block = types.NewBlockFromStorage(hash, h, nil, b.Uncles, b.Withdrawals)
 				if len(senders) != block.Transactions().Len() {
 					return block, senders, nil // no senders is fine - will recover them on the fly
 				}
@@ -412,7 +420,7 @@ func (back *BlockReaderWithSnapshots) BlockWithSenders(ctx context.Context, tx k
 				return nil, nil, err
 			}
 			if ok {
-				block = types.NewBlockFromStorage(hash, h, txs, b.Uncles)
+				block = types.NewBlockFromStorage(hash, h, txs, b.Uncles, b.Withdrawals)
 				if len(senders) != block.Transactions().Len() {
 					return block, senders, nil // no senders is fine - will recover them on the fly
 				}
@@ -505,6 +513,7 @@ func (back *BlockReaderWithSnapshots) bodyFromSnapshot(blockHeight uint64, sn *B
 
 	body := new(types.Body)
 	body.Uncles = b.Uncles
+	body.Withdrawals = b.Withdrawals
 	var txsAmount uint32
 	if b.TxAmount >= 2 {
 		txsAmount = b.TxAmount - 2
@@ -588,6 +597,43 @@ func (back *BlockReaderWithSnapshots) txnByHash(txnHash common.Hash, segments []
 	return
 }
 
+func (back *BlockReaderWithSnapshots) receiptsFromSnapshot(blockHeight uint64, sn *ReceiptSegment, buf []byte) (types.Receipts, []byte, error) {
+	if sn.idxReceiptNumber == nil {
+		return nil, buf, nil
+	}
+	receiptsOffset := sn.idxReceiptNumber.Lookup2(blockHeight - sn.idxReceiptNumber.BaseDataID())
+	gg := sn.seg.MakeGetter()
+	gg.Reset(receiptsOffset)
+	buf, _ = gg.Next(buf[:0])
+	var stored types.ReceiptsForStorage
+	if err := rlp.DecodeBytes(buf, &stored); err != nil {
+		return nil, buf, fmt.Errorf("%w, block: %d, %s", err, blockHeight, sn.seg.FilePath())
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, r := range stored {
+		receipts[i] = (*types.Receipt)(r)
+	}
+	return receipts, buf, nil
+}
+
+// RawReceipts - receipts of canonical block without derived fields, nil if they are neither in snapshots nor in db
+func (back *BlockReaderWithSnapshots) RawReceipts(ctx context.Context, tx kv.Tx, blockHeight uint64) (receipts types.Receipts, err error) {
+	ok, err := back.sn.ViewReceipts(blockHeight, func(seg *ReceiptSegment) error {
+		receipts, _, err = back.receiptsFromSnapshot(blockHeight, seg, nil)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ok && receipts != nil {
+		return receipts, nil
+	}
+	return rawdb.ReadRawReceipts(tx, blockHeight), nil
+}
+
 // TxnLookup - find blockNumber and txnID by txnHash
 func (back *BlockReaderWithSnapshots) TxnLookup(ctx context.Context, tx kv.Getter, txnHash common.Hash) (uint64, bool, error) {
 	n, err := rawdb.ReadTxLookupEntry(tx, txnHash)
@@ -30,6 +30,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snap"
@@ -57,6 +58,12 @@ type TxnSegment struct {
 	From, To            uint64
 }
 
+type ReceiptSegment struct {
+	seg              *compress.Decompressor // value: rlp(types.ReceiptsForStorage)
+	idxReceiptNumber *recsplit.Index        // block_num_u64     -> receipts_segment_offset
+	From, To         uint64
+}
+
 func (sn *HeaderSegment) close() {
 	if sn.seg != nil {
 		sn.seg.Close()
@@ -139,6 +146,31 @@ func (sn *TxnSegment) reopen(dir string) (err error) {
 	return nil
 }
 
+func (sn *ReceiptSegment) close() {
+	if sn.seg != nil {
+		sn.seg.Close()
+		sn.seg = nil
+	}
+	if sn.idxReceiptNumber != nil {
+		sn.idxReceiptNumber.Close()
+		sn.idxReceiptNumber = nil
+	}
+}
+
+func (sn *ReceiptSegment) reopen(dir string) (err error) {
+	sn.close()
+	fileName := snap.SegmentFileName(sn.From, sn.To, snap.Receipts)
+	sn.seg, err = compress.NewDecompressor(path.Join(dir, fileName))
+	if err != nil {
+		return err
+	}
+	sn.idxReceiptNumber, err = recsplit.OpenIndex(path.Join(dir, snap.IdxFileName(sn.From, sn.To, snap.Receipts.String())))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 type headerSegments struct {
 	lock     sync.RWMutex
 	segments []*HeaderSegment
@@ -253,13 +285,72 @@ func (s *txnSegments) ViewSegment(blockNum uint64, f func(*TxnSegment) error) (f
 	return false, nil
 }
 
+// receiptSegments - unlike other segments, receipts are optional and may have gaps:
+// they are produced only for blocks which receipts are in db
+type receiptSegments struct {
+	lock     sync.RWMutex
+	segments []*ReceiptSegment
+}
+
+func (s *receiptSegments) closeLocked() {
+	for i := range s.segments {
+		s.segments[i].close()
+	}
+}
+func (s *receiptSegments) reopen(dir string) error {
+	for _, seg := range s.segments {
+		if err := seg.reopen(dir); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+func (s *receiptSegments) View(f func([]*ReceiptSegment) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return f(s.segments)
+}
+func (s *receiptSegments) ViewSegment(blockNum uint64, f func(*ReceiptSegment) error) (found bool, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, seg := range s.segments {
+		if !(blockNum >= seg.From && blockNum < seg.To) {
+			continue
+		}
+		return true, f(seg)
+	}
+	return false, nil
+}
+
+// coveredTo - end of the indexed receipts segments which follow each other without gaps starting with the segment of the block `from`,
+// returns `from` if there is no such segment
+func (s *receiptSegments) coveredTo(from uint64) uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	to := from
+	for _, seg := range s.segments {
+		if seg.idxReceiptNumber == nil || seg.To <= to {
+			continue
+		}
+		if seg.From > to {
+			break
+		}
+		to = seg.To
+	}
+	return to
+}
+
 type RoSnapshots struct {
 	indicesReady  atomic.Bool
 	segmentsReady atomic.Bool
 
-	Headers *headerSegments
-	Bodies  *bodySegments
-	Txs     *txnSegments
+	Headers  *headerSegments
+	Bodies   *bodySegments
+	Txs      *txnSegments
+	Receipts *receiptSegments
 
 	dir         string
 	segmentsMax atomic.Uint64 // all types of .seg files are available - up to this number
@@ -273,7 +364,7 @@ type RoSnapshots struct {
 //  - gaps are not allowed
 //  - segment have [from:to) semantic
 func NewRoSnapshots(cfg ethconfig.Snapshot, snapshotDir string) *RoSnapshots {
-	return &RoSnapshots{dir: snapshotDir, cfg: cfg, Headers: &headerSegments{}, Bodies: &bodySegments{}, Txs: &txnSegments{}}
+	return &RoSnapshots{dir: snapshotDir, cfg: cfg, Headers: &headerSegments{}, Bodies: &bodySegments{}, Txs: &txnSegments{}, Receipts: &receiptSegments{}}
 }
 
 func (s *RoSnapshots) Cfg() ethconfig.Snapshot { return s.cfg }
@@ -284,6 +375,11 @@ func (s *RoSnapshots) IndicesMax() uint64      { return s.idxMax.Load() }
 func (s *RoSnapshots) SegmentsMax() uint64     { return s.segmentsMax.Load() }
 func (s *RoSnapshots) BlocksAvailable() uint64 { return cmp.Min(s.segmentsMax.Load(), s.idxMax.Load()) }
 
+// ReceiptsAvailableTo - receipts of blocks [from, to) can be read from snapshots
+func (s *RoSnapshots) ReceiptsAvailableTo(from uint64) (to uint64) {
+	return cmp.Min(s.Receipts.coveredTo(from), s.BlocksAvailable()+1)
+}
+
 func (s *RoSnapshots) EnsureExpectedBlocksAreAvailable(cfg *snapshothashes.Config) error {
 	if s.BlocksAvailable() < cfg.ExpectBlocks {
 		return fmt.Errorf("app must wait until all expected snapshots are available. Expected: %d, Available: %d", cfg.ExpectBlocks, s.BlocksAvailable())
@@ -322,7 +418,7 @@ func (s *RoSnapshots) idxAvailability() uint64 {
 }
 
 func (s *RoSnapshots) ReopenIndices() error {
-	return s.ReopenSomeIndices(snap.AllSnapshotTypes...)
+	return s.ReopenSomeIndices(append(snap.AllSnapshotTypes, snap.OptionalSnapshotTypes...)...)
 }
 
 func (s *RoSnapshots) ReopenSomeIndices(types ...snap.Type) (err error) {
@@ -332,6 +428,8 @@ func (s *RoSnapshots) ReopenSomeIndices(types ...snap.Type) (err error) {
 	defer s.Bodies.lock.Unlock()
 	s.Txs.lock.Lock()
 	defer s.Txs.lock.Unlock()
+	s.Receipts.lock.Lock()
+	defer s.Receipts.lock.Unlock()
 
 	for _, t := range types {
 		switch t {
@@ -347,6 +445,10 @@ func (s *RoSnapshots) ReopenSomeIndices(types ...snap.Type) (err error) {
 			if err := s.Txs.reopen(s.dir); err != nil {
 				return err
 			}
+		case snap.Receipts:
+			if err := s.Receipts.reopen(s.dir); err != nil {
+				return err
+			}
 		default:
 			panic(fmt.Sprintf("unknown snapshot type: %s", t))
 		}
@@ -380,6 +482,8 @@ func (s *RoSnapshots) Reopen() error {
 	defer s.Bodies.lock.Unlock()
 	s.Txs.lock.Lock()
 	defer s.Txs.lock.Unlock()
+	s.Receipts.lock.Lock()
+	defer s.Receipts.lock.Unlock()
 	s.closeSegmentsLocked()
 	files, err := segments2(s.dir)
 	if err != nil {
@@ -429,6 +533,9 @@ func (s *RoSnapshots) Reopen() error {
 			s.segmentsMax.Store(0)
 		}
 	}
+	if err := s.openReceiptSegmentsLocked(); err != nil {
+		return err
+	}
 	s.segmentsReady.Store(true)
 
 	for _, sn := range s.Headers.segments {
@@ -453,6 +560,12 @@ func (s *RoSnapshots) Reopen() error {
 			return err
 		}
 	}
+	for _, sn := range s.Receipts.segments {
+		sn.idxReceiptNumber, err = recsplit.OpenIndex(path.Join(s.dir, snap.IdxFileName(sn.From, sn.To, snap.Receipts.String())))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
 
 	s.idxMax.Store(s.idxAvailability())
 	s.indicesReady.Store(true)
@@ -466,6 +579,8 @@ func (s *RoSnapshots) ReopenSegments() error {
 	defer s.Bodies.lock.Unlock()
 	s.Txs.lock.Lock()
 	defer s.Txs.lock.Unlock()
+	s.Receipts.lock.Lock()
+	defer s.Receipts.lock.Unlock()
 	s.closeSegmentsLocked()
 	files, err := segments2(s.dir)
 	if err != nil {
@@ -516,10 +631,41 @@ func (s *RoSnapshots) ReopenSegments() error {
 			s.segmentsMax.Store(0)
 		}
 	}
+	if err := s.openReceiptSegmentsLocked(); err != nil {
+		return err
+	}
 	s.segmentsReady.Store(true)
 	return nil
 }
 
+// openReceiptSegmentsLocked - opens receipts segments of the blocks ranges which segments are open, without indices
+func (s *RoSnapshots) openReceiptSegmentsLocked() error {
+	if len(s.Headers.segments) == 0 {
+		return nil
+	}
+	blocksTo := s.Headers.segments[len(s.Headers.segments)-1].To
+	files, err := receiptSegments2(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.To > blocksTo {
+			break
+		}
+		seg := &ReceiptSegment{From: f.From, To: f.To}
+		fileName := snap.SegmentFileName(f.From, f.To, snap.Receipts)
+		seg.seg, err = compress.NewDecompressor(path.Join(s.dir, fileName))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		s.Receipts.segments = append(s.Receipts.segments, seg)
+	}
+	return nil
+}
+
 func (s *RoSnapshots) Close() {
 	s.Headers.lock.Lock()
 	defer s.Headers.lock.Unlock()
@@ -527,6 +673,8 @@ func (s *RoSnapshots) Close() {
 	defer s.Bodies.lock.Unlock()
 	s.Txs.lock.Lock()
 	defer s.Txs.lock.Unlock()
+	s.Receipts.lock.Lock()
+	defer s.Receipts.lock.Unlock()
 	s.closeSegmentsLocked()
 }
 func (s *RoSnapshots) closeSegmentsLocked() {
@@ -542,6 +690,10 @@ func (s *RoSnapshots) closeSegmentsLocked() {
 		s.Txs.closeLocked()
 		s.Txs.segments = nil
 	}
+	if s.Receipts != nil {
+		s.Receipts.closeLocked()
+		s.Receipts.segments = nil
+	}
 }
 func (s *RoSnapshots) PrintDebug() {
 	s.Headers.lock.RLock()
@@ -550,6 +702,8 @@ func (s *RoSnapshots) PrintDebug() {
 	defer s.Bodies.lock.RUnlock()
 	s.Txs.lock.RLock()
 	defer s.Txs.lock.RUnlock()
+	s.Receipts.lock.RLock()
+	defer s.Receipts.lock.RUnlock()
 	fmt.Printf("sn: %d, %d\n", s.segmentsMax.Load(), s.idxMax.Load())
 	fmt.Println("    == Snapshots, Header")
 	for _, sn := range s.Headers.segments {
@@ -563,6 +717,10 @@ func (s *RoSnapshots) PrintDebug() {
 	for _, sn := range s.Txs.segments {
 		fmt.Printf("%d,  %t, %t\n", sn.From, sn.IdxTxnHash == nil, sn.IdxTxnHash2BlockNum == nil)
 	}
+	fmt.Println("    == Snapshots, Receipts")
+	for _, sn := range s.Receipts.segments {
+		fmt.Printf("%d,  %t\n", sn.From, sn.idxReceiptNumber == nil)
+	}
 }
 func (s *RoSnapshots) ViewHeaders(blockNum uint64, f func(sn *HeaderSegment) error) (found bool, err error) {
 	if !s.indicesReady.Load() || blockNum > s.BlocksAvailable() {
@@ -583,6 +741,13 @@ func (s *RoSnapshots) ViewTxs(blockNum uint64, f func(sn *TxnSegment) error) (fo
 	return s.Txs.ViewSegment(blockNum, f)
 }
 
+func (s *RoSnapshots) ViewReceipts(blockNum uint64, f func(sn *ReceiptSegment) error) (found bool, err error) {
+	if !s.indicesReady.Load() || blockNum > s.BlocksAvailable() {
+		return false, nil
+	}
+	return s.Receipts.ViewSegment(blockNum, f)
+}
+
 func BuildIndices(ctx context.Context, s *RoSnapshots, chainID uint256.Int, tmpDir string, from uint64, workers int, lvl log.Lvl) error {
 	log.Log(lvl, "[snapshots] Build indices", "from", from)
 	logEvery := time.NewTicker(20 * time.Second)
@@ -737,6 +902,53 @@ func BuildIndices(ctx context.Context, s *RoSnapshots, chainID uint256.Int, tmpD
 		return err
 	}
 
+	if err := s.Receipts.View(func(segments []*ReceiptSegment) error {
+		wg := &sync.WaitGroup{}
+		errs := make(chan error, len(segments)*2)
+		workersCh := make(chan struct{}, workers)
+		for _, sn := range segments {
+			if sn.From < from && sn.idxReceiptNumber != nil { // receipts of old blocks may be produced later than the blocks
+				continue
+			}
+
+			wg.Add(1)
+			workersCh <- struct{}{}
+			go func(blockFrom, blockTo uint64) {
+				defer func() {
+					wg.Done()
+					<-workersCh
+				}()
+
+				f := filepath.Join(s.Dir(), snap.SegmentFileName(blockFrom, blockTo, snap.Receipts))
+				errs <- ReceiptsIdx(ctx, f, blockFrom, tmpDir, lvl)
+				select {
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				case <-logEvery.C:
+					var m runtime.MemStats
+					runtime.ReadMemStats(&m)
+					log.Log(lvl, "[snapshots] ReceiptsIdx", "blockNum", blockTo,
+						"alloc", common2.ByteCount(m.Alloc), "sys", common2.ByteCount(m.Sys))
+				default:
+				}
+
+			}(sn.From, sn.To)
+		}
+		go func() {
+			wg.Wait()
+			close(errs)
+		}()
+		for err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -814,6 +1026,20 @@ func segments2(dir string) (res []snap.FileInfo, err error) {
 	return noGaps(noOverlaps(allTypeOfSegmentsMustExist(dir, res)))
 }
 
+func receiptSegments2(dir string) (res []snap.FileInfo, err error) {
+	list, err := snap.Segments(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range list {
+		if f.T != snap.Receipts {
+			continue
+		}
+		res = append(res, f)
+	}
+	return noOverlaps(res), nil
+}
+
 func chooseSegmentEnd(from, to, blocksPerFile uint64) uint64 {
 	next := (from/blocksPerFile + 1) * blocksPerFile
 	to = cmp.Min(next, to)
@@ -926,6 +1152,9 @@ func retireBlocks(ctx context.Context, blockFrom, blockTo uint64, chainID uint25
 	if err := BuildIndices(ctx, snapshots, chainID, tmpDir, snapshots.IndicesMax(), idxWorkers, log.LvlInfo); err != nil {
 		return err
 	}
+	if err := retireReceipts(ctx, tmpDir, snapshots, db, workers, lvl); err != nil {
+		return fmt.Errorf("retireReceipts: %w", err)
+	}
 	merger := NewMerger(tmpDir, workers, lvl, chainID, notifier)
 	ranges := merger.FindMergeRanges(snapshots)
 	if len(ranges) == 0 {
@@ -946,6 +1175,14 @@ func retireBlocks(ctx context.Context, blockFrom, blockTo uint64, chainID uint25
 				Path: snap.SegmentFileName(r.from, r.to, t),
 			})
 		}
+		for _, t := range snap.OptionalSnapshotTypes {
+			if _, err := os.Stat(filepath.Join(snapshots.Dir(), snap.SegmentFileName(r.from, r.to, t))); err != nil {
+				continue
+			}
+			req.Items = append(req.Items, &proto_downloader.DownloadItem{
+				Path: snap.SegmentFileName(r.from, r.to, t),
+			})
+		}
 	}
 	if len(req.Items) > 0 && downloader != nil {
 		if _, err := downloader.Download(ctx, req); err != nil {
@@ -955,6 +1192,58 @@ func retireBlocks(ctx context.Context, blockFrom, blockTo uint64, chainID uint25
 	return nil
 }
 
+// retireReceipts - dumps receipts of blocks segments which have no receipts segment yet. Blocks are retired
+// independently of execution, so receipts of old segments may become available later. Segments which receipts
+// are not in db (not executed yet or pruned) are skipped.
+func retireReceipts(ctx context.Context, tmpDir string, snapshots *RoSnapshots, db kv.RoDB, workers int, lvl log.Lvl) error {
+	var executed uint64
+	if err := db.View(ctx, func(tx kv.Tx) (err error) {
+		executed, err = stages.GetStageProgress(tx, stages.Execution)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	var ranges []mergeRange
+	if err := snapshots.Headers.View(func(segments []*HeaderSegment) error {
+		for _, sn := range segments {
+			if sn.To > executed+1 {
+				break
+			}
+			if snapshots.Receipts.coveredTo(sn.From) >= sn.To {
+				continue
+			}
+			ranges = append(ranges, mergeRange{from: sn.From, to: sn.To})
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var dumped bool
+	for _, r := range ranges {
+		segFilePath := filepath.Join(snapshots.Dir(), snap.SegmentFileName(r.from, r.to, snap.Receipts))
+		if err := DumpReceipts(ctx, db, segFilePath, tmpDir, r.from, r.to, workers, lvl); err != nil {
+			if errors.Is(err, ErrReceiptsMissed) {
+				log.Debug("[snapshots] Skip receipts", "range", r.String(), "err", err)
+				continue
+			}
+			return fmt.Errorf("DumpReceipts: %w", err)
+		}
+		if err := ReceiptsIdx(ctx, segFilePath, r.from, tmpDir, lvl); err != nil {
+			return fmt.Errorf("ReceiptsIdx: %w", err)
+		}
+		dumped = true
+	}
+	if !dumped {
+		return nil
+	}
+	if err := snapshots.Reopen(); err != nil {
+		return fmt.Errorf("ReopenSegments: %w", err)
+	}
+	return nil
+}
+
 func DumpBlocks(ctx context.Context, blockFrom, blockTo, blocksPerFile uint64, tmpDir, snapshotDir string, chainDB kv.RoDB, workers int, lvl log.Lvl) error {
 	if blocksPerFile == 0 {
 		return nil
@@ -1269,6 +1558,77 @@ func DumpBodies(ctx context.Context, db kv.RoDB, segmentFilePath, tmpDir string,
 	return nil
 }
 
+// ErrReceiptsMissed - receipts of some block in the range are not in db: pruned or block is not executed yet
+var ErrReceiptsMissed = errors.New("receipts missed in db")
+
+// DumpReceipts - [from, to)
+// Format: rlp(types.ReceiptsForStorage) - one word per block, including empty blocks
+func DumpReceipts(ctx context.Context, db kv.RoDB, segmentFilePath, tmpDir string, blockFrom, blockTo uint64, workers int, lvl log.Lvl) error {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	f, err := compress.NewCompressor(ctx, "Receipts", segmentFilePath, tmpDir, compress.MinPatternScore, workers, lvl)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var count uint64
+	from := dbutils.EncodeBlockNumber(blockFrom)
+	if err := kv.BigChunks(db, kv.HeaderCanonical, from, func(tx kv.Tx, k, v []byte) (bool, error) {
+		blockNum := binary.BigEndian.Uint64(k)
+		if blockNum >= blockTo {
+			return false, nil
+		}
+		var receipts types.Receipts
+		if blockNum > 0 { // genesis has no receipts
+			has, err := tx.Has(kv.Receipts, k)
+			if err != nil {
+				return false, err
+			}
+			if !has {
+				return false, fmt.Errorf("%w: block_num=%d", ErrReceiptsMissed, blockNum)
+			}
+			receipts = rawdb.ReadRawReceipts(tx, blockNum)
+		}
+		stored := make(types.ReceiptsForStorage, len(receipts))
+		for i, r := range receipts {
+			stored[i] = (*types.ReceiptForStorage)(r)
+		}
+		value, err := rlp.EncodeToBytes(stored)
+		if err != nil {
+			return false, err
+		}
+		if err := f.AddWord(value); err != nil {
+			return false, err
+		}
+		count++
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-logEvery.C:
+			var m runtime.MemStats
+			runtime.ReadMemStats(&m)
+			log.Log(lvl, "[snapshots] Dumping receipts", "block num", blockNum,
+				"alloc", common2.ByteCount(m.Alloc), "sys", common2.ByteCount(m.Sys),
+			)
+		default:
+		}
+		return true, nil
+	}); err != nil {
+		return err
+	}
+	if count != blockTo-blockFrom {
+		return fmt.Errorf("%w: expected %d blocks, got %d", ErrReceiptsMissed, blockTo-blockFrom, count)
+	}
+	if err := f.Compress(); err != nil {
+		return fmt.Errorf("compress: %w", err)
+	}
+
+	return nil
+}
+
 var EmptyTxHash = common.Hash{}
 
 func TransactionsIdx(ctx context.Context, chainID uint256.Int, blockFrom, blockTo uint64, snapshotDir string, tmpDir string, lvl log.Lvl) (err error) {
@@ -1484,6 +1844,28 @@ func BodiesIdx(ctx context.Context, segmentFilePath string, firstBlockNumInSegme
 	return nil
 }
 
+// ReceiptsIdx - blockNum -> offset (analog of kv.Receipts key)
+func ReceiptsIdx(ctx context.Context, segmentFilePath string, firstBlockNumInSegment uint64, tmpDir string, lvl log.Lvl) error {
+	num := make([]byte, 8)
+
+	d, err := compress.NewDecompressor(segmentFilePath)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	if err := Idx(ctx, d, firstBlockNumInSegment, tmpDir, func(idx *recsplit.RecSplit, i, offset uint64, word []byte) error {
+		n := binary.PutUvarint(num, i)
+		if err := idx.AddKey(num[:n], offset); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("ReceiptsIdx: %w", err)
+	}
+	return nil
+}
+
 type decompressItem struct {
 	i, offset uint64
 	word      []byte
@@ -1578,7 +1960,7 @@ func ForEachHeader(ctx context.Context, s *RoSnapshots, walker func(header *type
 			ch := forEachAsync(ctx, sn.seg)
 			for it := range ch {
 				if it.err != nil {
-					return nil
+					return it.err
 				}
 
 				header := new(types.Header)
@@ -1667,6 +2049,25 @@ func (m *Merger) filesByRange(snapshots *RoSnapshots, from, to uint64) (toMergeH
 	return
 }
 
+// receiptFilesByRange - receipts segments of [from, to), complete=false if receipts of some blocks of the range are not in snapshots
+func (m *Merger) receiptFilesByRange(snapshots *RoSnapshots, from, to uint64) (toMergeReceipts []string, complete bool, err error) {
+	next := from
+	err = snapshots.Receipts.View(func(segments []*ReceiptSegment) error {
+		for _, sn := range segments {
+			if sn.From < from {
+				continue
+			}
+			if sn.To > to || sn.From != next {
+				break
+			}
+			toMergeReceipts = append(toMergeReceipts, sn.seg.FilePath())
+			next = sn.To
+		}
+		return nil
+	})
+	return toMergeReceipts, next == to, err
+}
+
 // Merge does merge segments in given ranges
 func (m *Merger) Merge(ctx context.Context, snapshots *RoSnapshots, mergeRanges []mergeRange, snapshotDir string, doIndex bool) error {
 	if len(mergeRanges) == 0 {
@@ -1716,6 +2117,24 @@ func (m *Merger) Merge(ctx context.Context, snapshots *RoSnapshots, mergeRanges
 			}
 		}
 
+		toMergeReceipts, complete, err := m.receiptFilesByRange(snapshots, r.from, r.to)
+		if err != nil {
+			return err
+		}
+		if complete { // incomplete receipts segments stay as is - they don't have to follow blocks segments
+			segFilePath := filepath.Join(snapshotDir, snap.SegmentFileName(r.from, r.to, snap.Receipts))
+			if err := m.merge(ctx, toMergeReceipts, segFilePath, logEvery); err != nil {
+				return fmt.Errorf("mergeByAppendSegments: %w", err)
+			}
+			if doIndex {
+				if err := ReceiptsIdx(ctx, segFilePath, r.from, m.tmpDir, m.lvl); err != nil {
+					return fmt.Errorf("ReceiptsIdx: %w", err)
+				}
+			}
+		} else {
+			toMergeReceipts = nil
+		}
+
 		if err := snapshots.Reopen(); err != nil {
 			return fmt.Errorf("ReopenSegments: %w", err)
 		}
@@ -1735,6 +2154,10 @@ func (m *Merger) Merge(ctx context.Context, snapshots *RoSnapshots, mergeRanges
 		if err := m.removeOldFiles(toMergeTxs, snapshotDir); err != nil {
 			return err
 		}
+
+		if err := m.removeOldFiles(toMergeReceipts, snapshotDir); err != nil {
+			return err
+		}
 	}
 	log.Log(m.lvl, "[snapshots] Merge done", "from", mergeRanges[0].from)
 	return nil
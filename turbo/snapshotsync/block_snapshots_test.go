@@ -2,6 +2,7 @@ package snapshotsync
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 	"testing/fstest"
@@ -200,6 +201,61 @@ func TestOpenAllSnapshot(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestOpenReceiptsSnapshot(t *testing.T) {
+	dir, require := t.TempDir(), require.New(t)
+	cfg := ethconfig.Snapshot{Enabled: true}
+	createFile := func(from, to uint64, name snap.Type) { createTestSegmentFile(t, from, to, name, dir) }
+	for _, snT := range snap.AllSnapshotTypes {
+		createFile(0, 500_000, snT)
+		createFile(500_000, 1_000_000, snT)
+	}
+	createFile(0, 500_000, snap.Receipts)
+	createFile(1_000_000, 1_500_000, snap.Receipts) // beyond blocks segments - not opened
+
+	s := NewRoSnapshots(cfg, dir)
+	defer s.Close()
+	require.NoError(s.Reopen())
+	require.Equal(1, len(s.Receipts.segments))
+	require.Equal(500_000, int(s.ReceiptsAvailableTo(0)))
+	require.Equal(600_000, int(s.ReceiptsAvailableTo(600_000))) // gap - nothing is covered
+
+	ok, err := s.ViewReceipts(10, func(sn *ReceiptSegment) error {
+		require.Equal(int(sn.To), 500_000)
+		return nil
+	})
+	require.NoError(err)
+	require.True(ok)
+	ok, err = s.ViewReceipts(500_000, func(sn *ReceiptSegment) error { return nil })
+	require.NoError(err)
+	require.False(ok)
+
+	// receipts segments are merged only when they cover the whole merge range
+	createFile(500_000, 1_000_000, snap.Receipts)
+	for i := uint64(1_000_000); i < 1_500_000; i += 100_000 {
+		for _, snT := range snap.AllSnapshotTypes {
+			createFile(i, i+100_000, snT)
+		}
+		if i != 1_200_000 {
+			createFile(i, i+100_000, snap.Receipts)
+		}
+	}
+	require.NoError(os.Remove(filepath.Join(dir, snap.SegmentFileName(1_000_000, 1_500_000, snap.Receipts))))
+	require.NoError(s.Reopen())
+	require.Equal(1_200_000, int(s.ReceiptsAvailableTo(0)))
+	require.Equal(1_500_000, int(s.ReceiptsAvailableTo(1_300_000)))
+
+	merger := NewMerger(dir, 1, log.LvlInfo, uint256.Int{}, nil)
+	ranges := merger.FindMergeRanges(s)
+	require.True(len(ranges) > 0)
+	require.NoError(merger.Merge(context.Background(), s, ranges, s.Dir(), false))
+	_, err = os.Stat(filepath.Join(dir, snap.SegmentFileName(1_000_000, 1_500_000, snap.Headers)))
+	require.NoError(err)
+	_, err = os.Stat(filepath.Join(dir, snap.SegmentFileName(1_000_000, 1_500_000, snap.Receipts)))
+	require.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, snap.SegmentFileName(1_100_000, 1_200_000, snap.Receipts)))
+	require.NoError(err)
+}
+
 func TestParseCompressedFileName(t *testing.T) {
 	require := require.New(t)
 	fs := fstest.MapFS{
@@ -237,4 +293,8 @@ func TestParseCompressedFileName(t *testing.T) {
 	require.Equal(f.T, snap.Bodies)
 	require.Equal(1_000, int(f.From))
 	require.Equal(2_000, int(f.To))
+
+	f, err = snap.ParseFileName("", "v1-1-2-receipts.seg")
+	require.NoError(err)
+	require.Equal(f.T, snap.Receipts)
 }
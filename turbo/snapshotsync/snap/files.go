@@ -17,6 +17,7 @@ const (
 	Headers Type = iota
 	Bodies
 	Transactions
+	Receipts
 	NumberOfTypes
 )
 
@@ -28,6 +29,8 @@ func (ft Type) String() string {
 		return "bodies"
 	case Transactions:
 		return "transactions"
+	case Receipts:
+		return "receipts"
 	default:
 		panic(fmt.Sprintf("unknown file type: %d", ft))
 	}
@@ -41,6 +44,8 @@ func ParseFileType(s string) (Type, bool) {
 		return Bodies, true
 	case "transactions":
 		return Transactions, true
+	case "receipts":
+		return Receipts, true
 	default:
 		return NumberOfTypes, false
 	}
@@ -54,8 +59,12 @@ const (
 
 func (it IdxType) String() string { return string(it) }
 
+// AllSnapshotTypes - segments which must exist for a blocks range to be available
 var AllSnapshotTypes = []Type{Headers, Bodies, Transactions}
 
+// OptionalSnapshotTypes - segments which are produced only when their data is in db (receipts are not pruned and blocks are executed)
+var OptionalSnapshotTypes = []Type{Receipts}
+
 var (
 	ErrInvalidFileName = fmt.Errorf("invalid compressed file name")
 )
@@ -119,6 +128,8 @@ func ParseFileName(dir, fileName string) (res FileInfo, err error) {
 		snapshotType = Bodies
 	case Transactions:
 		snapshotType = Transactions
+	case Receipts:
+		snapshotType = Receipts
 	default:
 		return res, fmt.Errorf("unexpected snapshot suffix: %s,%w", parts[2], ErrInvalidFileName)
 	}
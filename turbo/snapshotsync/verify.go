@@ -0,0 +1,471 @@
+package snapshotsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/compress"
+	"github.com/ledgerwatch/erigon-lib/recsplit"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snap"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// SnapshotProblem - corruption found in .seg or .idx file
+type SnapshotProblem struct {
+	File     string    // file name (without dir)
+	From, To uint64    // blocks range of file
+	Type     snap.Type // type of segment
+	Index    string    // name of index if problem is in .idx file, empty if in .seg file
+	Err      error
+}
+
+func (p SnapshotProblem) String() string { return fmt.Sprintf("%s: %s", p.File, p.Err) }
+
+// VerifySnapshots - checks content of segments. Torrent piece hashes (see downloader.VerifyDtaFiles)
+// only prove that file is the same as was published, but not that it's correct. This func checks:
+//   - headers have sequential numbers and linked by ParentHash (also across segments)
+//   - transactions root, uncles hash and withdrawals root of each header match bodies and transactions segments
+//   - BaseTxId of bodies are contiguous
+//   - every key of .idx files resolves to the right record
+//
+// Only first problem of each file is reported. Returns error only if verification can't be continued.
+func VerifySnapshots(ctx context.Context, s *RoSnapshots, lvl log.Lvl) (problems []SnapshotProblem, err error) {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	seen := map[string]struct{}{}
+	report := func(from, to uint64, t snap.Type, index string, err error) {
+		p := SnapshotProblem{From: from, To: to, Type: t, Index: index, Err: err}
+		if index != "" {
+			p.File = snap.IdxFileName(from, to, index)
+		} else {
+			p.File = snap.SegmentFileName(from, to, t)
+		}
+		if _, ok := seen[p.File]; ok {
+			return
+		}
+		seen[p.File] = struct{}{}
+		problems = append(problems, p)
+		log.Warn("[snapshots] Verify", "problem", p.String())
+	}
+
+	v := &verifier{report: report}
+	if err := s.Headers.View(func(headers []*HeaderSegment) error {
+		return s.Bodies.View(func(bodies []*BodySegment) error {
+			return s.Txs.View(func(txs []*TxnSegment) error {
+				for _, hSn := range headers {
+					bSn, tSn := findBodySegment(bodies, hSn.From), findTxnSegment(txs, hSn.From)
+					if bSn == nil || tSn == nil || bSn.To != hSn.To || tSn.To != hSn.To {
+						return fmt.Errorf("segments of range %d-%d are not aligned", hSn.From, hSn.To)
+					}
+					if err := v.verifyRange(ctx, hSn, bSn, tSn); err != nil {
+						return err
+					}
+					select {
+					case <-logEvery.C:
+						log.Log(lvl, "[snapshots] Verify", "blockNum", hSn.To, "problems", len(problems))
+					default:
+					}
+				}
+				return nil
+			})
+		})
+	}); err != nil {
+		return problems, err
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].File < problems[j].File })
+	return problems, nil
+}
+
+func findBodySegment(segments []*BodySegment, from uint64) *BodySegment {
+	for _, sn := range segments {
+		if sn.From == from {
+			return sn
+		}
+	}
+	return nil
+}
+
+func findTxnSegment(segments []*TxnSegment, from uint64) *TxnSegment {
+	for _, sn := range segments {
+		if sn.From == from {
+			return sn
+		}
+	}
+	return nil
+}
+
+// blockRoots - fields of header which must be re-computable from body and transactions
+type blockRoots struct {
+	txHash, uncleHash common.Hash
+	withdrawalsHash   *common.Hash
+}
+
+type verifier struct {
+	report func(from, to uint64, t snap.Type, index string, err error)
+
+	// state of headers chain - carried across segments
+	prevHash   common.Hash
+	prevNum    uint64
+	hasPrev    bool
+	nextTxnID  uint64
+	hasNextTxn bool
+}
+
+func (v *verifier) verifyRange(ctx context.Context, hSn *HeaderSegment, bSn *BodySegment, tSn *TxnSegment) error {
+	roots, err := v.verifyHeaders(ctx, hSn)
+	if err != nil {
+		return err
+	}
+	if roots == nil { // headers segment is broken - can't check bodies against it
+		v.hasNextTxn = false
+		return nil
+	}
+	return v.verifyBodies(ctx, bSn, tSn, roots)
+}
+
+// verifyIdx - checks that .idx file resolves ordinal i to offset. Broken .idx can panic on lookup.
+func verifyIdx(idx *recsplit.Index, i, offset uint64) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("lookup of %d: %v", i, rec)
+		}
+	}()
+	if got := idx.Lookup2(i); got != offset {
+		return fmt.Errorf("record %d: offset %d, expected %d", i, got, offset)
+	}
+	return nil
+}
+
+// lookupKey - recsplit.IndexReader.Lookup but returns error instead of panic on broken .idx
+func lookupKey(r *recsplit.IndexReader, key []byte) (id uint64, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("lookup of %x: %v", key, rec)
+		}
+	}()
+	return r.Lookup(key), nil
+}
+
+func checkIdxKeyCount(idx *recsplit.Index, d *compress.Decompressor) error {
+	if idx == nil {
+		return fmt.Errorf("not found")
+	}
+	if idx.KeyCount() != uint64(d.Count()) {
+		return fmt.Errorf("has %d keys, expected %d", idx.KeyCount(), d.Count())
+	}
+	return nil
+}
+
+func (v *verifier) verifyHeaders(ctx context.Context, sn *HeaderSegment) (roots []blockRoots, err error) {
+	badSeg := func(err error) { v.report(sn.From, sn.To, snap.Headers, "", err) }
+	badIdx := func(err error) { v.report(sn.From, sn.To, snap.Headers, snap.Headers.String(), err) }
+
+	idxOk := true
+	if err := checkIdxKeyCount(sn.idxHeaderHash, sn.seg); err != nil {
+		badIdx(err)
+		idxOk = false
+	}
+	var idxReader *recsplit.IndexReader
+	if idxOk {
+		idxReader = recsplit.NewIndexReader(sn.idxHeaderHash)
+	}
+
+	roots = make([]blockRoots, 0, sn.To-sn.From)
+	r := bytes.NewReader(nil)
+	segOk := true
+	if err := sn.seg.WithReadAhead(func() error {
+		g := sn.seg.MakeGetter()
+		var i, offset, nextPos uint64
+		word := make([]byte, 0, 4096)
+		for g.HasNext() {
+			word, nextPos = g.Next(word[:0])
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			if len(word) < 2 {
+				badSeg(fmt.Errorf("empty header at %d", sn.From+i))
+				segOk = false
+				return nil
+			}
+			header := new(types.Header)
+			r.Reset(word[1:])
+			if err := rlp.Decode(r, header); err != nil {
+				badSeg(fmt.Errorf("decode header at %d: %w", sn.From+i, err))
+				segOk = false
+				return nil
+			}
+			hash := header.Hash()
+			if header.Number.Uint64() != sn.From+i {
+				badSeg(fmt.Errorf("header %d has number %d", sn.From+i, header.Number.Uint64()))
+				segOk = false
+				return nil
+			}
+			if word[0] != hash[0] {
+				badSeg(fmt.Errorf("header %d: wrong first byte of hash", header.Number.Uint64()))
+				segOk = false
+				return nil
+			}
+			if v.hasPrev && (v.prevNum+1 != header.Number.Uint64() || v.prevHash != header.ParentHash) {
+				badSeg(fmt.Errorf("header %d is not linked to parent %d %x", header.Number.Uint64(), v.prevNum, v.prevHash))
+				segOk = false
+				return nil
+			}
+			v.prevHash, v.prevNum, v.hasPrev = hash, header.Number.Uint64(), true
+
+			if idxOk {
+				if err := verifyIdx(sn.idxHeaderHash, i, offset); err != nil {
+					badIdx(err)
+					idxOk = false
+				} else if id, err := lookupKey(idxReader, hash[:]); err != nil || id != i {
+					badIdx(fmt.Errorf("hash %x resolves to %d, expected %d, err: %v", hash, id, i, err))
+					idxOk = false
+				}
+			}
+
+			roots = append(roots, blockRoots{txHash: header.TxHash, uncleHash: header.UncleHash, withdrawalsHash: header.WithdrawalsHash})
+			i++
+			offset = nextPos
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if !segOk {
+		v.hasPrev = false
+		return nil, nil
+	}
+	if uint64(len(roots)) != sn.To-sn.From {
+		badSeg(fmt.Errorf("has %d headers, expected %d", len(roots), sn.To-sn.From))
+		v.hasPrev = false
+		return nil, nil
+	}
+	return roots, nil
+}
+
+func (v *verifier) verifyBodies(ctx context.Context, bSn *BodySegment, tSn *TxnSegment, roots []blockRoots) error {
+	badBodies := func(err error) { v.report(bSn.From, bSn.To, snap.Bodies, "", err) }
+	badBodiesIdx := func(err error) { v.report(bSn.From, bSn.To, snap.Bodies, snap.Bodies.String(), err) }
+	badTxs := func(err error) { v.report(tSn.From, tSn.To, snap.Transactions, "", err) }
+	badTxsIdx := func(err error) { v.report(tSn.From, tSn.To, snap.Transactions, snap.Transactions.String(), err) }
+	badTxs2BlockIdx := func(err error) { v.report(tSn.From, tSn.To, snap.Transactions, snap.Transactions2Block.String(), err) }
+
+	bodiesIdxOk := true
+	if err := checkIdxKeyCount(bSn.idxBodyNumber, bSn.seg); err != nil {
+		badBodiesIdx(err)
+		bodiesIdxOk = false
+	}
+	txsIdxOk, txs2BlockIdxOk := true, true
+	if err := checkIdxKeyCount(tSn.IdxTxnHash, tSn.Seg); err != nil {
+		badTxsIdx(err)
+		txsIdxOk = false
+	}
+	if err := checkIdxKeyCount(tSn.IdxTxnHash2BlockNum, tSn.Seg); err != nil {
+		badTxs2BlockIdx(err)
+		txs2BlockIdxOk = false
+	}
+	var txsIdxReader, txs2BlockIdxReader *recsplit.IndexReader
+	if txsIdxOk {
+		txsIdxReader = recsplit.NewIndexReader(tSn.IdxTxnHash)
+	}
+	if txs2BlockIdxOk {
+		txs2BlockIdxReader = recsplit.NewIndexReader(tSn.IdxTxnHash2BlockNum)
+	}
+
+	return bSn.seg.WithReadAhead(func() error {
+		return tSn.Seg.WithReadAhead(func() error {
+			bodyGetter, txGetter := bSn.seg.MakeGetter(), tSn.Seg.MakeGetter()
+			var i, bodyOffset, nextBodyPos uint64
+			var txOrdinal, txOffset, nextTxPos uint64
+			var firstTxnID uint64
+			bodyBuf, txBuf := make([]byte, 0, 4096), make([]byte, 0, 4096)
+			txReader := bytes.NewReader(nil)
+			stream := rlp.NewStream(txReader, 0)
+			// key of system tx in .idx is pad32(txnID), but TransactionsIdx re-uses buffer of previous tx hash
+			var idHash common.Hash
+			for bodyGetter.HasNext() {
+				bodyBuf, nextBodyPos = bodyGetter.Next(bodyBuf[:0])
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+				blockNum := bSn.From + i
+				if i >= uint64(len(roots)) {
+					badBodies(fmt.Errorf("has more bodies than headers: %d", len(roots)))
+					v.hasNextTxn = false
+					return nil
+				}
+				body := &types.BodyForStorage{}
+				if err := rlp.DecodeBytes(bodyBuf, body); err != nil {
+					badBodies(fmt.Errorf("decode body %d: %w", blockNum, err))
+					v.hasNextTxn = false
+					return nil
+				}
+				if i == 0 {
+					firstTxnID = body.BaseTxId
+				}
+				if v.hasNextTxn && body.BaseTxId != v.nextTxnID {
+					badBodies(fmt.Errorf("body %d has BaseTxId %d, expected %d", blockNum, body.BaseTxId, v.nextTxnID))
+					v.hasNextTxn = false
+					return nil
+				}
+				v.nextTxnID, v.hasNextTxn = body.BaseTxId+uint64(body.TxAmount), true
+
+				if bodiesIdxOk {
+					if err := verifyIdx(bSn.idxBodyNumber, i, bodyOffset); err != nil {
+						badBodiesIdx(err)
+						bodiesIdxOk = false
+					}
+				}
+
+				root := roots[i]
+				if h := types.CalcUncleHash(body.Uncles); h != root.uncleHash {
+					badBodies(fmt.Errorf("body %d: uncles hash %x, header has %x", blockNum, h, root.uncleHash))
+					v.hasNextTxn = false
+					return nil
+				}
+				if root.withdrawalsHash != nil || body.Withdrawals != nil {
+					var h common.Hash
+					if body.Withdrawals != nil {
+						h = types.DeriveSha(types.Withdrawals(body.Withdrawals))
+					}
+					if root.withdrawalsHash == nil || body.Withdrawals == nil || h != *root.withdrawalsHash {
+						badBodies(fmt.Errorf("body %d: withdrawals don't match header", blockNum))
+						v.hasNextTxn = false
+						return nil
+					}
+				}
+
+				// first and last txs of each block are system txs (empty for non-bor chains)
+				txs := make(types.Transactions, 0, body.TxAmount)
+				for j := uint32(0); j < body.TxAmount; j++ {
+					if !txGetter.HasNext() {
+						badTxs(fmt.Errorf("not enough txs for block %d", blockNum))
+						return nil
+					}
+					txBuf, nextTxPos = txGetter.Next(txBuf[:0])
+					var txHash common.Hash
+					isSystemTx := len(txBuf) == 0
+					if isSystemTx {
+						binary.BigEndian.PutUint64(idHash[:], firstTxnID+txOrdinal)
+						txHash = idHash
+					} else {
+						if len(txBuf) < 1+20 {
+							badTxs(fmt.Errorf("too short record for tx %d of block %d", j, blockNum))
+							return nil
+						}
+						txReader.Reset(txBuf[1+20:])
+						stream.Reset(txReader, 0)
+						txn, err := types.DecodeTransaction(stream)
+						if err != nil {
+							badTxs(fmt.Errorf("decode tx %d of block %d: %w", j, blockNum, err))
+							return nil
+						}
+						txHash = txn.Hash()
+						idHash = txHash
+						if txBuf[0] != txHash[0] {
+							badTxs(fmt.Errorf("tx %d of block %d: wrong first byte of hash", j, blockNum))
+							return nil
+						}
+						if j != 0 && j != body.TxAmount-1 {
+							txs = append(txs, txn)
+						}
+					}
+
+					if txsIdxOk {
+						if err := verifyIdx(tSn.IdxTxnHash, txOrdinal, txOffset); err != nil {
+							badTxsIdx(err)
+							txsIdxOk = false
+						} else if id, err := lookupKey(txsIdxReader, txHash[:]); err != nil || id != txOrdinal {
+							badTxsIdx(fmt.Errorf("tx %x resolves to %d, expected %d, err: %v", txHash, id, txOrdinal, err))
+							txsIdxOk = false
+						}
+					}
+					if txs2BlockIdxOk {
+						if n, err := lookupKey(txs2BlockIdxReader, txHash[:]); err != nil || n != blockNum {
+							badTxs2BlockIdx(fmt.Errorf("tx %x resolves to block %d, expected %d, err: %v", txHash, n, blockNum, err))
+							txs2BlockIdxOk = false
+						}
+					}
+					txOrdinal++
+					txOffset = nextTxPos
+				}
+				if h := types.DeriveSha(txs); h != root.txHash {
+					badTxs(fmt.Errorf("block %d: txs root %x, header has %x", blockNum, h, root.txHash))
+					return nil
+				}
+
+				i++
+				bodyOffset = nextBodyPos
+			}
+			if i != uint64(len(roots)) {
+				badBodies(fmt.Errorf("has %d bodies, expected %d", i, len(roots)))
+				v.hasNextTxn = false
+				return nil
+			}
+			if txGetter.HasNext() {
+				badTxs(fmt.Errorf("has more txs than bodies refer to: %d", txOrdinal))
+			}
+			return nil
+		})
+	})
+}
+
+// RepairIndices - removes and builds again .idx files with problems. Broken .seg files can't be repaired -
+// they must be removed and downloaded again.
+// Snapshots must be closed - .idx files are memory-mapped. Problems must be sorted by file name (as
+// VerifySnapshots returns them) - then bodies .idx is rebuilt before transactions .idx which depends on it.
+func RepairIndices(ctx context.Context, problems []SnapshotProblem, chainID uint256.Int, dir, tmpDir string, lvl log.Lvl) error {
+	txsRebuilt := map[uint64]struct{}{}
+	for _, p := range problems {
+		if p.Index == "" {
+			continue
+		}
+		if p.Type == snap.Transactions {
+			if _, ok := txsRebuilt[p.From]; ok {
+				continue
+			}
+			txsRebuilt[p.From] = struct{}{}
+		}
+		segmentFile := filepath.Join(dir, snap.SegmentFileName(p.From, p.To, p.Type))
+		if _, err := os.Stat(segmentFile); err != nil {
+			return fmt.Errorf("can't rebuild %s: %w", p.File, err)
+		}
+		_ = os.Remove(filepath.Join(dir, p.File))
+		log.Log(lvl, "[snapshots] Rebuilding", "file", p.File)
+		var err error
+		switch p.Type {
+		case snap.Headers:
+			err = HeadersIdx(ctx, segmentFile, p.From, tmpDir, lvl)
+		case snap.Bodies:
+			err = BodiesIdx(ctx, segmentFile, p.From, tmpDir, lvl)
+		case snap.Transactions:
+			// both indices are built together
+			_ = os.Remove(filepath.Join(dir, snap.IdxFileName(p.From, p.To, snap.Transactions.String())))
+			_ = os.Remove(filepath.Join(dir, snap.IdxFileName(p.From, p.To, snap.Transactions2Block.String())))
+			err = TransactionsIdx(ctx, chainID, p.From, p.To, dir, tmpDir, lvl)
+		case snap.Receipts:
+			err = ReceiptsIdx(ctx, segmentFile, p.From, tmpDir, lvl)
+		default:
+			err = fmt.Errorf("unknown snapshot type: %s", p.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("rebuild %s: %w", p.File, err)
+		}
+	}
+	return nil
+}
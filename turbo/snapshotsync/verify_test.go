@@ -0,0 +1,117 @@
+package snapshotsync
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/compress"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snap"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestBlocksSegments - headers chain of empty blocks [from, to), brokenAt - header with wrong ParentHash (0 - none)
+// returns hash of last header
+func createTestBlocksSegments(t *testing.T, dir string, from, to uint64, parentHash common.Hash, brokenAt uint64) common.Hash {
+	ctx := context.Background()
+	newCompressor := func(tp snap.Type) *compress.Compressor {
+		c, err := compress.NewCompressor(ctx, "test", filepath.Join(dir, snap.SegmentFileName(from, to, tp)), dir, compress.MinPatternScore, 1, log.LvlDebug)
+		require.NoError(t, err)
+		return c
+	}
+	headers, bodies, txs := newCompressor(snap.Headers), newCompressor(snap.Bodies), newCompressor(snap.Transactions)
+	defer headers.Close()
+	defer bodies.Close()
+	defer txs.Close()
+
+	for i := from; i < to; i++ {
+		h := &types.Header{
+			ParentHash: parentHash,
+			UncleHash:  types.EmptyUncleHash,
+			TxHash:     types.EmptyRootHash,
+			Difficulty: big.NewInt(1),
+			Number:     new(big.Int).SetUint64(i),
+			Time:       i,
+		}
+		if i == brokenAt {
+			h.ParentHash = common.Hash{1}
+		}
+		hash := h.Hash()
+		headerRlp, err := rlp.EncodeToBytes(h)
+		require.NoError(t, err)
+		require.NoError(t, headers.AddWord(append([]byte{hash[0]}, headerRlp...)))
+
+		bodyRlp, err := rlp.EncodeToBytes(&types.BodyForStorage{BaseTxId: i * 2, TxAmount: 2})
+		require.NoError(t, err)
+		require.NoError(t, bodies.AddWord(bodyRlp))
+		require.NoError(t, txs.AddWord(nil))
+		require.NoError(t, txs.AddWord(nil))
+		parentHash = hash
+	}
+	require.NoError(t, headers.Compress())
+	require.NoError(t, bodies.Compress())
+	require.NoError(t, txs.Compress())
+	return parentHash
+}
+
+func TestVerifySnapshots(t *testing.T) {
+	ctx, dir := context.Background(), t.TempDir()
+	lastHash := createTestBlocksSegments(t, dir, 0, 1_000, common.Hash{}, 0)
+	createTestBlocksSegments(t, dir, 1_000, 2_000, lastHash, 0)
+
+	s := NewRoSnapshots(ethconfig.Snapshot{Enabled: true}, dir)
+	defer s.Close()
+	require.NoError(t, s.ReopenSegments())
+	require.NoError(t, BuildIndices(ctx, s, *uint256.NewInt(1), dir, 0, 1, log.LvlDebug))
+	require.NoError(t, s.Reopen())
+
+	problems, err := VerifySnapshots(ctx, s, log.LvlDebug)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	// replace bodies index by index of another segment
+	s.Close()
+	bodiesIdx := filepath.Join(dir, snap.IdxFileName(1_000, 2_000, snap.Bodies.String()))
+	headersIdx := filepath.Join(dir, snap.IdxFileName(1_000, 2_000, snap.Headers.String()))
+	require.NoError(t, os.Remove(bodiesIdx))
+	require.NoError(t, os.Rename(headersIdx, bodiesIdx))
+	require.NoError(t, HeadersIdx(ctx, filepath.Join(dir, snap.SegmentFileName(1_000, 2_000, snap.Headers)), 1_000, dir, log.LvlDebug))
+	require.NoError(t, s.Reopen())
+
+	problems, err = VerifySnapshots(ctx, s, log.LvlDebug)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(problems))
+	require.Equal(t, snap.IdxFileName(1_000, 2_000, snap.Bodies.String()), problems[0].File)
+
+	s.Close()
+	require.NoError(t, RepairIndices(ctx, problems, *uint256.NewInt(1), dir, dir, log.LvlDebug))
+	require.NoError(t, s.Reopen())
+	problems, err = VerifySnapshots(ctx, s, log.LvlDebug)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
+
+func TestVerifySnapshotsBrokenChain(t *testing.T) {
+	ctx, dir := context.Background(), t.TempDir()
+	createTestBlocksSegments(t, dir, 0, 1_000, common.Hash{}, 500)
+
+	s := NewRoSnapshots(ethconfig.Snapshot{Enabled: true}, dir)
+	defer s.Close()
+	require.NoError(t, s.ReopenSegments())
+	require.NoError(t, BuildIndices(ctx, s, *uint256.NewInt(1), dir, 0, 1, log.LvlDebug))
+	require.NoError(t, s.Reopen())
+
+	problems, err := VerifySnapshots(ctx, s, log.LvlDebug)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(problems))
+	require.Equal(t, snap.SegmentFileName(0, 1_000, snap.Headers), problems[0].File)
+	require.Empty(t, problems[0].Index)
+}
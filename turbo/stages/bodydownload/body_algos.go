@@ -45,7 +45,7 @@ func (bd *BodyDownload) UpdateFromDb(db kv.Tx) (headHeight uint64, headHash comm
 	bd.requestedLow = bodyProgress + 1
 	bd.lowWaitUntil = 0
 	bd.requestHigh = bd.requestedLow + (bd.outstandingLimit / 2)
-	bd.requestedMap = make(map[DoubleHash]uint64)
+	bd.requestedMap = make(map[BodyHashes]uint64)
 	bd.delivered.Clear()
 	bd.deliveredCount = 0
 	bd.wastedCount = 0
@@ -146,20 +146,22 @@ func (bd *BodyDownload) RequestMoreBodies(tx kv.RwTx, blockReader interfaces.Ful
 				request = false
 			} else {
 				bd.deliveriesH[blockNum-bd.requestedLow] = header
-				if header.UncleHash != types.EmptyUncleHash || header.TxHash != types.EmptyRootHash {
+				if header.UncleHash != types.EmptyUncleHash || header.TxHash != types.EmptyRootHash ||
+					(header.WithdrawalsHash != nil && *header.WithdrawalsHash != types.EmptyRootHash) {
 					// Perhaps we already have this block
 					block = rawdb.ReadBlock(tx, hash, blockNum)
 					if block == nil {
-						var doubleHash DoubleHash
-						copy(doubleHash[:], header.UncleHash.Bytes())
-						copy(doubleHash[common.HashLength:], header.TxHash.Bytes())
-						bd.requestedMap[doubleHash] = blockNum
+						bd.requestedMap[makeBodyHashes(header.UncleHash, header.TxHash, header.WithdrawalsHash)] = blockNum
 					} else {
 						bd.deliveriesB[blockNum-bd.requestedLow] = block.RawBody()
 						request = false
 					}
 				} else {
-					bd.deliveriesB[blockNum-bd.requestedLow] = &types.RawBody{}
+					body := &types.RawBody{}
+					if header.WithdrawalsHash != nil {
+						body.Withdrawals = []*types.Withdrawal{}
+					}
+					bd.deliveriesB[blockNum-bd.requestedLow] = body
 					request = false
 				}
 			}
@@ -168,7 +170,7 @@ func (bd *BodyDownload) RequestMoreBodies(tx kv.RwTx, blockReader interfaces.Ful
 			blockNums = append(blockNums, blockNum)
 			hashes = append(hashes, hash)
 		} else {
-			// Both uncleHash and txHash are empty (or block is prefetched), no need to request
+			// UncleHash, TxHash and WithdrawalsHash are empty (or block is prefetched), no need to request
 			bd.delivered.Add(blockNum)
 		}
 	}
@@ -195,8 +197,8 @@ func (bd *BodyDownload) RequestSent(bodyReq *BodyRequest, timeWithTimeout uint64
 }
 
 // DeliverBodies takes the block body received from a peer and adds it to the various data structures
-func (bd *BodyDownload) DeliverBodies(txs [][][]byte, uncles [][]*types.Header, lenOfP2PMsg uint64, peerID [64]byte) {
-	bd.deliveryCh <- Delivery{txs: txs, uncles: uncles, lenOfP2PMessage: lenOfP2PMsg, peerID: peerID}
+func (bd *BodyDownload) DeliverBodies(txs [][][]byte, uncles [][]*types.Header, withdrawals [][]*types.Withdrawal, lenOfP2PMsg uint64, peerID [64]byte) {
+	bd.deliveryCh <- Delivery{txs: txs, uncles: uncles, withdrawals: withdrawals, lenOfP2PMessage: lenOfP2PMsg, peerID: peerID}
 
 	select {
 	case bd.DeliveryNotify <- struct{}{}:
@@ -241,19 +243,26 @@ Loop:
 		}
 
 		reqMap := make(map[uint64]*BodyRequest)
-		txs, uncles, lenOfP2PMessage, _ := delivery.txs, delivery.uncles, delivery.lenOfP2PMessage, delivery.peerID
+		txs, uncles, withdrawals, lenOfP2PMessage, _ := delivery.txs, delivery.uncles, delivery.withdrawals, delivery.lenOfP2PMessage, delivery.peerID
 		var delivered, undelivered int
 
 		for i := range txs {
 			uncleHash := types.CalcUncleHash(uncles[i])
 			txHash := types.DeriveSha(RawTransactions(txs[i]))
-			var doubleHash DoubleHash
-			copy(doubleHash[:], uncleHash.Bytes())
-			copy(doubleHash[common.HashLength:], txHash.Bytes())
+			// Withdrawals are verified against the header by the match: a body without withdrawals
+			// only matches a header without WithdrawalsHash and vice versa
+			var blockWithdrawals []*types.Withdrawal
+			var withdrawalsHash *common.Hash
+			if i < len(withdrawals) && withdrawals[i] != nil {
+				blockWithdrawals = withdrawals[i]
+				h := types.DeriveSha(types.Withdrawals(blockWithdrawals))
+				withdrawalsHash = &h
+			}
+			bodyHashes := makeBodyHashes(uncleHash, txHash, withdrawalsHash)
 
-			// Block numbers are added to the bd.delivered bitmap here, only for blocks for which the body has been received, and their double hashes are present in the bd.requesredMap
+			// Block numbers are added to the bd.delivered bitmap here, only for blocks for which the body has been received, and their body hashes are present in the bd.requesredMap
 			// Also, block numbers can be added to bd.delivered for empty blocks, above
-			blockNum, ok := bd.requestedMap[doubleHash]
+			blockNum, ok := bd.requestedMap[bodyHashes]
 			if !ok {
 				undelivered++
 				continue
@@ -264,9 +273,9 @@ Loop:
 					reqMap[req.BlockNums[0]] = req
 				}
 			}
-			delete(bd.requestedMap, doubleHash) // Delivered, cleaning up
+			delete(bd.requestedMap, bodyHashes) // Delivered, cleaning up
 
-			bd.deliveriesB[blockNum-bd.requestedLow] = &types.RawBody{Transactions: txs[i], Uncles: uncles[i]}
+			bd.deliveriesB[blockNum-bd.requestedLow] = &types.RawBody{Transactions: txs[i], Uncles: uncles[i], Withdrawals: blockWithdrawals}
 			bd.delivered.Add(blockNum)
 			delivered++
 		}
@@ -285,6 +294,17 @@ Loop:
 	return nil
 }
 
+// makeBodyHashes builds the key of the requested body, withdrawalsHash is nil before Shanghai
+func makeBodyHashes(uncleHash, txHash common.Hash, withdrawalsHash *common.Hash) BodyHashes {
+	var bodyHashes BodyHashes
+	copy(bodyHashes[:], uncleHash.Bytes())
+	copy(bodyHashes[common.HashLength:], txHash.Bytes())
+	if withdrawalsHash != nil {
+		copy(bodyHashes[2*common.HashLength:], withdrawalsHash.Bytes())
+	}
+	return bodyHashes
+}
+
 func (bd *BodyDownload) DeliverySize(delivered float64, wasted float64) {
 	bd.deliveredCount += delivered
 	bd.wastedCount += wasted
@@ -311,6 +331,24 @@ func (bd *BodyDownload) VerifyUncles(header *types.Header, uncles []*types.Heade
 	return headerdownload.NoPenalty, nil
 }
 
+// VerifyWithdrawals checks the withdrawals of a body against the WithdrawalsHash of its header.
+// Withdrawals must be nil before Shanghai (no WithdrawalsHash) and not nil after.
+func VerifyWithdrawals(header *types.Header, withdrawals []*types.Withdrawal) error {
+	if header.WithdrawalsHash == nil {
+		if withdrawals != nil {
+			return fmt.Errorf("unexpected withdrawals in block without withdrawals root")
+		}
+		return nil
+	}
+	if withdrawals == nil {
+		return fmt.Errorf("missing withdrawals, want root %x", *header.WithdrawalsHash)
+	}
+	if hash := types.DeriveSha(types.Withdrawals(withdrawals)); hash != *header.WithdrawalsHash {
+		return fmt.Errorf("withdrawals root hash mismatch: have %x, want %x", hash, *header.WithdrawalsHash)
+	}
+	return nil
+}
+
 func (bd *BodyDownload) GetDeliveries() ([]*types.Header, []*types.RawBody, error) {
 	err := bd.doDeliverBodies() // TODO: join this 2 funcs and simplify
 	if err != nil {
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core/types"
 )
 
 func TestCreateBodyDownload(t *testing.T) {
@@ -14,3 +16,29 @@ func TestCreateBodyDownload(t *testing.T) {
 		t.Fatalf("update from db: %v", err)
 	}
 }
+
+func TestVerifyWithdrawals(t *testing.T) {
+	withdrawals := []*types.Withdrawal{{Index: 1, Validator: 2, Address: common.HexToAddress("0x3"), Amount: 4}}
+	root := types.DeriveSha(types.Withdrawals(withdrawals))
+	emptyRoot := types.EmptyRootHash
+
+	preShanghai := &types.Header{}
+	if err := VerifyWithdrawals(preShanghai, nil); err != nil {
+		t.Fatalf("pre-Shanghai body: %v", err)
+	}
+	if err := VerifyWithdrawals(preShanghai, []*types.Withdrawal{}); err == nil {
+		t.Fatal("withdrawals accepted before Shanghai")
+	}
+	if err := VerifyWithdrawals(&types.Header{WithdrawalsHash: &emptyRoot}, []*types.Withdrawal{}); err != nil {
+		t.Fatalf("empty withdrawals: %v", err)
+	}
+	if err := VerifyWithdrawals(&types.Header{WithdrawalsHash: &root}, withdrawals); err != nil {
+		t.Fatalf("withdrawals: %v", err)
+	}
+	if err := VerifyWithdrawals(&types.Header{WithdrawalsHash: &root}, nil); err == nil {
+		t.Fatal("missing withdrawals accepted")
+	}
+	if err := VerifyWithdrawals(&types.Header{WithdrawalsHash: &emptyRoot}, withdrawals); err == nil {
+		t.Fatal("mismatching withdrawals accepted")
+	}
+}
@@ -281,7 +281,7 @@ func MockWithEverything(t *testing.T, gspec *core.Genesis, key *ecdsa.PrivateKey
 
 	isBor := mock.ChainConfig.Bor != nil
 
-	mock.Sync = stagedsync.New(
+	stagesList, unwindOrder, pruneOrder := stagedsync.WithCustomStages(mock.Ctx, mock.DB, mock.tmpdir,
 		stagedsync.DefaultStages(mock.Ctx, prune,
 			stagedsync.StageHeadersCfg(mock.DB, mock.sentriesClient.Hd, mock.sentriesClient.Bd, *mock.ChainConfig, sendHeaderRequest, propagateNewBlockHashes, penalize, cfg.BatchSize, false, allSnapshots, snapshotsDownloader, blockReader, mock.tmpdir, mock.Notifications.Events),
 			stagedsync.StageCumulativeIndexCfg(mock.DB),
@@ -317,13 +317,16 @@ func MockWithEverything(t *testing.T, gspec *core.Genesis, key *ecdsa.PrivateKey
 			stagedsync.StageHashStateCfg(mock.DB, mock.tmpdir),
 			stagedsync.StageTrieCfg(mock.DB, true, true, mock.tmpdir, blockReader),
 			stagedsync.StageHistoryCfg(mock.DB, prune, mock.tmpdir),
-			stagedsync.StageLogIndexCfg(mock.DB, prune, mock.tmpdir),
+			stagedsync.StageLogIndexCfg(mock.DB, prune, mock.tmpdir, allSnapshots, blockReader),
 			stagedsync.StageCallTracesCfg(mock.DB, prune, 0, mock.tmpdir),
+			stagedsync.StageTraceIndexCfg(mock.DB, prune, mock.ChainConfig, mock.Engine, blockReader),
+			stagedsync.StageAddressAppearancesCfg(mock.DB, prune, mock.ChainConfig, mock.Engine, blockReader),
 			stagedsync.StageTxLookupCfg(mock.DB, prune, mock.tmpdir, allSnapshots, isBor),
 			stagedsync.StageFinishCfg(mock.DB, mock.tmpdir, mock.Log, nil), true),
 		stagedsync.DefaultUnwindOrder,
 		stagedsync.DefaultPruneOrder,
 	)
+	mock.Sync = stagedsync.New(stagesList, unwindOrder, pruneOrder)
 
 	mock.sentriesClient.Hd.StartPoSDownloader(mock.Ctx, sendHeaderRequest, penalize)
 
@@ -445,7 +448,7 @@ func (ms *MockSentry) InsertChain(chain *core.ChainPack) error {
 	// Send all the bodies
 	packet := make(eth.BlockBodiesPacket, chain.Length)
 	for i, block := range chain.Blocks {
-		packet[i] = (*eth.BlockBody)(block.Body())
+		packet[i] = &eth.BlockBody{Transactions: block.Transactions(), Uncles: block.Uncles()}
 	}
 	b, err = rlp.EncodeToBytes(&eth.BlockBodiesPacket66{
 		RequestId:         1,
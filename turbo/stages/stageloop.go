@@ -276,7 +276,7 @@ func NewStagedSync(
 	// Hence we run it in the test mode.
 	runInTestMode := cfg.ImportMode
 	isBor := controlServer.ChainConfig.Bor != nil
-	return stagedsync.New(
+	stagesList, unwindOrder, pruneOrder := stagedsync.WithCustomStages(ctx, db, tmpdir,
 		stagedsync.DefaultStages(ctx, cfg.Prune,
 			stagedsync.StageHeadersCfg(db, controlServer.Hd, controlServer.Bd, *controlServer.ChainConfig, controlServer.SendHeaderRequest, controlServer.PropagateNewBlockHashes, controlServer.Penalize, cfg.BatchSize, p2pCfg.NoDiscovery, snapshots, snapshotDownloader, blockReader, tmpdir, notifications.Events),
 			stagedsync.StageCumulativeIndexCfg(db),
@@ -312,11 +312,14 @@ func NewStagedSync(
 			stagedsync.StageHashStateCfg(db, tmpdir),
 			stagedsync.StageTrieCfg(db, true, true, tmpdir, blockReader),
 			stagedsync.StageHistoryCfg(db, cfg.Prune, tmpdir),
-			stagedsync.StageLogIndexCfg(db, cfg.Prune, tmpdir),
+			stagedsync.StageLogIndexCfg(db, cfg.Prune, tmpdir, snapshots, blockReader),
 			stagedsync.StageCallTracesCfg(db, cfg.Prune, 0, tmpdir),
+			stagedsync.StageTraceIndexCfg(db, cfg.Prune, controlServer.ChainConfig, controlServer.Engine, blockReader),
+			stagedsync.StageAddressAppearancesCfg(db, cfg.Prune, controlServer.ChainConfig, controlServer.Engine, blockReader),
 			stagedsync.StageTxLookupCfg(db, cfg.Prune, tmpdir, snapshots, isBor),
 			stagedsync.StageFinishCfg(db, tmpdir, logger, headCh), runInTestMode),
 		stagedsync.DefaultUnwindOrder,
 		stagedsync.DefaultPruneOrder,
-	), nil
+	)
+	return stagedsync.New(stagesList, unwindOrder, pruneOrder), nil
 }
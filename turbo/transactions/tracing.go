@@ -90,8 +90,9 @@ func TraceTx(
 ) error {
 	// Assemble the structured logger or the JavaScript tracer
 	var (
-		tracer vm.Tracer
-		err    error
+		tracer       vm.Tracer
+		resultTracer tracers.Tracer
+		err          error
 	)
 	var streaming bool
 	switch {
@@ -104,18 +105,19 @@ func TraceTx(
 				return err
 			}
 		}
-		// Construct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer, &tracers.Context{
+		// Construct the native or JavaScript tracer to execute with
+		if resultTracer, err = tracers.New(*config.Tracer, &tracers.Context{
 			TxHash: txCtx.TxHash,
 		}); err != nil {
 			stream.WriteNil()
 			return err
 		}
+		tracer = resultTracer
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			resultTracer.Stop(errors.New("execution timeout"))
 		}()
 		defer cancel()
 		streaming = false
@@ -166,7 +168,7 @@ func TraceTx(
 		stream.WriteString(returnVal)
 		stream.WriteObjectEnd()
 	} else {
-		if r, err1 := tracer.(*tracers.Tracer).GetResult(); err1 == nil {
+		if r, err1 := resultTracer.GetResult(); err1 == nil {
 			stream.Write(r)
 		} else {
 			return err1
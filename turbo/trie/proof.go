@@ -95,3 +95,60 @@ func (t *Trie) Prove(key []byte, fromLevel int, storage bool) ([][]byte, error)
 	}
 	return proof, nil
 }
+
+// NodeAt returns the encoded node at the path (in HEX encoding, without terminator), as requested by snap/1 GetTrieNodes.
+// Paths of storage trie nodes start with 64 nibbles of the account key, `storage` must be set for them.
+// Returns false if the trie doesn't contain such node, or it's represented by its hash.
+func (t *Trie) NodeAt(hex []byte, storage bool) ([]byte, bool, error) {
+	hasher := newHasher(false)
+	defer returnHasherToPool(hasher)
+	tn := t.root
+	for {
+		switch n := tn.(type) {
+		case nil, hashNode, valueNode, codeNode:
+			return nil, false, nil
+		case *accountNode:
+			if !storage {
+				return nil, false, nil
+			}
+			storage = false
+			tn = n.storage
+			continue
+		}
+		if len(hex) == 0 {
+			rlp, err := hasher.hashChildren(tn, 0)
+			if err != nil {
+				return nil, false, err
+			}
+			return common.CopyBytes(rlp), true, nil
+		}
+		switch n := tn.(type) {
+		case *shortNode:
+			nKey := n.Key
+			if nKey[len(nKey)-1] == 16 {
+				nKey = nKey[:len(nKey)-1]
+			}
+			if !bytes.HasPrefix(hex, nKey) {
+				return nil, false, nil
+			}
+			hex = hex[len(nKey):]
+			tn = n.Val
+		case *duoNode:
+			i1, i2 := n.childrenIdx()
+			switch hex[0] {
+			case i1:
+				tn = n.child1
+			case i2:
+				tn = n.child2
+			default:
+				return nil, false, nil
+			}
+			hex = hex[1:]
+		case *fullNode:
+			tn = n.Children[hex[0]]
+			hex = hex[1:]
+		default:
+			return nil, false, nil
+		}
+	}
+}
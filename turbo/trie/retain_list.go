@@ -84,6 +84,11 @@ func (rl *RetainList) AddHex(hex []byte) {
 	rl.hexes = append(rl.hexes, hex)
 }
 
+func (rl *RetainList) AddHexWithMarker(hex []byte, marker bool) {
+	rl.AddHex(hex)
+	rl.markers = append(rl.markers, marker)
+}
+
 // AddCodeTouch adds a new code touch into the resolve set
 func (rl *RetainList) AddCodeTouch(codeHash common.Hash) {
 	rl.codeTouches[codeHash] = struct{}{}
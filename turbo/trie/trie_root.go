@@ -122,6 +122,10 @@ type RootHashAggregator struct {
 	a              accounts.Account
 	leafData       GenStructStepLeafData
 	accData        GenStructStepAccountData
+
+	proofRetainer RetainDecider // if set - nodes on the path to its keys are constructed instead of being only hashed
+	proofRoot     node          // root of the trie constructed for proofRetainer by the last CutoffStreamItem
+	retainBuf     []byte
 }
 
 type StreamReceiver interface {
@@ -173,6 +177,21 @@ func (l *FlatDBTrieLoader) SetStreamReceiver(receiver StreamReceiver) {
 	l.receiver = receiver
 }
 
+// SetProofRetainer - makes next CalcTrieRoot to construct trie nodes on the path to keys of `rd`
+// (instead of only hashing them), so Merkle proofs for these keys can be produced by ProofTrie().Prove
+// `rd` must also be part of RetainDecider passed to Reset, otherwise intermediate hashes will be used for such keys
+func (l *FlatDBTrieLoader) SetProofRetainer(rd RetainDecider) {
+	l.defaultReceiver.proofRetainer = rd
+}
+
+// ProofTrie - returns trie constructed by last CalcTrieRoot for keys of ProofRetainer.
+// Sub-tries which are not on the path to such keys are represented by their hashes
+func (l *FlatDBTrieLoader) ProofTrie() *Trie {
+	t := New(common.Hash{})
+	t.root = l.defaultReceiver.proofRoot
+	return t
+}
+
 // CalcTrieRoot algo:
 //	for iterateIHOfAccounts {
 //		if canSkipState
@@ -327,6 +346,23 @@ func (r *RootHashAggregator) RetainNothing(_ []byte) bool {
 	return false
 }
 
+func (r *RootHashAggregator) retainAccount(prefix []byte) bool {
+	if r.proofRetainer == nil {
+		return false
+	}
+	return r.proofRetainer.Retain(prefix)
+}
+
+// retainStorage - storage prefixes are relative to account, but keys of proofRetainer contain accWithInc
+func (r *RootHashAggregator) retainStorage(prefix []byte) bool {
+	if r.proofRetainer == nil {
+		return false
+	}
+	hexutil.DecompressNibbles(r.currAccK, &r.retainBuf)
+	r.retainBuf = append(r.retainBuf, prefix...)
+	return r.proofRetainer.Retain(r.retainBuf)
+}
+
 func (r *RootHashAggregator) Reset(hc HashCollector2, shc StorageHashCollector2, trace bool) {
 	r.hc = hc
 	r.shc = shc
@@ -344,6 +380,7 @@ func (r *RootHashAggregator) Reset(hc HashCollector2, shc StorageHashCollector2,
 	r.valueStorage = nil
 	r.wasIHStorage = false
 	r.root = common.Hash{}
+	r.proofRoot = nil
 	r.trace = trace
 	r.hb.trace = trace
 }
@@ -493,6 +530,9 @@ func (r *RootHashAggregator) Receive(itemType StreamItem,
 		}
 		if r.hb.hasRoot() {
 			r.root = r.hb.rootHash()
+			if r.proofRetainer != nil {
+				r.proofRoot = r.hb.root()
+			}
 		} else {
 			r.root = EmptyRoot
 		}
@@ -563,7 +603,7 @@ func (r *RootHashAggregator) genStructStorage() error {
 		r.leafData.Value = rlphacks.RlpSerializableBytes(r.valueStorage)
 		data = &r.leafData
 	}
-	r.groupsStorage, r.hasTreeStorage, r.hasHashStorage, err = GenStructStep(r.RetainNothing, r.currStorage.Bytes(), r.succStorage.Bytes(), r.hb, func(keyHex []byte, hasState, hasTree, hasHash uint16, hashes, rootHash []byte) error {
+	r.groupsStorage, r.hasTreeStorage, r.hasHashStorage, err = GenStructStep(r.retainStorage, r.currStorage.Bytes(), r.succStorage.Bytes(), r.hb, func(keyHex []byte, hasState, hasTree, hasHash uint16, hashes, rootHash []byte) error {
 		if r.shc == nil {
 			return nil
 		}
@@ -631,7 +671,7 @@ func (r *RootHashAggregator) genStructAccount() error {
 	r.currStorage.Reset()
 	r.succStorage.Reset()
 	var err error
-	if r.groups, r.hasTree, r.hasHash, err = GenStructStep(r.RetainNothing, r.curr.Bytes(), r.succ.Bytes(), r.hb, func(keyHex []byte, hasState, hasTree, hasHash uint16, hashes, rootHash []byte) error {
+	if r.groups, r.hasTree, r.hasHash, err = GenStructStep(r.retainAccount, r.curr.Bytes(), r.succ.Bytes(), r.hb, func(keyHex []byte, hasState, hasTree, hasHash uint16, hashes, rootHash []byte) error {
 		if r.hc == nil {
 			return nil
 		}